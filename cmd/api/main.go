@@ -2,16 +2,15 @@ package main
 
 import (
 	"context"
-	"eduBase/internal/models"
-	"fmt"
 	"github.com/go-chi/cors"
-	"go.uber.org/zap"
-	"golang.org/x/crypto/bcrypt"
 	"log"
 	"net/http"
+	"time"
 
 	"eduBase/config"
+	"eduBase/internal/database"
 	"eduBase/internal/handlers"
+	"eduBase/internal/helpers"
 	"eduBase/internal/logger"
 	"eduBase/internal/middleware"
 	"eduBase/internal/repository"
@@ -19,15 +18,15 @@ import (
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/jwtauth/v5"
-	"github.com/jackc/pgx/v5"
 
 	_ "eduBase/docs"
 	httpSwagger "github.com/swaggo/http-swagger"
+	"go.uber.org/zap"
 )
 
 // @title eduBase API
 // @version 1.0
-// @description База школ с ролями ROO и School.
+// @description База школ района: РОО, инспекторы и сотрудники школ с разграничением прав.
 // @BasePath /
 // @securityDefinitions.apikey BearerAuth
 // @in header
@@ -35,41 +34,96 @@ import (
 func main() {
 	cfg := config.Load()
 	logg := logger.New(cfg.AppEnv)
+	helpers.SetTrustedProxies(cfg.TrustedProxies)
 
-	conn, err := pgx.Connect(context.Background(), cfg.DBURL)
+	pool, err := database.NewPool(context.Background(), cfg)
 	if err != nil {
 		log.Fatal("db connect failed:", err)
 	}
-	defer conn.Close(context.Background())
+	defer pool.Close()
 
 	jwtAuth := jwtauth.New("HS256", []byte(cfg.JWTSecret), nil)
 
 	// === Repositories ===
-	userRepo := repository.NewUserRepository(conn)
-	schoolRepo := repository.NewSchoolRepository(conn)
-	classRepo := repository.NewClassRepository(conn)
-	staffRepo := repository.NewStaffRepository(conn)
-	studentRepo := repository.NewStudentRepository(conn)
-	statsRepo := repository.NewStatsRepository(conn)
+	userRepo := repository.NewUserRepository(pool)
+	schoolRepo := repository.NewSchoolRepository(pool)
+	classRepo := repository.NewClassRepository(pool)
+	staffRepo := repository.NewStaffRepository(pool)
+	studentRepo := repository.NewStudentRepository(pool)
+	statsRepo := repository.NewStatsRepository(pool)
+	sessionRepo := repository.NewSessionRepository(pool)
+	auditRepo := repository.NewAuditRepository(pool)
+	pdAccessRepo := repository.NewPDAccessRepository(pool)
+	trashRepo := repository.NewTrashRepository(pool)
+	academicYearRepo := repository.NewAcademicYearRepository(pool)
+	movementRepo := repository.NewMovementRepository(pool)
+	transferRepo := repository.NewTransferRepository(pool)
+	guardianRepo := repository.NewGuardianRepository(pool)
+	attendanceRepo := repository.NewAttendanceRepository(pool)
+	subjectRepo := repository.NewSubjectRepository(pool)
+	assignmentRepo := repository.NewAssignmentRepository(pool)
+	timetableRepo := repository.NewTimetableRepository(pool)
+	markRepo := repository.NewMarkRepository(pool)
+
+	txManager := repository.NewTxManager(pool)
 
 	// === Services ===
-	authSvc := services.NewAuthService(userRepo, jwtAuth)
-	schoolSvc := services.NewSchoolService(schoolRepo)
-	classSvc := services.NewClassService(classRepo)
-	staffSvc := services.NewStaffService(staffRepo)
-	studentSvc := services.NewStudentService(studentRepo, classRepo, schoolRepo)
-	statsSvc := services.NewStatsService(statsRepo, schoolRepo)
+	authSvc := services.NewAuthService(userRepo, txManager, jwtAuth, cfg.AccessTokenTTL, cfg.RefreshTokenTTL)
+	userSvc := services.NewUserService(userRepo, txManager)
+	schoolSvc := services.NewSchoolService(schoolRepo, txManager)
+	classSvc := services.NewClassService(classRepo, txManager)
+	staffSvc := services.NewStaffService(staffRepo, txManager)
+	studentSvc := services.NewStudentService(studentRepo, classRepo, schoolRepo, movementRepo, txManager)
+	statsSvc := services.NewStatsService(statsRepo, schoolRepo, txManager)
+	auditSvc := services.NewAuditService(auditRepo)
+	pdAccessSvc := services.NewPDAccessService(pdAccessRepo)
+	trashSvc := services.NewTrashService(trashRepo, txManager)
+	academicYearSvc := services.NewAcademicYearService(academicYearRepo, txManager)
+	promotionSvc := services.NewPromotionService(txManager)
+	transferSvc := services.NewTransferService(transferRepo, txManager)
+	guardianSvc := services.NewGuardianService(guardianRepo, txManager)
+	attendanceSvc := services.NewAttendanceService(attendanceRepo, classRepo, txManager)
+	subjectSvc := services.NewSubjectService(subjectRepo, txManager)
+	assignmentSvc := services.NewAssignmentService(assignmentRepo, txManager)
+	timetableSvc := services.NewTimetableService(timetableRepo, txManager)
+	markSvc := services.NewMarkService(markRepo, classRepo, txManager)
 
 	// === Handlers ===
 	authHandler := handlers.NewAuthHandler(authSvc)
-	rooHandler := handlers.NewRooHandler(authSvc, schoolRepo)
-	rooSchoolHandler := handlers.NewRooSchoolHandler(schoolSvc)
-	classHandler := handlers.NewClassHandler(classSvc)
-	staffHandler := handlers.NewStaffHandler(staffSvc)
-	studentHandler := handlers.NewStudentHandler(studentSvc)
+	rooHandler := handlers.NewRooHandler(authSvc)
+	rooSchoolHandler := handlers.NewRooSchoolHandler(schoolSvc, authSvc)
+	userHandler := handlers.NewUserHandler(userSvc)
+	classHandler := handlers.NewClassHandler(classSvc, timetableSvc)
+	staffHandler := handlers.NewStaffHandler(staffSvc, timetableSvc)
+	studentHandler := handlers.NewStudentHandler(studentSvc, guardianSvc, markSvc, pdAccessSvc)
 	statsHandler := handlers.NewStatsHandler(statsSvc)
-
-	CreateDefaultAdmin(context.Background(), userRepo, logg)
+	auditHandler := handlers.NewAuditHandler(auditSvc)
+	pdAccessHandler := handlers.NewPDAccessHandler(pdAccessSvc)
+	trashHandler := handlers.NewTrashHandler(trashSvc, pdAccessSvc)
+	academicYearHandler := handlers.NewAcademicYearHandler(academicYearSvc)
+	promotionHandler := handlers.NewPromotionHandler(promotionSvc)
+	transferHandler := handlers.NewTransferHandler(transferSvc, pdAccessSvc)
+	attendanceHandler := handlers.NewAttendanceHandler(attendanceSvc, pdAccessSvc)
+	subjectHandler := handlers.NewSubjectHandler(subjectSvc)
+	assignmentHandler := handlers.NewAssignmentHandler(assignmentSvc)
+	markHandler := handlers.NewMarkHandler(markSvc, pdAccessSvc)
+
+	if created, err := authSvc.BootstrapAdmin(context.Background(), cfg.AdminEmail, cfg.AdminPassword); err != nil {
+		logg.Warnw("bootstrap_admin_skipped", "err", err)
+	} else if created {
+		logg.Infow("bootstrap_admin_created", "email", cfg.AdminEmail)
+	}
+	if n, err := authSvc.MigratePlaintextPasswords(context.Background()); err != nil {
+		logg.Errorw("password_migration_failed", "err", err)
+	} else if n > 0 {
+		logg.Infow("passwords_hashed", "count", n)
+	}
+	if cfg.TrashRetention > 0 {
+		go runTrashPurge(context.Background(), trashSvc, cfg.TrashRetention, logg)
+	}
+	if cfg.StatsSnapshotPeriod != "off" {
+		go runStatsSnapshots(context.Background(), statsSvc, cfg.StatsSnapshotPeriod, logg)
+	}
 	// === Router ===
 	r := chi.NewRouter()
 
@@ -92,37 +146,35 @@ func main() {
 		authHandler.Routes(r)
 	})
 
-	// ROO-only
+	// Любой аутентифицированный пользователь
 	r.Group(func(r chi.Router) {
-		r.Use(middleware.Authenticator(jwtAuth))
-		r.Use(middleware.RequireRole("roo"))
-		rooHandler.Routes(r)
-		rooSchoolHandler.Routes(r)
+		r.Use(middleware.Authenticator(jwtAuth, sessionRepo))
+		r.Use(middleware.AuditActor)
+		authHandler.ProtectedRoutes(r)
 	})
 
-	// ROO or School (shared)
+	// Доступ к данным: права проверяются на уровне маршрутов (middleware.RequirePermission)
 	r.Group(func(r chi.Router) {
-		r.Use(middleware.Authenticator(jwtAuth))
-		r.Use(middleware.RequireAnyRole("roo", "school"))
+		r.Use(middleware.Authenticator(jwtAuth, sessionRepo))
+		r.Use(middleware.RequirePasswordChanged)
+		r.Use(middleware.AuditActor)
+		rooHandler.Routes(r)
+		rooSchoolHandler.Routes(r)
+		userHandler.Routes(r)
 		classHandler.Routes(r)
-	})
-
-	r.Group(func(r chi.Router) {
-		r.Use(middleware.Authenticator(jwtAuth))
-		r.Use(middleware.RequireAnyRole("roo", "school"))
 		staffHandler.Routes(r)
-	})
-
-	r.Group(func(r chi.Router) {
-		r.Use(middleware.Authenticator(jwtAuth))
-		r.Use(middleware.RequireAnyRole("roo", "school"))
 		studentHandler.Routes(r)
-	})
-	// для аутентифицированных пользователей (roo, school)
-	r.Group(func(r chi.Router) {
-		r.Use(middleware.Authenticator(jwtAuth))
-		r.Use(middleware.RequireAnyRole("roo", "school"))
 		statsHandler.Routes(r)
+		auditHandler.Routes(r)
+		pdAccessHandler.Routes(r)
+		trashHandler.Routes(r)
+		academicYearHandler.Routes(r)
+		promotionHandler.Routes(r)
+		transferHandler.Routes(r)
+		attendanceHandler.Routes(r)
+		subjectHandler.Routes(r)
+		assignmentHandler.Routes(r)
+		markHandler.Routes(r)
 	})
 
 	logg.Infof("📘 Swagger: http://localhost:%s/docs/index.html", cfg.AppPort)
@@ -130,34 +182,51 @@ func main() {
 	log.Fatal(http.ListenAndServe(":"+cfg.AppPort, r))
 }
 
-func CreateDefaultAdmin(ctx context.Context, userRepo *repository.UserRepository, log *zap.SugaredLogger) {
-	adminEmail := "admin"
-	adminPass := "admin"
-	adminRole := "roo"
-
-	user, err := userRepo.FindByEmail(ctx, adminEmail)
-	if err == nil && user != nil {
-		log.Infow("admin_exists", "email", adminEmail)
-		return
-	}
-
-	hash, err := bcrypt.GenerateFromPassword([]byte(adminPass), bcrypt.DefaultCost)
-	if err != nil {
-		log.Errorw("bcrypt_failed", "err", err)
-		return
-	}
-
-	admin := &models.User{
-		Email:    adminEmail,
-		Password: string(hash),
-		Role:     adminRole,
+// runTrashPurge раз в час окончательно удаляет записи, пролежавшие в корзине дольше retention
+func runTrashPurge(ctx context.Context, svc *services.TrashService, retention time.Duration, logg *zap.SugaredLogger) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+	for {
+		purged, err := svc.Purge(ctx, retention)
+		if err != nil {
+			logg.Errorw("trash_purge_failed", "err", err)
+		} else {
+			for entity, n := range purged {
+				if n > 0 {
+					logg.Infow("trash_purged", "entity", entity, "count", n)
+				}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
 	}
+}
 
-	if err := userRepo.Create(ctx, admin); err != nil {
-		log.Errorw("create_admin_failed", "err", err)
-		return
+// runStatsSnapshots раз в час проверяет, есть ли снимок сводной статистики за текущий день (или месяц), и делает его
+func runStatsSnapshots(ctx context.Context, svc *services.StatsService, period string, logg *zap.SugaredLogger) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+	for {
+		now := time.Now()
+		due, err := svc.SnapshotDue(ctx, period, now)
+		if err != nil {
+			logg.Errorw("stats_snapshot_check_failed", "err", err)
+		} else if due {
+			if err := svc.TakeSnapshot(ctx, now); err != nil {
+				logg.Errorw("stats_snapshot_failed", "err", err)
+			} else {
+				logg.Infow("stats_snapshot_taken", "date", now.Format(time.DateOnly))
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
 	}
-
-	log.Infow("admin_created", "email", adminEmail, "role", adminRole)
-	fmt.Println("✅ Admin user created: email=admin password=admin role=roo")
 }
@@ -2,7 +2,11 @@ package config
 
 import (
 	"log"
+	"net"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -12,6 +16,31 @@ type Config struct {
 	AppPort   string
 	JWTSecret string
 	DBURL     string
+
+	// Первый пользователь РОО (создаётся, только если в базе нет ни одного)
+	AdminEmail    string
+	AdminPassword string
+
+	// Время жизни токенов
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+
+	// Пул соединений с БД
+	DBMaxConns         int32
+	DBMinConns         int32
+	DBMaxConnIdleTime  time.Duration
+	DBMaxConnLifetime  time.Duration
+	DBStatementTimeout time.Duration
+
+	// Сколько хранятся записи в корзине до окончательного удаления; 0 — не удалять
+	TrashRetention time.Duration
+
+	// Периодичность снимков сводной статистики: day, month или off — не делать
+	StatsSnapshotPeriod string
+
+	// Прокси, которым доверяем X-Forwarded-For и X-Real-IP (IP или подсети через запятую);
+	// пусто — IP клиента берётся только из адреса соединения
+	TrustedProxies []*net.IPNet
 }
 
 func Load() *Config {
@@ -21,9 +50,93 @@ func Load() *Config {
 		AppPort:   os.Getenv("APP_PORT"),
 		JWTSecret: os.Getenv("JWT_SECRET"),
 		DBURL:     os.Getenv("DB_URL"),
+
+		AdminEmail:    os.Getenv("ADMIN_EMAIL"),
+		AdminPassword: os.Getenv("ADMIN_PASSWORD"),
+
+		AccessTokenTTL:  getDuration("ACCESS_TOKEN_TTL", 15*time.Minute),
+		RefreshTokenTTL: getDuration("REFRESH_TOKEN_TTL", 30*24*time.Hour),
+
+		DBMaxConns:         int32(getInt("DB_MAX_CONNS", 20)),
+		DBMinConns:         int32(getInt("DB_MIN_CONNS", 2)),
+		DBMaxConnIdleTime:  getDuration("DB_MAX_CONN_IDLE_TIME", 5*time.Minute),
+		DBMaxConnLifetime:  getDuration("DB_MAX_CONN_LIFETIME", time.Hour),
+		DBStatementTimeout: getDuration("DB_STATEMENT_TIMEOUT", 30*time.Second),
+
+		TrashRetention: time.Duration(getInt("TRASH_RETENTION_DAYS", 30)) * 24 * time.Hour,
+
+		StatsSnapshotPeriod: os.Getenv("STATS_SNAPSHOT_PERIOD"),
+
+		TrustedProxies: getNets("TRUSTED_PROXIES"),
+	}
+	if cfg.StatsSnapshotPeriod == "" {
+		cfg.StatsSnapshotPeriod = "day"
 	}
 	if cfg.DBURL == "" {
 		log.Fatal("DB_URL is required")
 	}
+	if cfg.DBMinConns > cfg.DBMaxConns {
+		log.Fatal("DB_MIN_CONNS must not exceed DB_MAX_CONNS")
+	}
+	switch cfg.StatsSnapshotPeriod {
+	case "day", "month", "off":
+	default:
+		log.Fatal("STATS_SNAPSHOT_PERIOD must be day, month or off")
+	}
 	return cfg
 }
+
+// getInt читает целое из env, при отсутствии возвращает значение по умолчанию.
+func getInt(key string, def int) int {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		log.Fatalf("%s must be a non-negative integer", key)
+	}
+	return n
+}
+
+// getDuration читает длительность в формате time.ParseDuration (например 30s, 5m).
+func getDuration(key string, def time.Duration) time.Duration {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d < 0 {
+		log.Fatalf("%s must be a duration like 30s or 5m", key)
+	}
+	return d
+}
+
+// getNets читает список IP и подсетей (10.0.0.1, 10.0.0.0/8) через запятую; одиночный IP — подсеть из одного адреса.
+func getNets(key string) []*net.IPNet {
+	var nets []*net.IPNet
+	for _, v := range strings.Split(os.Getenv(key), ",") {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			continue
+		}
+		if !strings.Contains(v, "/") {
+			ip := net.ParseIP(v)
+			if ip == nil {
+				log.Fatalf("%s must be a comma-separated list of IPs or CIDRs", key)
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, n, err := net.ParseCIDR(v)
+		if err != nil {
+			log.Fatalf("%s must be a comma-separated list of IPs or CIDRs", key)
+		}
+		nets = append(nets, n)
+	}
+	return nets
+}
@@ -15,45 +15,69 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/auth/login": {
-            "post": {
-                "description": "Авторизация для ROO и School, возвращает JWT токен.",
-                "consumes": [
-                    "application/json"
+        "/academic-years": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Все учебные годы, новые сверху. ID года передаётся в ?academic_year= для просмотра архива.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Auth"
+                    "AcademicYears"
                 ],
-                "summary": "Авторизация пользователя",
-                "parameters": [
-                    {
-                        "description": "Данные для входа",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
+                "summary": "Учебные годы",
+                "responses": {
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.loginRequest"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.AcademicYear"
+                            }
                         }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/academic-years/current": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
                     }
                 ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "AcademicYears"
+                ],
+                "summary": "Текущий учебный год",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.LoginResponse"
+                            "$ref": "#/definitions/models.AcademicYear"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/helpers.ErrorResponse"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/helpers.ErrorResponse"
                         }
@@ -61,33 +85,65 @@ const docTemplate = `{
                 }
             }
         },
-        "/classes": {
+        "/assignments": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "ROO — все классы, School — только свои",
+                "description": "Кто какой предмет ведёт в каком классе и сколько часов в неделю; записи без staff_id — вакансии.\nРОО и инспектор — все школы или school_id, школа — свои, учитель — только свой класс. По умолчанию — текущий учебный год.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Classes"
+                    "Assignments"
+                ],
+                "summary": "Назначения учителей",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID школы (для РОО и инспектора)",
+                        "name": "school_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID класса",
+                        "name": "class_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID сотрудника",
+                        "name": "staff_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID предмета",
+                        "name": "subject_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID учебного года (по умолчанию текущий)",
+                        "name": "academic_year",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Получить список классов",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/models.Class"
+                                "$ref": "#/definitions/models.TeachingAssignment"
                             }
                         }
                     },
-                    "403": {
-                        "description": "Forbidden",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/helpers.ErrorResponse"
                         }
@@ -106,7 +162,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "School создаёт свой класс",
+                "description": "Школа — в свои классы, РОО — в любые. Без staff_id создаётся вакансия.",
                 "consumes": [
                     "application/json"
                 ],
@@ -114,17 +170,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Classes"
+                    "Assignments"
                 ],
-                "summary": "Создать новый класс",
+                "summary": "Назначить учителя на предмет в классе",
                 "parameters": [
                     {
-                        "description": "Данные класса",
+                        "description": "Назначение",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.Class"
+                            "$ref": "#/definitions/handlers.assignmentRequest"
                         }
                     }
                 ],
@@ -132,7 +188,7 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/models.Class"
+                            "$ref": "#/definitions/models.TeachingAssignment"
                         }
                     },
                     "400": {
@@ -141,8 +197,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/helpers.ErrorResponse"
                         }
                     },
-                    "403": {
-                        "description": "Forbidden",
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "$ref": "#/definitions/helpers.ErrorResponse"
                         }
@@ -156,39 +212,51 @@ const docTemplate = `{
                 }
             }
         },
-        "/classes/{id}": {
-            "get": {
+        "/assignments/{id}": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "ROO — любой класс, School — только свой",
+                "description": "Меняет учителя (без staff_id — вакансия) и часы; класс и предмет не меняются",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Classes"
+                    "Assignments"
                 ],
-                "summary": "Получить класс по ID",
+                "summary": "Изменить назначение",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID класса",
+                        "description": "ID назначения",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Учитель и часы",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.assignmentRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Class"
+                            "$ref": "#/definitions/models.TeachingAssignment"
                         }
                     },
-                    "403": {
-                        "description": "Forbidden",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/helpers.ErrorResponse"
                         }
@@ -199,6 +267,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/helpers.ErrorResponse"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -207,39 +281,23 @@ const docTemplate = `{
                     }
                 }
             },
-            "put": {
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "ROO — может обновить любой, School — только свой",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
                 "tags": [
-                    "Classes"
+                    "Assignments"
                 ],
-                "summary": "Обновить класс",
+                "summary": "Снять назначение",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID класса",
+                        "description": "ID назначения",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Данные для обновления",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.Class"
-                        }
                     }
                 ],
                 "responses": {
@@ -252,18 +310,6 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/helpers.ErrorResponse"
-                        }
-                    },
-                    "403": {
-                        "description": "Forbidden",
-                        "schema": {
-                            "$ref": "#/definitions/helpers.ErrorResponse"
-                        }
-                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -277,35 +323,49 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/attendance": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "School — только свои, ROO — любые",
+                "description": "Все обучающиеся класса с отметками; неотмеченные — без status. Учитель — только свой класс.\nПросмотр фиксируется в журнале доступа к персональным данным",
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "Classes"
+                    "Attendance"
                 ],
-                "summary": "Удалить класс",
+                "summary": "Посещаемость класса за день",
                 "parameters": [
                     {
                         "type": "integer",
                         "description": "ID класса",
-                        "name": "id",
-                        "in": "path",
+                        "name": "class_id",
+                        "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Дата YYYY-MM-DD (по умолчанию сегодня)",
+                        "name": "date",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/models.ClassAttendance"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
                         }
                     },
                     "403": {
@@ -327,16 +387,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/roo/register_school": {
-            "post": {
+            },
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Доступно только для ROO. Создаёт пользователя с ролью ` + "`" + `school` + "`" + ` и запись в таблице ` + "`" + `schools` + "`" + `.",
+                "description": "Пакетная отправка отметок за день по всему классу: present, sick, excused, unexcused, late.\nСохраняются все отметки или ни одной; повторная отправка исправляет ранее поставленные. Учитель — только свой класс.\nДата — в пределах текущего учебного года и не позже сегодняшней; каждый ученик — не больше одного раза.",
                 "consumes": [
                     "application/json"
                 ],
@@ -344,28 +402,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "ROO"
+                    "Attendance"
                 ],
-                "summary": "Регистрация новой школы",
+                "summary": "Отметить посещаемость класса",
                 "parameters": [
                     {
-                        "description": "Данные новой школы",
-                        "name": "request",
+                        "description": "Отметки",
+                        "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handlers.registerSchoolRequest"
+                            "$ref": "#/definitions/handlers.markAttendanceRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "{\"status\": \"school registered\"}",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/models.ClassAttendance"
                         }
                     },
                     "400": {
@@ -374,14 +429,14 @@ const docTemplate = `{
                             "$ref": "#/definitions/helpers.ErrorResponse"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/helpers.ErrorResponse"
                         }
                     },
-                    "403": {
-                        "description": "Forbidden",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/helpers.ErrorResponse"
                         }
@@ -395,33 +450,51 @@ const docTemplate = `{
                 }
             }
         },
-        "/roo/schools": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
+        "/auth/login": {
+            "post": {
+                "description": "Авторизация для всех ролей, возвращает access- и refresh-токены.",
+                "consumes": [
+                    "application/json"
                 ],
-                "description": "Возвращает список всех школ (только для ROO)",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Schools"
+                    "Auth"
+                ],
+                "summary": "Авторизация пользователя",
+                "parameters": [
+                    {
+                        "description": "Данные для входа",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.loginRequest"
+                        }
+                    }
                 ],
-                "summary": "Получить все школы",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.School"
-                            }
+                            "$ref": "#/definitions/handlers.LoginResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "школа сотрудника в корзине",
                         "schema": {
                             "$ref": "#/definitions/helpers.ErrorResponse"
                         }
@@ -429,39 +502,41 @@ const docTemplate = `{
                 }
             }
         },
-        "/roo/schools/{id}": {
-            "get": {
+        "/auth/logout": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Возвращает данные конкретной школы (только для ROO)",
+                "description": "Отзывает текущую сессию. С all=true — все сессии пользователя на всех устройствах.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Schools"
+                    "Auth"
                 ],
-                "summary": "Получить школу по ID",
+                "summary": "Выход из системы",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "ID школы",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "type": "boolean",
+                        "description": "Завершить все сессии",
+                        "name": "all",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.School"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/helpers.ErrorResponse"
                         }
@@ -473,14 +548,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/auth/me/password": {
             "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Обновляет данные школы по ID (только для ROO)",
+                "description": "Меняет пароль, завершает все остальные сессии и возвращает новую пару токенов.",
                 "consumes": [
                     "application/json"
                 ],
@@ -488,24 +565,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Schools"
+                    "Auth"
                 ],
-                "summary": "Обновить школу",
+                "summary": "Смена пароля текущего пользователя",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "ID школы",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Поля для обновления",
-                        "name": "request",
+                        "description": "Старый и новый пароль",
+                        "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.School"
+                            "$ref": "#/definitions/handlers.changePasswordRequest"
                         }
                     }
                 ],
@@ -513,10 +583,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/handlers.LoginResponse"
                         }
                     },
                     "400": {
@@ -525,6 +592,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/helpers.ErrorResponse"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -532,42 +605,53 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
+            }
+        },
+        "/auth/refresh": {
+            "post": {
+                "description": "Обменивает refresh-токен на новую пару токенов. Старый refresh-токен становится недействительным.",
+                "consumes": [
+                    "application/json"
                 ],
-                "description": "Удаляет школу по ID (только для ROO)",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Schools"
+                    "Auth"
                 ],
-                "summary": "Удалить школу",
+                "summary": "Обновление токенов",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "ID школы",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Refresh-токен",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.refreshRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/handlers.LoginResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "школа сотрудника в корзине",
                         "schema": {
                             "$ref": "#/definitions/helpers.ErrorResponse"
                         }
@@ -575,50 +659,26 @@ const docTemplate = `{
                 }
             }
         },
-        "/staff": {
+        "/classes": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "ROO — все, School — только свои. Поддерживает фильтры по всем полям.",
+                "description": "РОО — все классы, школа — только свои, учитель — только свой класс. По умолчанию — текущий учебный год.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Staff"
+                    "Classes"
                 ],
-                "summary": "Получить список сотрудников",
+                "summary": "Получить список классов",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "ФИО",
-                        "name": "full_name",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Телефон",
-                        "name": "phone",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Должность",
-                        "name": "position",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Образование",
-                        "name": "education",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Категория",
-                        "name": "category",
+                        "type": "integer",
+                        "description": "ID учебного года (по умолчанию текущий)",
+                        "name": "academic_year",
                         "in": "query"
                     }
                 ],
@@ -628,10 +688,22 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/models.Staff"
+                                "$ref": "#/definitions/models.Class"
                             }
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -646,7 +718,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Только школа может добавлять своих сотрудников",
+                "description": "Школа создаёт свой класс; РОО указывает school_id в теле. academic_year_id по умолчанию — текущий учебный год.",
                 "consumes": [
                     "application/json"
                 ],
@@ -654,17 +726,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Staff"
+                    "Classes"
                 ],
-                "summary": "Добавить сотрудника",
+                "summary": "Создать новый класс",
                 "parameters": [
                     {
-                        "description": "Данные сотрудника",
+                        "description": "Данные класса",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.Staff"
+                            "$ref": "#/definitions/models.Class"
                         }
                     }
                 ],
@@ -672,7 +744,7 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/models.Staff"
+                            "$ref": "#/definitions/models.Class"
                         }
                     },
                     "400": {
@@ -696,29 +768,40 @@ const docTemplate = `{
                 }
             }
         },
-        "/staff/stats": {
+        "/classes/export/xlsx": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Кол-во сотрудников по должностям",
+                "description": "Для РОО и инспектора — лист на каждую школу; школа выгружает свои классы, учитель — свой класс.",
                 "produces": [
-                    "application/json"
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
                 ],
                 "tags": [
-                    "Staff"
+                    "Classes"
+                ],
+                "summary": "Экспорт классов в xlsx",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID учебного года (по умолчанию текущий)",
+                        "name": "academic_year",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Получить статистику по персоналу (ROO)",
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "xlsx",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "integer"
-                            }
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
                         }
                     },
                     "403": {
@@ -726,29 +809,35 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/helpers.ErrorResponse"
                         }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/staff/{id}": {
+        "/classes/{id}": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "ROO — любого, School — только своего",
+                "description": "РОО — любой класс, школа — только свой, учитель — только свой класс",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Staff"
+                    "Classes"
                 ],
-                "summary": "Получить сотрудника по ID",
+                "summary": "Получить класс по ID",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID сотрудника",
+                        "description": "ID класса",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -758,7 +847,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Staff"
+                            "$ref": "#/definitions/models.Class"
                         }
                     },
                     "403": {
@@ -772,6 +861,12 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/helpers.ErrorResponse"
                         }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
                     }
                 }
             },
@@ -781,7 +876,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "ROO — любого, School — только своего",
+                "description": "РОО — может обновить любой, школа — только свой. class_teacher_id — классный руководитель из сотрудников школы:\nесли поле не передано, руководитель не меняется; null — снять руководителя",
                 "consumes": [
                     "application/json"
                 ],
@@ -789,24 +884,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Staff"
+                    "Classes"
                 ],
-                "summary": "Обновить данные сотрудника",
+                "summary": "Обновить класс",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID сотрудника",
+                        "description": "ID класса",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Обновлённые данные",
+                        "description": "Данные для обновления",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.Staff"
+                            "$ref": "#/definitions/models.Class"
                         }
                     }
                 ],
@@ -820,6 +915,12 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
                     "403": {
                         "description": "Forbidden",
                         "schema": {
@@ -831,6 +932,12 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/helpers.ErrorResponse"
                         }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
                     }
                 }
             },
@@ -840,15 +947,15 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "School — только своего, ROO — любого",
+                "description": "Переносит класс с учениками в корзину (/trash). Школа — только свои, РОО — любые",
                 "tags": [
-                    "Staff"
+                    "Classes"
                 ],
-                "summary": "Удалить сотрудника",
+                "summary": "Удалить класс",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID сотрудника",
+                        "description": "ID класса",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -870,6 +977,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/helpers.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -879,44 +992,48 @@ const docTemplate = `{
                 }
             }
         },
-        "/stats/summary": {
+        "/classes/{id}/timetable": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "ROO — вся система или по school_id; School — только своя школа (параметр игнорируется)",
+                "description": "Уроки класса на неделю по дням и номерам уроков. РОО — любой класс, школа — свой, учитель — только свой класс",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Stats"
+                    "Timetable"
                 ],
-                "summary": "Сводная статистика (кол-во классов, учеников, учителей)",
+                "summary": "Расписание класса",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Фильтрация по школе (только для ROO)",
-                        "name": "school_id",
-                        "in": "query"
+                        "description": "ID класса",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.StatsSummary"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.TimetableEntry"
+                            }
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/helpers.ErrorResponse"
                         }
                     },
-                    "403": {
-                        "description": "Forbidden",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/helpers.ErrorResponse"
                         }
@@ -928,50 +1045,71 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/students": {
-            "get": {
+            },
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
+                "description": "Школа — только в свои классы текущего учебного года. Учитель и кабинет не могут быть заняты в это время другим уроком того же учебного года (409);\nкласс — тоже, кроме подгрупп: уроков одного предмета у разных учителей.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Students"
+                    "Timetable"
                 ],
-                "summary": "Получить список учеников",
+                "summary": "Добавить урок в расписание класса",
                 "parameters": [
-                    {
-                        "type": "string",
-                        "description": "ФИО",
-                        "name": "full_name",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Пол (male/female)",
-                        "name": "gender",
-                        "in": "query"
-                    },
                     {
                         "type": "integer",
                         "description": "ID класса",
-                        "name": "class_id",
-                        "in": "query"
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Урок",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.lessonRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Student"
-                            }
+                            "$ref": "#/definitions/models.TimetableEntry"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
                         }
                     },
                     "500": {
@@ -981,13 +1119,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/classes/{id}/timetable/{entryID}": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
+                "description": "Школа — только в своих классах; проверки занятости те же, что при добавлении",
                 "consumes": [
                     "application/json"
                 ],
@@ -995,25 +1136,39 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Students"
+                    "Timetable"
                 ],
-                "summary": "Добавить ученика",
+                "summary": "Изменить урок в расписании класса",
                 "parameters": [
                     {
-                        "description": "Данные ученика",
+                        "type": "integer",
+                        "description": "ID класса",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID урока",
+                        "name": "entryID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Урок",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.Student"
+                            "$ref": "#/definitions/handlers.lessonRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Student"
+                            "$ref": "#/definitions/models.TimetableEntry"
                         }
                     },
                     "400": {
@@ -1028,6 +1183,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/helpers.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1035,94 +1202,125 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/students/export": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "produces": [
-                    "text/csv"
-                ],
+                "description": "Школа — только в своих классах текущего учебного года",
                 "tags": [
-                    "Students"
+                    "Timetable"
                 ],
-                "summary": "Экспорт учеников в CSV (только ROO)",
-                "responses": {
-                    "200": {
-                        "description": "csv file",
-                        "schema": {
-                            "type": "string"
-                        }
-                    }
-                }
-            }
-        },
-        "/students/stats": {
-            "get": {
-                "security": [
+                "summary": "Убрать урок из расписания класса",
+                "parameters": [
                     {
-                        "BearerAuth": []
+                        "type": "integer",
+                        "description": "ID класса",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID урока",
+                        "name": "entryID",
+                        "in": "path",
+                        "required": true
                     }
                 ],
-                "description": "Только для ROO (по полу, школам и т.д.)",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Students"
-                ],
-                "summary": "Получить статистику по ученикам",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
-                                "type": "integer"
+                                "type": "string"
                             }
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
                     "403": {
                         "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/helpers.ErrorResponse"
                         }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/students/{id}": {
+        "/marks": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
+                "description": "Ученики класса с отметками за период и итоговыми отметками по предмету. По умолчанию — текущий месяц, не больше года.\nУчитель — только свой класс. Просмотр фиксируется в журнале доступа к персональным данным",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Students"
+                    "Marks"
                 ],
-                "summary": "Получить ученика по ID",
+                "summary": "Журнал класса по предмету",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID ученика",
-                        "name": "id",
-                        "in": "path",
+                        "description": "ID класса",
+                        "name": "class_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID предмета",
+                        "name": "subject_id",
+                        "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Начало периода YYYY-MM-DD (по умолчанию первое число месяца)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Конец периода YYYY-MM-DD (включительно, по умолчанию сегодня)",
+                        "name": "to",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Student"
+                            "$ref": "#/definitions/models.ClassJournal"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
                         }
                     },
                     "403": {
@@ -1136,6 +1334,12 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/helpers.ErrorResponse"
                         }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
                     }
                 }
             },
@@ -1145,6 +1349,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
+                "description": "Пакетная отправка отметок 2–5 по предмету за дату урока; отметка без mark снимается.\nСохраняются все отметки или ни одной; повторная отправка исправляет ранее поставленные. Учитель — только свой класс.\nДата — в пределах текущего учебного года и не позже сегодняшней; каждый ученик — не больше одного раза.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1152,24 +1357,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Students"
+                    "Marks"
                 ],
-                "summary": "Обновить данные ученика",
+                "summary": "Поставить отметки классу",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "ID ученика",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Новые данные",
+                        "description": "Отметки",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.Student"
+                            "$ref": "#/definitions/handlers.markRequest"
                         }
                     }
                 ],
@@ -1177,10 +1375,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/models.ClassJournal"
                         }
                     },
                     "400": {
@@ -1200,38 +1395,4099 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/helpers.ErrorResponse"
                         }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/marks/terms": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
+                "description": "Пакетная отправка отметок за четверть (q1–q4), полугодие (h1, h2) или год (year) по предмету; отметка без mark снимается.\nСохраняются все отметки или ни одной; каждый ученик — не больше одного раза. Учитель — только свой класс.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "Students"
+                    "Marks"
                 ],
-                "summary": "Удалить ученика",
+                "summary": "Поставить итоговые отметки классу",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "ID ученика",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Итоговые отметки",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.termMarkRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.TermMark"
                             }
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/promotion": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Переводит классы текущего года в целевой: 5А → 6А (класс создаётся, если его ещё нет), ученики выпускного класса (11, в основной школе — 9) получают статус graduated.\nrepeaters остаются в классе той же параллели, leavers получают статус left. Школа переводит только свои классы, РОО — указанную школу или весь район.\nС dry_run=true возвращает план без изменений. После перевода РОО делает целевой год текущим: PUT /roo/academic-years/{id}/current.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Promotion"
+                ],
+                "summary": "Перевод классов на следующий учебный год",
+                "parameters": [
+                    {
+                        "description": "Параметры перевода",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PromotionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PromotionResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "перевод уже выполнен или нет текущего года",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/roo/academic-years": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Только РОО. Новый год не становится текущим автоматически.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "AcademicYears"
+                ],
+                "summary": "Добавить учебный год",
+                "parameters": [
+                    {
+                        "description": "Учебный год (даты в формате YYYY-MM-DD)",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.academicYearRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.AcademicYear"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/roo/academic-years/{id}/current": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Только РОО. Классы и ученики по умолчанию показываются за текущий год; остальные годы доступны через ?academic_year=.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "AcademicYears"
+                ],
+                "summary": "Сделать учебный год текущим",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID учебного года",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/roo/audit": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Кто, когда и что изменил. Для update хранятся только изменившиеся поля (before/after). РОО и инспектор.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Audit"
+                ],
+                "summary": "Журнал изменений",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID школы",
+                        "name": "school_id",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "student",
+                            "staff",
+                            "class",
+                            "school",
+                            "user"
+                        ],
+                        "type": "string",
+                        "description": "Тип сущности",
+                        "name": "entity_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID сущности",
+                        "name": "entity_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Кто изменил",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "С даты (YYYY-MM-DD или RFC3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "По дату включительно (YYYY-MM-DD или RFC3339)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Сколько записей (по умолчанию 100, максимум 1000)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Смещение",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.AuditEntry"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/roo/pd-access": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Кто и когда просматривал карточки, списки и выгрузки учеников. Например: student_id=15\u0026from=2025-09-01\u0026to=2025-09-30 — кто смотрел ученика 15 в сентябре.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Audit"
+                ],
+                "summary": "Журнал доступа к персональным данным учеников",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID ученика",
+                        "name": "student_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Кто просматривал",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "С даты (YYYY-MM-DD или RFC3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "По дату включительно (YYYY-MM-DD или RFC3339)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Сколько записей (по умолчанию 100, максимум 1000)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Смещение",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.PDAccessEntry"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/roo/register-school": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Создаёт школу и генерирует пароль автоматически.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ROO"
+                ],
+                "summary": "Регистрация школы (ROO)",
+                "parameters": [
+                    {
+                        "description": "Данные для регистрации",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.registerSchoolRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/roo/schools": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает список всех школ (РОО; чтение — также инспектор)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Schools"
+                ],
+                "summary": "Получить все школы",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.School"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/roo/schools/export/xlsx": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "РОО и инспектор",
+                "produces": [
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "Schools"
+                ],
+                "summary": "Экспорт списка школ в xlsx",
+                "responses": {
+                    "200": {
+                        "description": "xlsx",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/roo/schools/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает данные конкретной школы (РОО; чтение — также инспектор)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Schools"
+                ],
+                "summary": "Получить школу по ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID школы",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.School"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Обновляет данные школы по ID (только для ROO)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Schools"
+                ],
+                "summary": "Обновить школу",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID школы",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Поля для обновления",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.School"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Переносит школу с классами, сотрудниками и учениками в корзину (/trash) и отзывает сессии её сотрудников (только для ROO)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Schools"
+                ],
+                "summary": "Удалить школу",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID школы",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/roo/schools/{id}/reset-password": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Генерирует новый пароль и возвращает его один раз; в базе хранится только хэш (только для ROO)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Schools"
+                ],
+                "summary": "Сбросить пароль школы",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID школы",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResetPasswordResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/roo/schools/{id}/revoke-sessions": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Отзывает все access/refresh токены учётной записи школы, например при компрометации (только для ROO)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Schools"
+                ],
+                "summary": "Завершить все сессии школы",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID школы",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/roo/subjects": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Только РОО",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Subjects"
+                ],
+                "summary": "Добавить предмет",
+                "parameters": [
+                    {
+                        "description": "Предмет",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Subject"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Subject"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/roo/subjects/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Только РОО",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Subjects"
+                ],
+                "summary": "Переименовать предмет",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID предмета",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Предмет",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Subject"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Subject"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Только РОО; предмет, который используется в назначениях, расписании или оценках, удалить нельзя",
+                "tags": [
+                    "Subjects"
+                ],
+                "summary": "Удалить предмет",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID предмета",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/roo/users": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Список пользователей",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.User"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Если пароль не передан — генерируется. Пароль возвращается один раз и должен быть сменён при первом входе.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Создать пользователя",
+                "parameters": [
+                    {
+                        "description": "Данные пользователя",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.userRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.UserCreatedResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/roo/users/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Пользователь по ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID пользователя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Меняет email, ФИО, роль и класс учителя. Пароль меняется через /auth/me/password или сброс.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Обновить пользователя",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID пользователя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Данные пользователя",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.userRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Нельзя удалить себя, последнего администратора РОО и директора школы",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Удалить пользователя",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID пользователя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/roo/users/{id}/reset-password": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Генерирует временный пароль (показывается один раз), завершает сессии и требует смены пароля при входе",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Сбросить пароль пользователя",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID пользователя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/school/users": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Список пользователей",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.User"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Если пароль не передан — генерируется. Пароль возвращается один раз и должен быть сменён при первом входе.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Создать пользователя",
+                "parameters": [
+                    {
+                        "description": "Данные пользователя",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.userRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.UserCreatedResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/school/users/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Пользователь по ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID пользователя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Меняет email, ФИО, роль и класс учителя. Пароль меняется через /auth/me/password или сброс.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Обновить пользователя",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID пользователя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Данные пользователя",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.userRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Нельзя удалить себя, последнего администратора РОО и директора школы",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Удалить пользователя",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID пользователя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/school/users/{id}/reset-password": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Генерирует временный пароль (показывается один раз), завершает сессии и требует смены пароля при входе",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Сбросить пароль пользователя",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID пользователя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/staff": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Только сотрудники школы с правом staff:write добавляют сотрудников своей школы",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Staff"
+                ],
+                "summary": "Добавить сотрудника",
+                "parameters": [
+                    {
+                        "description": "Данные сотрудника",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Staff"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Staff"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/staff/export/xlsx": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Фильтры — как у списка /staff; для РОО и инспектора — лист на каждую школу. Школа выгружает своих сотрудников.",
+                "produces": [
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "Staff"
+                ],
+                "summary": "Экспорт сотрудников в xlsx",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ФИО",
+                        "name": "full_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Телефон",
+                        "name": "phone",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Должность",
+                        "name": "position",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Предмет",
+                        "name": "subject",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Образование",
+                        "name": "education",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Категория",
+                        "name": "category",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "xlsx",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/staff/import": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Файл по шаблону /staff/import/template (csv — в UTF-8 с теми же колонками, разделитель «;» или «,»), читается первый лист.\nСтрока, совпавшая с сотрудником школы по телефону (match=phone, по умолчанию) или ФИО (match=full_name), обновляет его заполненными ячейками; остальные добавляются.\nПри любой ошибке не загружается ничего (422 с ошибками по строкам); dry_run=true только проверяет файл.\nШкола загружает своих сотрудников, РОО — сотрудников школы school_id.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Staff"
+                ],
+                "summary": "Загрузка сотрудников из xlsx или csv",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "Файл xlsx или csv",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "phone",
+                        "description": "Сопоставление с существующими: phone или full_name",
+                        "name": "match",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID школы (обязателен для РОО)",
+                        "name": "school_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Только проверить, не загружать",
+                        "name": "dry_run",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "dry_run: файл без ошибок",
+                        "schema": {
+                            "$ref": "#/definitions/models.ImportReport"
+                        }
+                    },
+                    "201": {
+                        "description": "сотрудники загружены",
+                        "schema": {
+                            "$ref": "#/definitions/models.ImportReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.ImportReport"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/staff/import/template": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Лист «Сотрудники» — заголовок для заполнения, лист «Пример» — пример строки; обязательные колонки отмечены «*».\nСтаж — целое число лет, даты рождения и начала работы — ДД.ММ.ГГГГ или дата Excel.",
+                "produces": [
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "Staff"
+                ],
+                "summary": "Шаблон загрузки сотрудников (xlsx)",
+                "responses": {
+                    "200": {
+                        "description": "xlsx",
+                        "schema": {
+                            "type": "file"
+                        }
+                    }
+                }
+            }
+        },
+        "/staff/stats": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Кол-во сотрудников по должностям. Устарело: кадровый состав — /stats/staff",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Staff"
+                ],
+                "summary": "Получить статистику по персоналу (ROO)",
+                "deprecated": true,
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "integer"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/staff/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "ROO — любого, School — только своего",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Staff"
+                ],
+                "summary": "Получить сотрудника по ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID сотрудника",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Staff"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "ROO — любого, School — только своего",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Staff"
+                ],
+                "summary": "Обновить данные сотрудника",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID сотрудника",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Обновлённые данные",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Staff"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Переносит сотрудника в корзину (/trash). Школа — только своего, РОО — любого",
+                "tags": [
+                    "Staff"
+                ],
+                "summary": "Удалить сотрудника",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID сотрудника",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/staff/{id}/timetable": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Уроки сотрудника во всех классах за учебный год. РОО — любого, школа — только своего",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Timetable"
+                ],
+                "summary": "Расписание учителя",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID сотрудника",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID учебного года (по умолчанию текущий)",
+                        "name": "academic_year",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.TimetableEntry"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/stats/attendance": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Доля присутствовавших (present + late) от отмеченных учеников по каждой школе за каждый день периода.\nРОО и инспектор — весь район или по school_id; школа — только своя. По умолчанию — последние 7 дней, не больше 92 дней.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stats"
+                ],
+                "summary": "Посещаемость по школам и дням",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Фильтрация по школе (только для РОО и инспектора)",
+                        "name": "school_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Начало периода YYYY-MM-DD",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Конец периода YYYY-MM-DD (включительно, по умолчанию сегодня)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.AttendanceDay"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/stats/history": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Значения показателя по ежедневным (или ежемесячным — STATS_SNAPSHOT_PERIOD) снимкам за период, по возрастанию даты.\nПоказатели: schools, classes, students, teachers, staff_total. По умолчанию — последний год.\nРОО и инспектор — весь район или по school_id; школа — только своя.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stats"
+                ],
+                "summary": "Динамика сводной статистики",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Показатель: schools, classes, students, teachers, staff_total",
+                        "name": "metric",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Фильтрация по школе (только для РОО и инспектора)",
+                        "name": "school_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Начало периода YYYY-MM-DD (по умолчанию год назад)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Конец периода YYYY-MM-DD (включительно, по умолчанию сегодня)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.StatsHistory"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/stats/oo1": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Классы и обучающиеся по параллелям, обучающиеся по возрасту (на 1 января учебного года), полу и параллелям,\nработники и учителя по образованию, квалификационной категории и педагогическому стажу.\nОбучающиеся — по книге движения на дату (школа и класс последнего прибытия или смены класса); работники — принятые не позже даты;\nучитель — должность «учитель» или назначения учебного года, как в /stats/staff.\nРОО и инспектор — весь район или по school_id; школа — только своя.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stats"
+                ],
+                "summary": "Форма ОО-1 на дату",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Фильтрация по школе (только для РОО и инспектора)",
+                        "name": "school_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Дата отчёта YYYY-MM-DD (по умолчанию сегодня)",
+                        "name": "date",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.OO1Report"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/stats/oo1/xlsx": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Те же разделы, что /stats/oo1, — по листу на раздел, с итоговыми строками.",
+                "produces": [
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "Stats"
+                ],
+                "summary": "Форма ОО-1 на дату (xlsx)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Фильтрация по школе (только для РОО и инспектора)",
+                        "name": "school_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Дата отчёта YYYY-MM-DD (по умолчанию сегодня)",
+                        "name": "date",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "xlsx",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/stats/performance": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "По итоговым отметкам периода: успеваемость — доля учеников без «2», качество знаний — доля учеников только на «4» и «5».\nУченик учитывается, если у него есть хотя бы одна итоговая отметка за период. Разрезы — классы, школы и район (только для всего района).\nРОО и инспектор — весь район или по school_id; школа — только своя.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stats"
+                ],
+                "summary": "Успеваемость и качество знаний",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Фильтрация по школе (только для РОО и инспектора)",
+                        "name": "school_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Период: q1–q4, h1, h2, year (по умолчанию year)",
+                        "name": "period",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID учебного года (по умолчанию текущий)",
+                        "name": "academic_year",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PerformanceStats"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/stats/staff": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Учителя по квалификационной категории, уровню образования и педагогическому стажу (до 3, 3–5, 5–10, 10–20, 20 лет и более),\nпредпенсионного и пенсионного возраста (55 лет и старше) и молодые специалисты (стаж меньше 3 лет, моложе 35 лет); доли — в процентах от учителей.\nУчитель — сотрудник с должностью «учитель» или с назначениями текущего учебного года. Разрез по школам.\nРОО и инспектор — весь район или по school_id; школа — только своя.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stats"
+                ],
+                "summary": "Кадровый состав",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Фильтрация по школе (только для РОО и инспектора)",
+                        "name": "school_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.StaffStats"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/stats/students": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возрастная пирамида (полных лет на дату, по полу), пол по параллелям, наполняемость классов по интервалам и разрез по школам.\nУченики — по зачислениям учебного года: для текущего года — только обучающиеся, для прошлых — все зачисленные в тот год.\nРОО и инспектор — весь район или по school_id; школа — только своя.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stats"
+                ],
+                "summary": "Состав учеников",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Фильтрация по школе (только для РОО и инспектора)",
+                        "name": "school_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Параллель",
+                        "name": "grade",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID учебного года (по умолчанию текущий)",
+                        "name": "academic_year",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Дата, на которую считается возраст, YYYY-MM-DD (по умолчанию сегодня)",
+                        "name": "date",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.StudentStats"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/stats/summary": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "РОО и инспектор — весь район или по school_id; школа — только своя (параметр игнорируется)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stats"
+                ],
+                "summary": "Сводная статистика (кол-во классов, учеников, учителей)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Фильтрация по школе (только для РОО и инспектора)",
+                        "name": "school_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "schools, classes, students, teachers, staff_total",
+                        "schema": {
+                            "$ref": "#/definitions/models.StatsSummary"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/stats/teaching-load": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Недельные часы каждого учителя по назначениям текущего учебного года и отклонение от ставки (18 ч),\nвакантные часы (назначения без учителя) по школам и предметам и сколько учителей нужно району по каждому предмету.\nРОО и инспектор — весь район или по school_id; школа — только своя.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stats"
+                ],
+                "summary": "Нагрузка учителей и нехватка кадров по предметам",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Фильтрация по школе (только для РОО и инспектора)",
+                        "name": "school_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TeachingLoad"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/students": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Выборка (фильтр и ID учеников) фиксируется в журнале доступа к персональным данным",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Students"
+                ],
+                "summary": "Получить список учеников",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ФИО",
+                        "name": "full_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Пол (male/female)",
+                        "name": "gender",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID класса",
+                        "name": "class_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID учебного года (по умолчанию текущий); для архивного года — класс и школа того года",
+                        "name": "academic_year",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Student"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Students"
+                ],
+                "summary": "Добавить ученика",
+                "parameters": [
+                    {
+                        "description": "Данные ученика",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Student"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Student"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/students/export": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Вместе с родителями и законными представителями; фильтры — как у списка /students. Школа выгружает своих учеников, РОО и инспектор — всех.\nВыгрузка фиксируется в журнале доступа к персональным данным",
+                "produces": [
+                    "text/csv"
+                ],
+                "tags": [
+                    "Students"
+                ],
+                "summary": "Экспорт учеников в CSV",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ФИО",
+                        "name": "full_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Пол (male/female)",
+                        "name": "gender",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID класса",
+                        "name": "class_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID учебного года (по умолчанию текущий)",
+                        "name": "academic_year",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "csv file",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/students/export/xlsx": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Фильтры — как у списка /students; для РОО и инспектора — лист на каждую школу. Школа выгружает своих учеников.\nВыгрузка фиксируется в журнале доступа к персональным данным",
+                "produces": [
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "Students"
+                ],
+                "summary": "Экспорт учеников в xlsx",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ФИО",
+                        "name": "full_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Пол (male/female)",
+                        "name": "gender",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID класса",
+                        "name": "class_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID учебного года (по умолчанию текущий)",
+                        "name": "academic_year",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "xlsx",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/students/import": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Файл по шаблону /students/import/template, читается первый лист. Классы сопоставляются по названию среди классов школы текущего учебного года.\nПроверяются все строки: ФИО и класс обязательны, дата рождения, пол, дубли в файле и среди обучающихся школы.\nПри любой ошибке не загружается ничего (422 с ошибками по строкам); dry_run=true только проверяет файл. Только для школы.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Students"
+                ],
+                "summary": "Загрузка учеников из xlsx",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "Файл xlsx (или csv в UTF-8)",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Только проверить, не загружать",
+                        "name": "dry_run",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "dry_run: файл без ошибок",
+                        "schema": {
+                            "$ref": "#/definitions/models.ImportReport"
+                        }
+                    },
+                    "201": {
+                        "description": "ученики загружены",
+                        "schema": {
+                            "$ref": "#/definitions/models.ImportReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.ImportReport"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/students/import/template": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Лист «Ученики» — заголовок для заполнения, лист «Пример» — пример строки; обязательные колонки отмечены «*». Дата рождения — ДД.ММ.ГГГГ или дата Excel, пол — М или Ж.\nЛист «Классы» — классы школы в текущем учебном году, названия которых нужно указывать в колонке «Класс».",
+                "produces": [
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "Students"
+                ],
+                "summary": "Шаблон загрузки учеников (xlsx)",
+                "responses": {
+                    "200": {
+                        "description": "xlsx",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/students/stats": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Только для ROO (по полу, школам и т.д.). Устарело: подробный состав учеников — /stats/students",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Students"
+                ],
+                "summary": "Получить статистику по ученикам",
+                "deprecated": true,
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "integer"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/students/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Просмотр фиксируется в журнале доступа к персональным данным",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Students"
+                ],
+                "summary": "Получить ученика по ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID ученика",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Student"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Students"
+                ],
+                "summary": "Обновить данные ученика",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID ученика",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Новые данные",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Student"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Переносит ученика в корзину (/trash)",
+                "tags": [
+                    "Students"
+                ],
+                "summary": "Удалить ученика",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID ученика",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/students/{id}/guardians": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Доступ как к самому ученику; просмотр фиксируется в журнале доступа к персональным данным",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Guardians"
+                ],
+                "summary": "Родители и законные представители ученика",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID ученика",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Guardian"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Новый представитель — по полям full_name, relation и т.д.; существующий (родитель брата или сестры) — по id.\nis_primary делает представителя основным контактом ученика.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Guardians"
+                ],
+                "summary": "Добавить представителя ученику",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID ученика",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Представитель",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Guardian"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Guardian"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/students/{id}/guardians/{guardianID}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Данные представителя общие для всех его детей; is_primary относится к этому ученику",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Guardians"
+                ],
+                "summary": "Обновить представителя",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID ученика",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID представителя",
+                        "name": "guardianID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Новые данные",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Guardian"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Guardian"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Представитель, не связанный больше ни с одним учеником, удаляется",
+                "tags": [
+                    "Guardians"
+                ],
+                "summary": "Убрать представителя у ученика",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID ученика",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID представителя",
+                        "name": "guardianID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/students/{id}/marks": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Отметки и итоговые отметки ученика по предметам за учебный год. Учитель — только свой класс.\nПросмотр фиксируется в журнале доступа к персональным данным",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Marks"
+                ],
+                "summary": "Успеваемость ученика",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID ученика",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID учебного года (по умолчанию текущий)",
+                        "name": "academic_year",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.StudentMarks"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/students/{id}/movements": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Прибытия, выбытия и смены класса. Историю видят РОО и все школы, в которых ученик числился; учитель — только для своего класса.\nПросмотр фиксируется в журнале доступа к персональным данным",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Students"
+                ],
+                "summary": "Книга движения ученика",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID ученика",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.StudentMovement"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/subjects": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Subjects"
+                ],
+                "summary": "Справочник предметов",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Subject"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transfers": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Школа видит заявки, где она отправляет или принимает ученика; РОО — все. Учителям недоступно.\nВыборка фиксируется в журнале доступа к персональным данным.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Transfers"
+                ],
+                "summary": "Заявки на перевод",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "incoming — входящие, outgoing — исходящие (для школы)",
+                        "name": "direction",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "pending | accepted | rejected | cancelled",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID ученика",
+                        "name": "student_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.StudentTransfer"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Школа ученика создаёт заявку, принимающая школа подтверждает её через /transfers/{id}/accept.\nРОО может создать заявку за любую школу, а с class_id — сразу провести перевод.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Transfers"
+                ],
+                "summary": "Начать перевод ученика в другую школу",
+                "parameters": [
+                    {
+                        "description": "Заявка",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.createTransferRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.StudentTransfer"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "у ученика уже есть незавершённая заявка",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transfers/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Transfers"
+                ],
+                "summary": "Заявка на перевод",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID заявки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.StudentTransfer"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transfers/{id}/accept": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Принимающая школа зачисляет ученика в свой класс текущего учебного года; РОО может провести любую заявку.\nВыбытие и прибытие записываются в книгу движения обеих школ.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Transfers"
+                ],
+                "summary": "Принять ученика по заявке",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID заявки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Класс",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.acceptTransferRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.StudentTransfer"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "заявка закрыта или ученик уже выбыл",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transfers/{id}/cancel": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Отправляющая школа или РОО",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Transfers"
+                ],
+                "summary": "Отозвать заявку на перевод",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID заявки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Комментарий",
+                        "name": "data",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.closeTransferRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.StudentTransfer"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/transfers/{id}/reject": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Принимающая школа или РОО",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Transfers"
+                ],
+                "summary": "Отказать в переводе",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID заявки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Причина отказа",
+                        "name": "data",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.closeTransferRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.StudentTransfer"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/trash": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Удалённые записи, которые пользователь может восстановить: РОО — всё, школа — классы, сотрудники и ученики своей школы (по правам роли). Записи хранятся в корзине ограниченное время (TRASH_RETENTION_DAYS), затем удаляются окончательно.\nПросмотр удалённых учеников фиксируется в журнале доступа к персональным данным",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Trash"
+                ],
+                "summary": "Корзина",
+                "parameters": [
+                    {
+                        "enum": [
+                            "schools",
+                            "classes",
+                            "staff",
+                            "students"
+                        ],
+                        "type": "string",
+                        "description": "Раздел",
+                        "name": "kind",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.TrashItem"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/trash/{kind}/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Школа восстанавливается вместе с классами, сотрудниками и учениками, удалёнными вместе с ней; класс — вместе со своими учениками. Нельзя восстановить запись, пока в корзине её школа или класс.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Trash"
+                ],
+                "summary": "Восстановить из корзины",
+                "parameters": [
+                    {
+                        "enum": [
+                            "schools",
+                            "classes",
+                            "staff",
+                            "students"
+                        ],
+                        "type": "string",
+                        "description": "Раздел",
+                        "name": "kind",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID записи",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1244,202 +5500,1973 @@ const docTemplate = `{
     },
     "definitions": {
         "handlers.LoginResponse": {
-            "description": "JWT токен для дальнейших запросов",
+            "description": "JWT токен для дальнейших запросов и refresh-токен для его обновления",
+            "type": "object",
+            "properties": {
+                "expires_in": {
+                    "description": "время жизни access-токена, сек",
+                    "type": "integer",
+                    "example": 900
+                },
+                "must_change_password": {
+                    "description": "true — до смены пароля через /auth/me/password остальные методы недоступны",
+                    "type": "boolean",
+                    "example": false
+                },
+                "refresh_token": {
+                    "description": "refresh-токен (одноразовый)",
+                    "type": "string",
+                    "example": "q1w2e3r4t5y6u7i8o9p0..."
+                },
+                "token": {
+                    "description": "JWT access-токен",
+                    "type": "string",
+                    "example": "eyJhbGciOiJIUzI1NiIs..."
+                }
+            }
+        },
+        "handlers.ResetPasswordResponse": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "school1@example.com"
+                },
+                "password": {
+                    "type": "string",
+                    "example": "aB3dE5fG"
+                },
+                "school_id": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "handlers.UserCreatedResponse": {
+            "type": "object",
+            "properties": {
+                "password": {
+                    "type": "string",
+                    "example": "aB3dE5fG7hJ9"
+                },
+                "user": {
+                    "$ref": "#/definitions/models.User"
+                }
+            }
+        },
+        "handlers.academicYearRequest": {
+            "type": "object",
+            "properties": {
+                "end_date": {
+                    "type": "string",
+                    "example": "2027-08-31"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "2026/2027"
+                },
+                "start_date": {
+                    "type": "string",
+                    "example": "2026-09-01"
+                }
+            }
+        },
+        "handlers.acceptTransferRequest": {
+            "type": "object",
+            "properties": {
+                "class_id": {
+                    "type": "integer",
+                    "example": 42
+                }
+            }
+        },
+        "handlers.assignmentRequest": {
+            "type": "object",
+            "properties": {
+                "class_id": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "hours_per_week": {
+                    "type": "number",
+                    "example": 4
+                },
+                "staff_id": {
+                    "description": "без staff_id — вакансия",
+                    "type": "integer"
+                },
+                "subject_id": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "handlers.changePasswordRequest": {
+            "type": "object",
+            "properties": {
+                "new_password": {
+                    "type": "string"
+                },
+                "old_password": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.closeTransferRequest": {
+            "type": "object",
+            "properties": {
+                "note": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.createTransferRequest": {
+            "type": "object",
+            "properties": {
+                "class_id": {
+                    "description": "только РОО: класс принимающей школы — перевод проводится сразу",
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "смена места жительства"
+                },
+                "student_id": {
+                    "type": "integer",
+                    "example": 15
+                },
+                "to_school_id": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "handlers.lessonRequest": {
+            "type": "object",
+            "properties": {
+                "lesson": {
+                    "description": "номер урока, 1–12",
+                    "type": "integer",
+                    "example": 3
+                },
+                "room": {
+                    "type": "string",
+                    "example": "204"
+                },
+                "staff_id": {
+                    "type": "integer"
+                },
+                "subject_id": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "weekday": {
+                    "description": "1 — понедельник … 6 — суббота",
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "handlers.loginRequest": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.markAttendanceRequest": {
+            "type": "object",
+            "properties": {
+                "class_id": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "date": {
+                    "description": "YYYY-MM-DD, по умолчанию сегодня",
+                    "type": "string",
+                    "example": "2025-11-17"
+                },
+                "records": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AttendanceMark"
+                    }
+                }
+            }
+        },
+        "handlers.markRequest": {
+            "type": "object",
+            "properties": {
+                "class_id": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "date": {
+                    "description": "YYYY-MM-DD, по умолчанию сегодня",
+                    "type": "string",
+                    "example": "2025-11-20"
+                },
+                "marks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.MarkEntry"
+                    }
+                },
+                "subject_id": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "handlers.refreshRequest": {
+            "type": "object",
+            "properties": {
+                "refresh_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.registerSchoolRequest": {
+            "type": "object",
+            "properties": {
+                "director": {
+                    "type": "string",
+                    "example": "Иванов Иван"
+                },
+                "email": {
+                    "type": "string",
+                    "example": "school1@example.com"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Школа №1"
+                },
+                "password": {
+                    "type": "string",
+                    "example": "123456"
+                }
+            }
+        },
+        "handlers.termMarkRequest": {
+            "type": "object",
+            "properties": {
+                "class_id": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "marks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.MarkEntry"
+                    }
+                },
+                "period": {
+                    "description": "q1–q4, h1, h2, year",
+                    "type": "string",
+                    "example": "q1"
+                },
+                "subject_id": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "handlers.userRequest": {
+            "type": "object",
+            "properties": {
+                "class_id": {
+                    "description": "класс учителя",
+                    "type": "integer"
+                },
+                "email": {
+                    "type": "string",
+                    "example": "inspector@roo.ru"
+                },
+                "full_name": {
+                    "type": "string",
+                    "example": "Петрова Анна Сергеевна"
+                },
+                "password": {
+                    "description": "пусто — сгенерировать",
+                    "type": "string",
+                    "example": ""
+                },
+                "role": {
+                    "description": "roo|inspector для РОО; deputy|secretary|teacher для школы",
+                    "type": "string",
+                    "example": "inspector"
+                }
+            }
+        },
+        "helpers.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.AcademicYear": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "end_date": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_current": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "2025/2026"
+                },
+                "start_date": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.AttendanceDay": {
+            "type": "object",
+            "properties": {
+                "date": {
+                    "type": "string",
+                    "example": "2025-11-17"
+                },
+                "excused": {
+                    "type": "integer"
+                },
+                "late": {
+                    "type": "integer"
+                },
+                "marked": {
+                    "description": "отмечено за день",
+                    "type": "integer"
+                },
+                "present": {
+                    "type": "integer"
+                },
+                "rate": {
+                    "description": "доля присутствовавших (present + late) от отмеченных, %",
+                    "type": "number",
+                    "example": 94.5
+                },
+                "school_id": {
+                    "type": "integer"
+                },
+                "school_name": {
+                    "type": "string"
+                },
+                "sick": {
+                    "type": "integer"
+                },
+                "students": {
+                    "description": "обучающихся в школе сейчас",
+                    "type": "integer"
+                },
+                "unexcused": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.AttendanceMark": {
+            "type": "object",
+            "properties": {
+                "note": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "present | sick | excused | unexcused | late",
+                    "type": "string",
+                    "example": "present"
+                },
+                "student_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.AttendanceRecord": {
+            "type": "object",
+            "properties": {
+                "full_name": {
+                    "type": "string"
+                },
+                "marked_at": {
+                    "type": "string"
+                },
+                "marked_by": {
+                    "type": "integer"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "present"
+                },
+                "student_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.AuditEntry": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "description": "create | update | delete | restore | reset_password | change_password",
+                    "type": "string"
+                },
+                "after": {
+                    "type": "object"
+                },
+                "before": {
+                    "type": "object"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "entity_id": {
+                    "type": "integer"
+                },
+                "entity_type": {
+                    "description": "student | staff | class | school | user",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "ip": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                },
+                "school_id": {
+                    "type": "integer"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Class": {
+            "type": "object",
+            "properties": {
+                "academic_year_id": {
+                    "description": "учебный год; при создании по умолчанию текущий",
+                    "type": "integer"
+                },
+                "class_teacher_id": {
+                    "description": "классный руководитель (сотрудник школы)",
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "grade": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "school_id": {
+                    "type": "integer"
+                },
+                "student_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ClassAttendance": {
+            "type": "object",
+            "properties": {
+                "class_id": {
+                    "type": "integer"
+                },
+                "date": {
+                    "type": "string",
+                    "example": "2025-11-17"
+                },
+                "records": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AttendanceRecord"
+                    }
+                }
+            }
+        },
+        "models.ClassJournal": {
+            "type": "object",
+            "properties": {
+                "class_id": {
+                    "type": "integer"
+                },
+                "from": {
+                    "type": "string",
+                    "example": "2025-11-01"
+                },
+                "students": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.JournalRow"
+                    }
+                },
+                "subject_id": {
+                    "type": "integer"
+                },
+                "to": {
+                    "type": "string",
+                    "example": "2025-11-30"
+                }
+            }
+        },
+        "models.ClassSizeBand": {
+            "type": "object",
+            "properties": {
+                "band": {
+                    "type": "string",
+                    "example": "21–25"
+                },
+                "classes": {
+                    "type": "integer"
+                },
+                "max": {
+                    "type": "integer"
+                },
+                "min": {
+                    "type": "integer"
+                },
+                "students": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Guardian": {
+            "type": "object",
+            "required": [
+                "full_name"
+            ],
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "full_name": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_primary": {
+                    "description": "основной контакт по этому ученику",
+                    "type": "boolean"
+                },
+                "phones": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "relation": {
+                    "description": "mother | father | guardian (опекун) | trustee (попечитель) | other",
+                    "type": "string",
+                    "example": "mother"
+                },
+                "workplace": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ImportError": {
+            "type": "object",
+            "properties": {
+                "column": {
+                    "type": "string",
+                    "example": "Дата рождения"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "invalid date"
+                },
+                "row": {
+                    "description": "номер строки в файле, как в Excel",
+                    "type": "integer",
+                    "example": 7
+                }
+            }
+        },
+        "models.ImportReport": {
+            "type": "object",
+            "properties": {
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ImportError"
+                    }
+                },
+                "imported": {
+                    "description": "загружено (0 при dry_run и ошибках)",
+                    "type": "integer"
+                },
+                "rows": {
+                    "description": "строк с данными",
+                    "type": "integer"
+                },
+                "updated": {
+                    "description": "из них обновлено существующих записей",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.JournalRow": {
+            "type": "object",
+            "properties": {
+                "average": {
+                    "description": "средний балл отметок периода",
+                    "type": "number",
+                    "example": 4.25
+                },
+                "full_name": {
+                    "type": "string"
+                },
+                "marks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Mark"
+                    }
+                },
+                "student_id": {
+                    "type": "integer"
+                },
+                "term_marks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TermMark"
+                    }
+                }
+            }
+        },
+        "models.Mark": {
+            "type": "object",
+            "properties": {
+                "date": {
+                    "type": "string",
+                    "example": "2025-11-20"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "mark": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "marked_at": {
+                    "type": "string"
+                },
+                "marked_by": {
+                    "type": "integer"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "student_id": {
+                    "type": "integer"
+                },
+                "subject_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.MarkEntry": {
+            "type": "object",
+            "properties": {
+                "mark": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "note": {
+                    "type": "string"
+                },
+                "student_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.OO1AgeRow": {
+            "type": "object",
+            "properties": {
+                "age": {
+                    "type": "string",
+                    "example": "7 лет"
+                },
+                "by_grade": {
+                    "description": "параллель → обучающихся",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "girls": {
+                    "type": "integer"
+                },
+                "students": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.OO1GradeRow": {
+            "type": "object",
+            "properties": {
+                "classes": {
+                    "type": "integer"
+                },
+                "girls": {
+                    "type": "integer"
+                },
+                "grade": {
+                    "type": "integer"
+                },
+                "students": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.OO1Report": {
+            "type": "object",
+            "properties": {
+                "academic_year_id": {
+                    "type": "integer"
+                },
+                "age_date": {
+                    "description": "возраст обучающихся — на 1 января учебного года",
+                    "type": "string",
+                    "example": "2026-01-01"
+                },
+                "ages": {
+                    "description": "обучающиеся по возрасту и параллелям",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.OO1AgeRow"
+                    }
+                },
+                "categories": {
+                    "description": "работники по квалификационной категории",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.OO1StaffRow"
+                    }
+                },
+                "classes": {
+                    "type": "integer"
+                },
+                "date": {
+                    "type": "string",
+                    "example": "2025-09-20"
+                },
+                "education": {
+                    "description": "работники по уровню образования",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.OO1StaffRow"
+                    }
+                },
+                "experience": {
+                    "description": "работники по педагогическому стажу",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.OO1StaffRow"
+                    }
+                },
+                "girls": {
+                    "type": "integer"
+                },
+                "grades": {
+                    "description": "классы и обучающиеся по параллелям",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.OO1GradeRow"
+                    }
+                },
+                "school_id": {
+                    "description": "nil — весь район",
+                    "type": "integer"
+                },
+                "school_name": {
+                    "type": "string"
+                },
+                "staff": {
+                    "type": "integer"
+                },
+                "students": {
+                    "type": "integer"
+                },
+                "teachers": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.OO1StaffRow": {
+            "type": "object",
+            "properties": {
+                "label": {
+                    "type": "string",
+                    "example": "Высшее"
+                },
+                "staff": {
+                    "type": "integer"
+                },
+                "teachers": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.PDAccessEntry": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "description": "view | list | export",
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "filter": {
+                    "type": "object"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "ip": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                },
+                "student_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "user_email": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.PerformanceRow": {
+            "type": "object",
+            "properties": {
+                "class_id": {
+                    "type": "integer"
+                },
+                "class_name": {
+                    "type": "string"
+                },
+                "excellent": {
+                    "description": "только «5»",
+                    "type": "integer"
+                },
+                "failing": {
+                    "description": "есть «2»",
+                    "type": "integer"
+                },
+                "good": {
+                    "description": "«4» и «5»",
+                    "type": "integer"
+                },
+                "passed": {
+                    "description": "без «2»",
+                    "type": "integer"
+                },
+                "quality_rate": {
+                    "description": "качество знаний: доля учеников на «4» и «5», %",
+                    "type": "number",
+                    "example": 48.5
+                },
+                "school_id": {
+                    "type": "integer"
+                },
+                "school_name": {
+                    "type": "string"
+                },
+                "students": {
+                    "description": "аттестованных учеников",
+                    "type": "integer"
+                },
+                "success_rate": {
+                    "description": "успеваемость: доля учеников без «2», %",
+                    "type": "number",
+                    "example": 97.2
+                }
+            }
+        },
+        "models.PerformanceStats": {
+            "type": "object",
+            "properties": {
+                "academic_year_id": {
+                    "type": "integer"
+                },
+                "classes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PerformanceRow"
+                    }
+                },
+                "district": {
+                    "description": "только для всего района",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.PerformanceRow"
+                        }
+                    ]
+                },
+                "period": {
+                    "type": "string",
+                    "example": "q1"
+                },
+                "schools": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PerformanceRow"
+                    }
+                }
+            }
+        },
+        "models.PromotionClass": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "description": "promote | graduate",
+                    "type": "string",
+                    "example": "promote"
+                },
+                "created": {
+                    "description": "класс нового года создаётся при переводе",
+                    "type": "boolean"
+                },
+                "from_class_id": {
+                    "type": "integer"
+                },
+                "from_grade": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "from_name": {
+                    "type": "string",
+                    "example": "5А"
+                },
+                "school_id": {
+                    "type": "integer"
+                },
+                "to_class_id": {
+                    "description": "nil — выпуск или класс будет создан (dry_run)",
+                    "type": "integer"
+                },
+                "to_grade": {
+                    "type": "integer",
+                    "example": 6
+                },
+                "to_name": {
+                    "type": "string",
+                    "example": "6А"
+                }
+            }
+        },
+        "models.PromotionRequest": {
+            "type": "object",
+            "properties": {
+                "dry_run": {
+                    "description": "true — только показать изменения",
+                    "type": "boolean"
+                },
+                "leavers": {
+                    "description": "ID выбывающих учеников (например, после 9 класса)",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "repeaters": {
+                    "description": "ID учеников, оставленных на повторное обучение",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "school_id": {
+                    "description": "только РОО; без school_id — все школы района",
+                    "type": "integer"
+                },
+                "target_academic_year_id": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "models.PromotionResult": {
+            "type": "object",
+            "properties": {
+                "classes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PromotionClass"
+                    }
+                },
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "source_academic_year_id": {
+                    "type": "integer"
+                },
+                "students": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PromotionStudent"
+                    }
+                },
+                "target_academic_year_id": {
+                    "type": "integer"
+                },
+                "totals": {
+                    "$ref": "#/definitions/models.PromotionTotals"
+                }
+            }
+        },
+        "models.PromotionStudent": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "description": "promote | repeat | graduate | leave",
+                    "type": "string",
+                    "example": "promote"
+                },
+                "from_class_id": {
+                    "type": "integer"
+                },
+                "full_name": {
+                    "type": "string"
+                },
+                "school_id": {
+                    "type": "integer"
+                },
+                "student_id": {
+                    "type": "integer"
+                },
+                "to_class_id": {
+                    "type": "integer"
+                },
+                "to_class_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PromotionTotals": {
+            "type": "object",
+            "properties": {
+                "classes_created": {
+                    "type": "integer"
+                },
+                "graduated": {
+                    "type": "integer"
+                },
+                "left": {
+                    "type": "integer"
+                },
+                "promoted": {
+                    "type": "integer"
+                },
+                "repeated": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.School": {
+            "type": "object",
+            "properties": {
+                "class_count": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "director": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "student_count": {
+                    "type": "integer"
+                },
+                "user": {
+                    "$ref": "#/definitions/models.UserInfo"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Staff": {
+            "type": "object",
+            "required": [
+                "full_name",
+                "phone",
+                "position"
+            ],
+            "properties": {
+                "birth_date": {
+                    "type": "string"
+                },
+                "category": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "education": {
+                    "type": "string"
+                },
+                "full_name": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "ped_experience": {
+                    "type": "integer"
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "position": {
+                    "type": "string"
+                },
+                "school_id": {
+                    "type": "integer"
+                },
+                "subject": {
+                    "type": "string"
+                },
+                "total_experience": {
+                    "type": "integer"
+                },
+                "work_start": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.StaffSchoolRow": {
+            "type": "object",
+            "properties": {
+                "category_share": {
+                    "description": "доля учителей с высшей или первой категорией",
+                    "type": "number"
+                },
+                "first_category": {
+                    "type": "integer"
+                },
+                "higher_education": {
+                    "type": "integer"
+                },
+                "highest_category": {
+                    "type": "integer"
+                },
+                "near_retirement": {
+                    "type": "integer"
+                },
+                "school_id": {
+                    "type": "integer"
+                },
+                "school_name": {
+                    "type": "string"
+                },
+                "staff": {
+                    "type": "integer"
+                },
+                "teachers": {
+                    "type": "integer"
+                },
+                "young_specialists": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.StaffStats": {
+            "type": "object",
+            "properties": {
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TeacherGroup"
+                    }
+                },
+                "education": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TeacherGroup"
+                    }
+                },
+                "experience": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TeacherGroup"
+                    }
+                },
+                "near_retirement": {
+                    "type": "integer"
+                },
+                "near_retirement_share": {
+                    "type": "number"
+                },
+                "no_birth_date": {
+                    "description": "учителя без даты рождения — возраст неизвестен",
+                    "type": "integer"
+                },
+                "schools": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.StaffSchoolRow"
+                    }
+                },
+                "staff": {
+                    "type": "integer"
+                },
+                "teachers": {
+                    "type": "integer"
+                },
+                "young_specialists": {
+                    "type": "integer"
+                },
+                "young_specialists_share": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.StatsHistory": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "type": "string",
+                    "example": "2025-09-01"
+                },
+                "metric": {
+                    "type": "string",
+                    "example": "students"
+                },
+                "points": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.StatsPoint"
+                    }
+                },
+                "school_id": {
+                    "description": "nil — весь район",
+                    "type": "integer"
+                },
+                "to": {
+                    "type": "string",
+                    "example": "2026-05-31"
+                }
+            }
+        },
+        "models.StatsPoint": {
+            "type": "object",
+            "properties": {
+                "date": {
+                    "type": "string",
+                    "example": "2025-09-01"
+                },
+                "value": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.StatsSummary": {
+            "type": "object",
+            "properties": {
+                "classes": {
+                    "type": "integer"
+                },
+                "schools": {
+                    "type": "integer"
+                },
+                "staff_total": {
+                    "type": "integer"
+                },
+                "students": {
+                    "type": "integer"
+                },
+                "teachers": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Student": {
+            "type": "object",
+            "required": [
+                "full_name"
+            ],
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "birth_date": {
+                    "type": "string"
+                },
+                "class": {
+                    "type": "string"
+                },
+                "class_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "full_name": {
+                    "type": "string"
+                },
+                "gender": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "school_id": {
+                    "type": "integer"
+                },
+                "status": {
+                    "description": "active | graduated | left",
+                    "type": "string"
+                }
+            }
+        },
+        "models.StudentAgeRow": {
+            "type": "object",
+            "properties": {
+                "age": {
+                    "type": "integer"
+                },
+                "female": {
+                    "type": "integer"
+                },
+                "male": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.StudentGradeRow": {
+            "type": "object",
+            "properties": {
+                "female": {
+                    "type": "integer"
+                },
+                "grade": {
+                    "type": "integer"
+                },
+                "male": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.StudentMarks": {
+            "type": "object",
+            "properties": {
+                "academic_year_id": {
+                    "type": "integer"
+                },
+                "student_id": {
+                    "type": "integer"
+                },
+                "subjects": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SubjectMarks"
+                    }
+                }
+            }
+        },
+        "models.StudentMovement": {
+            "type": "object",
+            "properties": {
+                "academic_year_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "from_class_id": {
+                    "type": "integer"
+                },
+                "from_school_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "kind": {
+                    "type": "string",
+                    "example": "arrival"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "school_id": {
+                    "type": "integer"
+                },
+                "student_id": {
+                    "type": "integer"
+                },
+                "to_class_id": {
+                    "type": "integer"
+                },
+                "to_school_id": {
+                    "type": "integer"
+                },
+                "transfer_id": {
+                    "type": "integer"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.StudentSchoolRow": {
+            "type": "object",
+            "properties": {
+                "avg_class_size": {
+                    "type": "number"
+                },
+                "classes": {
+                    "type": "integer"
+                },
+                "female": {
+                    "type": "integer"
+                },
+                "male": {
+                    "type": "integer"
+                },
+                "school_id": {
+                    "type": "integer"
+                },
+                "school_name": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.StudentStats": {
             "type": "object",
             "properties": {
-                "token": {
-                    "description": "JWT-токен",
+                "academic_year_id": {
+                    "type": "integer"
+                },
+                "ages": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.StudentAgeRow"
+                    }
+                },
+                "class_sizes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ClassSizeBand"
+                    }
+                },
+                "date": {
+                    "description": "дата, на которую считается возраст",
                     "type": "string",
-                    "example": "eyJhbGciOiJIUzI1NiIs..."
+                    "example": "2025-09-01"
+                },
+                "female": {
+                    "type": "integer"
+                },
+                "grades": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.StudentGradeRow"
+                    }
+                },
+                "male": {
+                    "type": "integer"
+                },
+                "schools": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.StudentSchoolRow"
+                    }
+                },
+                "total": {
+                    "type": "integer"
                 }
             }
         },
-        "handlers.loginRequest": {
+        "models.StudentTransfer": {
             "type": "object",
             "properties": {
-                "email": {
+                "created_at": {
                     "type": "string"
                 },
-                "password": {
+                "created_by": {
+                    "type": "integer"
+                },
+                "decided_at": {
+                    "type": "string"
+                },
+                "decided_by": {
+                    "type": "integer"
+                },
+                "decision_note": {
+                    "type": "string"
+                },
+                "from_class_id": {
+                    "type": "integer"
+                },
+                "from_school": {
+                    "type": "string"
+                },
+                "from_school_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "pending"
+                },
+                "student_id": {
+                    "type": "integer"
+                },
+                "student_name": {
+                    "type": "string"
+                },
+                "to_class_id": {
+                    "description": "класс, в который зачислен ученик",
+                    "type": "integer"
+                },
+                "to_school": {
                     "type": "string"
+                },
+                "to_school_id": {
+                    "type": "integer"
                 }
             }
         },
-        "handlers.registerSchoolRequest": {
+        "models.Subject": {
             "type": "object",
             "properties": {
-                "director": {
-                    "type": "string",
-                    "example": "Иванов Иван"
+                "created_at": {
+                    "type": "string"
                 },
-                "email": {
-                    "type": "string",
-                    "example": "school1@example.com"
+                "id": {
+                    "type": "integer"
                 },
                 "name": {
                     "type": "string",
-                    "example": "Школа №1"
+                    "example": "Математика"
                 },
-                "password": {
+                "short_name": {
                     "type": "string",
-                    "example": "123456"
+                    "example": "Матем."
                 }
             }
         },
-        "helpers.ErrorResponse": {
+        "models.SubjectMarks": {
             "type": "object",
             "properties": {
-                "error": {
+                "average": {
+                    "type": "number"
+                },
+                "marks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Mark"
+                    }
+                },
+                "subject_id": {
+                    "type": "integer"
+                },
+                "subject_name": {
                     "type": "string"
+                },
+                "term_marks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TermMark"
+                    }
                 }
             }
         },
-        "models.Class": {
+        "models.SubjectNeed": {
             "type": "object",
             "properties": {
-                "created_at": {
+                "hours": {
+                    "type": "number"
+                },
+                "subject_id": {
+                    "type": "integer"
+                },
+                "subject_name": {
                     "type": "string"
                 },
-                "grade": {
+                "teachers": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.SubjectVacancy": {
+            "type": "object",
+            "properties": {
+                "classes": {
+                    "description": "классов без учителя",
                     "type": "integer"
                 },
-                "id": {
+                "hours": {
+                    "type": "number",
+                    "example": 12
+                },
+                "school_id": {
                     "type": "integer"
                 },
-                "name": {
+                "school_name": {
                     "type": "string"
                 },
-                "school_id": {
+                "subject_id": {
                     "type": "integer"
                 },
-                "student_count": {
+                "subject_name": {
+                    "type": "string"
+                },
+                "teachers": {
+                    "description": "нужно учителей: часы / ставка с округлением вверх",
                     "type": "integer"
                 }
             }
         },
-        "models.School": {
+        "models.TeacherGroup": {
             "type": "object",
             "properties": {
-                "class_count": {
-                    "type": "integer"
+                "label": {
+                    "type": "string",
+                    "example": "Высшая"
                 },
-                "created_at": {
+                "share": {
+                    "type": "number"
+                },
+                "teachers": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.TeacherLoad": {
+            "type": "object",
+            "properties": {
+                "full_name": {
                     "type": "string"
                 },
-                "director": {
+                "hours": {
+                    "type": "number",
+                    "example": 22
+                },
+                "position": {
                     "type": "string"
                 },
-                "id": {
+                "rate": {
+                    "description": "доля ставки",
+                    "type": "number",
+                    "example": 1.22
+                },
+                "school_id": {
                     "type": "integer"
                 },
-                "name": {
+                "school_name": {
                     "type": "string"
                 },
-                "student_count": {
+                "staff_id": {
                     "type": "integer"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "over",
+                        "under",
+                        "normal"
+                    ]
                 }
             }
         },
-        "models.Staff": {
+        "models.TeachingAssignment": {
             "type": "object",
-            "required": [
-                "full_name",
-                "phone",
-                "position"
-            ],
             "properties": {
-                "category": {
+                "academic_year_id": {
+                    "description": "учебный год класса",
+                    "type": "integer"
+                },
+                "class_id": {
+                    "type": "integer"
+                },
+                "class_name": {
                     "type": "string"
                 },
                 "created_at": {
                     "type": "string"
                 },
-                "education": {
+                "hours_per_week": {
+                    "type": "number",
+                    "example": 4
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "school_id": {
+                    "type": "integer"
+                },
+                "staff_id": {
+                    "type": "integer"
+                },
+                "staff_name": {
                     "type": "string"
                 },
-                "full_name": {
+                "subject_id": {
+                    "type": "integer"
+                },
+                "subject_name": {
                     "type": "string"
+                }
+            }
+        },
+        "models.TeachingLoad": {
+            "type": "object",
+            "properties": {
+                "needed": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SubjectNeed"
+                    }
+                },
+                "overloaded": {
+                    "type": "integer"
+                },
+                "standard_hours": {
+                    "description": "ставка, часов в неделю",
+                    "type": "number",
+                    "example": 18
+                },
+                "teachers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TeacherLoad"
+                    }
+                },
+                "underloaded": {
+                    "type": "integer"
+                },
+                "vacancies": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SubjectVacancy"
+                    }
+                }
+            }
+        },
+        "models.TermMark": {
+            "type": "object",
+            "properties": {
+                "academic_year_id": {
+                    "type": "integer"
+                },
+                "class_id": {
+                    "type": "integer"
                 },
                 "id": {
                     "type": "integer"
                 },
-                "note": {
+                "mark": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "marked_at": {
                     "type": "string"
                 },
-                "ped_experience": {
+                "marked_by": {
                     "type": "integer"
                 },
-                "phone": {
+                "period": {
+                    "description": "q1–q4, h1, h2, year",
+                    "type": "string",
+                    "example": "q1"
+                },
+                "student_id": {
+                    "type": "integer"
+                },
+                "subject_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.TimetableEntry": {
+            "type": "object",
+            "properties": {
+                "academic_year_id": {
+                    "description": "учебный год класса",
+                    "type": "integer"
+                },
+                "class_id": {
+                    "type": "integer"
+                },
+                "class_name": {
                     "type": "string"
                 },
-                "position": {
+                "created_at": {
                     "type": "string"
                 },
+                "id": {
+                    "type": "integer"
+                },
+                "lesson": {
+                    "description": "номер урока, 1–12",
+                    "type": "integer",
+                    "example": 3
+                },
+                "room": {
+                    "type": "string",
+                    "example": "204"
+                },
                 "school_id": {
                     "type": "integer"
                 },
-                "total_experience": {
+                "staff_id": {
                     "type": "integer"
                 },
-                "work_start": {
+                "staff_name": {
+                    "type": "string"
+                },
+                "subject_id": {
+                    "type": "integer"
+                },
+                "subject_name": {
                     "type": "string"
+                },
+                "weekday": {
+                    "description": "1 — понедельник … 6 — суббота",
+                    "type": "integer",
+                    "example": 1
                 }
             }
         },
-        "models.StatsSummary": {
+        "models.TrashItem": {
             "type": "object",
             "properties": {
-                "classes": {
+                "class_id": {
+                    "description": "только для ученика",
                     "type": "integer"
                 },
-                "staff_total": {
+                "deleted_at": {
+                    "type": "string"
+                },
+                "deleted_by": {
                     "type": "integer"
                 },
-                "students": {
+                "entity_type": {
+                    "description": "school | class | staff | student",
+                    "type": "string"
+                },
+                "id": {
                     "type": "integer"
                 },
-                "teachers": {
+                "name": {
+                    "type": "string"
+                },
+                "school_id": {
                     "type": "integer"
                 }
             }
         },
-        "models.Student": {
+        "models.User": {
             "type": "object",
-            "required": [
-                "full_name"
-            ],
             "properties": {
-                "address": {
-                    "type": "string"
-                },
-                "birth_date": {
-                    "type": "string"
-                },
                 "class_id": {
                     "type": "integer"
                 },
                 "created_at": {
                     "type": "string"
                 },
-                "full_name": {
+                "email": {
                     "type": "string"
                 },
-                "gender": {
+                "full_name": {
                     "type": "string"
                 },
                 "id": {
                     "type": "integer"
                 },
-                "note": {
-                    "type": "string"
+                "must_change_password": {
+                    "type": "boolean"
                 },
-                "phone": {
+                "role": {
+                    "description": "roo | inspector | director | deputy | secretary | teacher",
                     "type": "string"
                 },
                 "school_id": {
                     "type": "integer"
                 }
             }
+        },
+        "models.UserInfo": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "role": {
+                    "type": "string"
+                }
+            }
         }
     },
     "securityDefinitions": {
@@ -1458,7 +7485,7 @@ var SwaggerInfo = &swag.Spec{
 	BasePath:         "/",
 	Schemes:          []string{},
 	Title:            "eduBase API",
-	Description:      "База школ с ролями ROO и School.",
+	Description:      "База школ района: РОО, инспекторы и сотрудники школ с разграничением прав.",
 	InfoInstanceName: "swagger",
 	SwaggerTemplate:  docTemplate,
 	LeftDelim:        "{{",
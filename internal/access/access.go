@@ -0,0 +1,171 @@
+package access
+
+import (
+	"context"
+
+	"github.com/go-chi/jwtauth/v5"
+)
+
+// Роли пользователей
+const (
+	RoleROO       = "roo"       // администратор РОО
+	RoleInspector = "inspector" // инспектор РОО, только чтение
+	RoleDirector  = "director"  // директор школы
+	RoleDeputy    = "deputy"    // завуч
+	RoleSecretary = "secretary" // секретарь
+	RoleTeacher   = "teacher"   // учитель, видит только свой класс
+)
+
+// Permission — право на действие вида "ресурс:операция"
+type Permission string
+
+const (
+	SchoolsRead         Permission = "schools:read"
+	SchoolsWrite        Permission = "schools:write"
+	UsersManage         Permission = "users:manage"        // учётные записи РОО
+	SchoolUsersManage   Permission = "school_users:manage" // учётные записи сотрудников своей школы
+	ClassesRead         Permission = "classes:read"
+	ClassesWrite        Permission = "classes:write"
+	StaffRead           Permission = "staff:read"
+	StaffWrite          Permission = "staff:write"
+	StudentsRead        Permission = "students:read"
+	StudentsWrite       Permission = "students:write"
+	StudentsExport      Permission = "students:export"
+	StatsRead           Permission = "stats:read"
+	AuditRead           Permission = "audit:read"            // журнал изменений
+	AcademicYearsManage Permission = "academic_years:manage" // учебные годы района
+	AttendanceWrite     Permission = "attendance:write"      // отметки посещаемости
+	SubjectsManage      Permission = "subjects:manage"       // справочник предметов района
+	MarksWrite          Permission = "marks:write"           // отметки в журнале и итоговые
+)
+
+var rolePermissions = map[string][]Permission{
+	RoleROO: {
+		SchoolsRead, SchoolsWrite, UsersManage,
+		ClassesRead, ClassesWrite, StaffRead, StaffWrite,
+		StudentsRead, StudentsWrite, StudentsExport, StatsRead, AuditRead,
+		AcademicYearsManage, SubjectsManage,
+	},
+	RoleInspector: {
+		SchoolsRead, ClassesRead, StaffRead, StudentsRead, StudentsExport, StatsRead, AuditRead,
+	},
+	RoleDirector: {
+		SchoolUsersManage, ClassesRead, ClassesWrite, StaffRead, StaffWrite,
+		StudentsRead, StudentsWrite, StudentsExport, StatsRead, AttendanceWrite, MarksWrite,
+	},
+	RoleDeputy: {
+		ClassesRead, ClassesWrite, StaffRead, StaffWrite,
+		StudentsRead, StudentsWrite, StudentsExport, StatsRead, AttendanceWrite, MarksWrite,
+	},
+	RoleSecretary: {
+		ClassesRead, StaffRead, StudentsRead, StudentsWrite, StudentsExport, AttendanceWrite,
+	},
+	RoleTeacher: {
+		ClassesRead, StudentsRead, AttendanceWrite, MarksWrite,
+	},
+}
+
+// Can — есть ли у роли право
+func Can(role string, perm Permission) bool {
+	for _, p := range rolePermissions[role] {
+		if p == perm {
+			return true
+		}
+	}
+	return false
+}
+
+// IsDistrictRole — роль уровня района (видит все школы)
+func IsDistrictRole(role string) bool {
+	return role == RoleROO || role == RoleInspector
+}
+
+// IsSchoolRole — роль сотрудника школы
+func IsSchoolRole(role string) bool {
+	switch role {
+	case RoleDirector, RoleDeputy, RoleSecretary, RoleTeacher:
+		return true
+	}
+	return false
+}
+
+// Principal — текущий пользователь, восстановленный из claims JWT
+type Principal struct {
+	UserID    int
+	Role      string
+	SessionID int64
+	SchoolID  *int // nil — районный уровень
+	ClassID   *int // класс учителя
+}
+
+// FromContext читает claims, положенные jwtauth.Verifier
+func FromContext(ctx context.Context) Principal {
+	_, claims, _ := jwtauth.FromContext(ctx)
+	var p Principal
+	if v, ok := claims["user_id"].(float64); ok {
+		p.UserID = int(v)
+	}
+	p.Role, _ = claims["role"].(string)
+	if v, ok := claims["sid"].(float64); ok {
+		p.SessionID = int64(v)
+	}
+	if v, ok := claims["school_id"].(float64); ok {
+		id := int(v)
+		p.SchoolID = &id
+	}
+	if v, ok := claims["class_id"].(float64); ok {
+		id := int(v)
+		p.ClassID = &id
+	}
+	return p
+}
+
+func (p Principal) Can(perm Permission) bool { return Can(p.Role, perm) }
+
+func (p Principal) IsDistrict() bool { return IsDistrictRole(p.Role) }
+
+func (p Principal) IsTeacher() bool { return p.Role == RoleTeacher }
+
+// SchoolScope — ограничение по школе для запросов: nil для района, своя школа для остальных.
+// Сотрудник без привязки к школе получает school_id=0, то есть пустой результат.
+func (p Principal) SchoolScope() *int {
+	if p.IsDistrict() {
+		return nil
+	}
+	if p.SchoolID == nil {
+		none := 0
+		return &none
+	}
+	return p.SchoolID
+}
+
+// CanAccessSchool — принадлежит ли школа зоне видимости пользователя
+func (p Principal) CanAccessSchool(schoolID int) bool {
+	if p.IsDistrict() {
+		return true
+	}
+	return p.SchoolID != nil && *p.SchoolID == schoolID
+}
+
+// CanAccessClass — для учителя только свой класс, для остальных — класс своей школы
+func (p Principal) CanAccessClass(schoolID, classID int) bool {
+	if !p.CanAccessSchool(schoolID) {
+		return false
+	}
+	if p.IsTeacher() {
+		return p.ClassID != nil && *p.ClassID == classID
+	}
+	return true
+}
+
+// ClassScope — ограничение по классу: для учителя его класс (или 0, если класс не назначен)
+func (p Principal) ClassScope() *int {
+	if !p.IsTeacher() {
+		return nil
+	}
+	if p.ClassID == nil {
+		none := 0
+		return &none
+	}
+	return p.ClassID
+}
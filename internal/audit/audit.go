@@ -0,0 +1,159 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"slices"
+
+	"eduBase/internal/models"
+	"eduBase/internal/repository"
+)
+
+// Типы сущностей журнала
+const (
+	EntityStudent      = "student"
+	EntityStaff        = "staff"
+	EntityClass        = "class"
+	EntitySchool       = "school"
+	EntityUser         = "user"
+	EntityTransfer     = "transfer"
+	EntityGuardian     = "guardian"
+	EntityGuardianLink = "guardian_link" // связь представителя с учеником; entity_id — ID представителя
+	EntitySubject      = "subject"
+	EntityAssignment   = "assignment"
+	EntityTimetable    = "timetable"
+	EntityAttendance   = "attendance" // отметка посещаемости; entity_id — ID ученика
+	EntityMark         = "mark"       // текущая отметка; entity_id — ID ученика
+	EntityTermMark     = "term_mark"  // итоговая отметка; entity_id — ID ученика
+)
+
+// Действия
+const (
+	ActionCreate         = "create"
+	ActionUpdate         = "update"
+	ActionDelete         = "delete"
+	ActionRestore        = "restore"
+	ActionResetPassword  = "reset_password"
+	ActionChangePassword = "change_password" // пользователь сменил пароль сам
+)
+
+// Actor — кто выполняет запрос; кладётся в контекст middleware.AuditActor
+type Actor struct {
+	UserID int
+	Role   string
+	IP     string
+}
+
+type actorKey struct{}
+
+func WithActor(ctx context.Context, a Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, a)
+}
+
+// ActorFrom возвращает исполнителя; ok=false — системное действие (фоновая задача, миграция)
+func ActorFrom(ctx context.Context) (Actor, bool) {
+	a, ok := ctx.Value(actorKey{}).(Actor)
+	return a, ok
+}
+
+// Change — изменение одной сущности. Before — состояние до, After — после (nil при create/delete).
+type Change struct {
+	SchoolID   *int
+	EntityType string
+	EntityID   int
+	Action     string
+	Before     any
+	After      any
+	// Keep — поля, которые при update остаются в before/after, даже если не изменились:
+	// без них запись не понять (например, дата отметки)
+	Keep []string
+}
+
+// Record пишет изменение в журнал через q — вызывается в той же транзакции, что и само изменение.
+// Для update в журнал попадают только изменившиеся поля.
+func Record(ctx context.Context, q repository.DBTX, c Change) error {
+	before, after, err := diff(c.Before, c.After, c.Keep)
+	if err != nil {
+		return err
+	}
+	if c.Action == ActionUpdate && before == nil && after == nil {
+		return nil // ничего не поменялось
+	}
+
+	e := &models.AuditEntry{
+		SchoolID:   c.SchoolID,
+		EntityType: c.EntityType,
+		EntityID:   c.EntityID,
+		Action:     c.Action,
+		Before:     before,
+		After:      after,
+	}
+	if a, ok := ActorFrom(ctx); ok {
+		e.UserID = &a.UserID
+		e.Role = &a.Role
+		if a.IP != "" {
+			e.IP = &a.IP
+		}
+	}
+	return repository.NewAuditRepository(q).Insert(ctx, e)
+}
+
+// diff сериализует состояния; если заданы оба — оставляет только отличающиеся поля и поля keep
+func diff(before, after any, keep []string) (json.RawMessage, json.RawMessage, error) {
+	b, err := toMap(before)
+	if err != nil {
+		return nil, nil, err
+	}
+	a, err := toMap(after)
+	if err != nil {
+		return nil, nil, err
+	}
+	if b != nil && a != nil {
+		kept := 0
+		for k, v := range b {
+			if av, ok := a[k]; ok && reflect.DeepEqual(v, av) {
+				if slices.Contains(keep, k) {
+					kept++
+					continue
+				}
+				delete(b, k)
+				delete(a, k)
+			}
+		}
+		if len(b) == kept && len(a) == kept {
+			return nil, nil, nil
+		}
+	}
+	bj, err := marshal(b)
+	if err != nil {
+		return nil, nil, err
+	}
+	aj, err := marshal(a)
+	if err != nil {
+		return nil, nil, err
+	}
+	return bj, aj, nil
+}
+
+func toMap(v any) (map[string]any, error) {
+	if v == nil || (reflect.ValueOf(v).Kind() == reflect.Ptr && reflect.ValueOf(v).IsNil()) {
+		return nil, nil
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]any
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func marshal(m map[string]any) (json.RawMessage, error) {
+	if m == nil {
+		return nil, nil
+	}
+	return json.Marshal(m)
+}
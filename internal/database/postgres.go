@@ -0,0 +1,38 @@
+package database
+
+import (
+	"context"
+	"strconv"
+
+	"eduBase/config"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// NewPool создаёт пул соединений с настройками из конфига и проверяет подключение.
+func NewPool(ctx context.Context, cfg *config.Config) (*pgxpool.Pool, error) {
+	poolCfg, err := pgxpool.ParseConfig(cfg.DBURL)
+	if err != nil {
+		return nil, err
+	}
+
+	poolCfg.MaxConns = cfg.DBMaxConns
+	poolCfg.MinConns = cfg.DBMinConns
+	poolCfg.MaxConnIdleTime = cfg.DBMaxConnIdleTime
+	poolCfg.MaxConnLifetime = cfg.DBMaxConnLifetime
+
+	// statement_timeout задаётся на уровне сессии для каждого соединения пула
+	if cfg.DBStatementTimeout > 0 {
+		poolCfg.ConnConfig.RuntimeParams["statement_timeout"] = strconv.FormatInt(cfg.DBStatementTimeout.Milliseconds(), 10)
+	}
+
+	pool, err := pgxpool.NewWithConfig(ctx, poolCfg)
+	if err != nil {
+		return nil, err
+	}
+	if err := pool.Ping(ctx); err != nil {
+		pool.Close()
+		return nil, err
+	}
+	return pool, nil
+}
@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"eduBase/internal/access"
+	"eduBase/internal/helpers"
+	"eduBase/internal/middleware"
+	"eduBase/internal/models"
+	"eduBase/internal/repository"
+	"eduBase/internal/services"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// AcademicYearHandler — учебные годы
+type AcademicYearHandler struct {
+	svc *services.AcademicYearService
+}
+
+func NewAcademicYearHandler(svc *services.AcademicYearService) *AcademicYearHandler {
+	return &AcademicYearHandler{svc: svc}
+}
+
+func (h *AcademicYearHandler) Routes(r chi.Router) {
+	r.Get("/academic-years", h.GetAll)
+	r.Get("/academic-years/current", h.GetCurrent)
+	r.Route("/roo/academic-years", func(r chi.Router) {
+		r.Use(middleware.RequirePermission(access.AcademicYearsManage))
+		r.Post("/", h.Create)
+		r.Put("/{id}/current", h.SetCurrent)
+	})
+}
+
+type academicYearRequest struct {
+	Name      string `json:"name" example:"2026/2027"`
+	StartDate string `json:"start_date" example:"2026-09-01"`
+	EndDate   string `json:"end_date" example:"2027-08-31"`
+}
+
+// GetAll godoc
+// @Summary      Учебные годы
+// @Description  Все учебные годы, новые сверху. ID года передаётся в ?academic_year= для просмотра архива.
+// @Tags         AcademicYears
+// @Produce      json
+// @Success      200 {array} models.AcademicYear
+// @Failure      500 {object} helpers.ErrorResponse
+// @Security     BearerAuth
+// @Router       /academic-years [get]
+func (h *AcademicYearHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	list, err := h.svc.GetAll(r.Context())
+	if err != nil {
+		helpers.Error(w, http.StatusInternalServerError, "failed to load academic years")
+		return
+	}
+	helpers.JSON(w, http.StatusOK, list)
+}
+
+// GetCurrent godoc
+// @Summary      Текущий учебный год
+// @Tags         AcademicYears
+// @Produce      json
+// @Success      200 {object} models.AcademicYear
+// @Failure      404 {object} helpers.ErrorResponse
+// @Failure      500 {object} helpers.ErrorResponse
+// @Security     BearerAuth
+// @Router       /academic-years/current [get]
+func (h *AcademicYearHandler) GetCurrent(w http.ResponseWriter, r *http.Request) {
+	y, err := h.svc.GetCurrent(r.Context())
+	if err != nil {
+		if errors.Is(err, repository.ErrNoCurrentYear) {
+			helpers.Error(w, http.StatusNotFound, err.Error())
+			return
+		}
+		helpers.Error(w, http.StatusInternalServerError, "failed to load academic year")
+		return
+	}
+	helpers.JSON(w, http.StatusOK, y)
+}
+
+// Create godoc
+// @Summary      Добавить учебный год
+// @Description  Только РОО. Новый год не становится текущим автоматически.
+// @Tags         AcademicYears
+// @Accept       json
+// @Produce      json
+// @Param        data body academicYearRequest true "Учебный год (даты в формате YYYY-MM-DD)"
+// @Success      201 {object} models.AcademicYear
+// @Failure      400 {object} helpers.ErrorResponse
+// @Failure      409 {object} helpers.ErrorResponse
+// @Failure      500 {object} helpers.ErrorResponse
+// @Security     BearerAuth
+// @Router       /roo/academic-years [post]
+func (h *AcademicYearHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req academicYearRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Name == "" {
+		helpers.Error(w, http.StatusBadRequest, "name, start_date and end_date required")
+		return
+	}
+	start, err := time.Parse(time.DateOnly, req.StartDate)
+	if err != nil {
+		helpers.Error(w, http.StatusBadRequest, "invalid start_date")
+		return
+	}
+	end, err := time.Parse(time.DateOnly, req.EndDate)
+	if err != nil {
+		helpers.Error(w, http.StatusBadRequest, "invalid end_date")
+		return
+	}
+
+	y := models.AcademicYear{Name: req.Name, StartDate: start, EndDate: end}
+	if err := h.svc.Create(r.Context(), &y); err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidYearDates):
+			helpers.Error(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, repository.ErrAcademicYearExists):
+			helpers.Error(w, http.StatusConflict, err.Error())
+		default:
+			helpers.Error(w, http.StatusInternalServerError, "failed to create academic year")
+		}
+		return
+	}
+	helpers.JSON(w, http.StatusCreated, y)
+}
+
+// SetCurrent godoc
+// @Summary      Сделать учебный год текущим
+// @Description  Только РОО. Классы и ученики по умолчанию показываются за текущий год; остальные годы доступны через ?academic_year=.
+// @Tags         AcademicYears
+// @Produce      json
+// @Param        id path int true "ID учебного года"
+// @Success      200 {object} map[string]string
+// @Failure      404 {object} helpers.ErrorResponse
+// @Failure      500 {object} helpers.ErrorResponse
+// @Security     BearerAuth
+// @Router       /roo/academic-years/{id}/current [put]
+func (h *AcademicYearHandler) SetCurrent(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.Atoi(chi.URLParam(r, "id"))
+	if err := h.svc.SetCurrent(r.Context(), id); err != nil {
+		if errors.Is(err, repository.ErrAcademicYearNotFound) {
+			helpers.Error(w, http.StatusNotFound, err.Error())
+			return
+		}
+		helpers.Error(w, http.StatusInternalServerError, "failed to set current academic year")
+		return
+	}
+	helpers.JSON(w, http.StatusOK, map[string]string{"status": "current"})
+}
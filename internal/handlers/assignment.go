@@ -0,0 +1,197 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"eduBase/internal/access"
+	"eduBase/internal/helpers"
+	"eduBase/internal/middleware"
+	"eduBase/internal/models"
+	"eduBase/internal/repository"
+	"eduBase/internal/services"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// AssignmentHandler — назначения учителей на предметы в классах
+type AssignmentHandler struct {
+	svc *services.AssignmentService
+}
+
+func NewAssignmentHandler(svc *services.AssignmentService) *AssignmentHandler {
+	return &AssignmentHandler{svc: svc}
+}
+
+func (h *AssignmentHandler) Routes(r chi.Router) {
+	r.Route("/assignments", func(r chi.Router) {
+		r.With(middleware.RequirePermission(access.ClassesRead)).Get("/", h.GetAll)
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.RequirePermission(access.ClassesWrite))
+			r.Post("/", h.Create)
+			r.Put("/{id}", h.Update)
+			r.Delete("/{id}", h.Delete)
+		})
+	})
+}
+
+type assignmentRequest struct {
+	ClassID      int     `json:"class_id" example:"12"`
+	SubjectID    int     `json:"subject_id" example:"3"`
+	StaffID      *int    `json:"staff_id,omitempty"` // без staff_id — вакансия
+	HoursPerWeek float64 `json:"hours_per_week" example:"4"`
+}
+
+func writeAssignmentError(w http.ResponseWriter, err error, fallback string) {
+	switch {
+	case errors.Is(err, services.ErrInvalidHours),
+		errors.Is(err, services.ErrClassNotInSchool),
+		errors.Is(err, services.ErrStaffNotInSchool),
+		errors.Is(err, repository.ErrSubjectNotFound):
+		helpers.Error(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, repository.ErrAssignmentNotFound):
+		helpers.Error(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, repository.ErrAssignmentExists):
+		helpers.Error(w, http.StatusConflict, err.Error())
+	default:
+		helpers.Error(w, http.StatusInternalServerError, fallback)
+	}
+}
+
+// GetAll godoc
+// @Summary      Назначения учителей
+// @Description  Кто какой предмет ведёт в каком классе и сколько часов в неделю; записи без staff_id — вакансии.
+// @Description  РОО и инспектор — все школы или school_id, школа — свои, учитель — только свой класс. По умолчанию — текущий учебный год.
+// @Tags         Assignments
+// @Produce      json
+// @Param        school_id query int false "ID школы (для РОО и инспектора)"
+// @Param        class_id query int false "ID класса"
+// @Param        staff_id query int false "ID сотрудника"
+// @Param        subject_id query int false "ID предмета"
+// @Param        academic_year query int false "ID учебного года (по умолчанию текущий)"
+// @Success      200 {array} models.TeachingAssignment
+// @Failure      400 {object} helpers.ErrorResponse
+// @Failure      500 {object} helpers.ErrorResponse
+// @Security     BearerAuth
+// @Router       /assignments [get]
+func (h *AssignmentHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	p := access.FromContext(r.Context())
+
+	q := r.URL.Query()
+	var f repository.AssignmentFilter
+	var err error
+	for name, dst := range map[string]**int{
+		"school_id": &f.SchoolID, "class_id": &f.ClassID, "staff_id": &f.StaffID,
+		"subject_id": &f.SubjectID, "academic_year": &f.AcademicYearID,
+	} {
+		if *dst, err = queryInt(q, name); err != nil {
+			helpers.Error(w, http.StatusBadRequest, "invalid "+name)
+			return
+		}
+	}
+	if schoolID := p.SchoolScope(); schoolID != nil {
+		f.SchoolID = schoolID
+	}
+	// учитель видит только свой класс
+	if classID := p.ClassScope(); classID != nil {
+		f.ClassID = classID
+	}
+
+	list, err := h.svc.GetAll(ctx, f)
+	if err != nil {
+		helpers.Error(w, http.StatusInternalServerError, "failed to get assignments")
+		return
+	}
+	helpers.JSON(w, http.StatusOK, list)
+}
+
+// Create godoc
+// @Summary      Назначить учителя на предмет в классе
+// @Description  Школа — в свои классы, РОО — в любые. Без staff_id создаётся вакансия.
+// @Tags         Assignments
+// @Accept       json
+// @Produce      json
+// @Param        data body assignmentRequest true "Назначение"
+// @Success      201 {object} models.TeachingAssignment
+// @Failure      400 {object} helpers.ErrorResponse
+// @Failure      409 {object} helpers.ErrorResponse
+// @Failure      500 {object} helpers.ErrorResponse
+// @Security     BearerAuth
+// @Router       /assignments [post]
+func (h *AssignmentHandler) Create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	p := access.FromContext(r.Context())
+
+	var req assignmentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.ClassID == 0 || req.SubjectID == 0 {
+		helpers.Error(w, http.StatusBadRequest, "class_id and subject_id required")
+		return
+	}
+
+	a := models.TeachingAssignment{
+		ClassID: req.ClassID, SubjectID: req.SubjectID, StaffID: req.StaffID, HoursPerWeek: req.HoursPerWeek,
+	}
+	if err := h.svc.Create(ctx, &a, p.SchoolScope()); err != nil {
+		writeAssignmentError(w, err, "failed to create assignment")
+		return
+	}
+	helpers.JSON(w, http.StatusCreated, a)
+}
+
+// Update godoc
+// @Summary      Изменить назначение
+// @Description  Меняет учителя (без staff_id — вакансия) и часы; класс и предмет не меняются
+// @Tags         Assignments
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "ID назначения"
+// @Param        data body assignmentRequest true "Учитель и часы"
+// @Success      200 {object} models.TeachingAssignment
+// @Failure      400 {object} helpers.ErrorResponse
+// @Failure      404 {object} helpers.ErrorResponse
+// @Failure      409 {object} helpers.ErrorResponse
+// @Failure      500 {object} helpers.ErrorResponse
+// @Security     BearerAuth
+// @Router       /assignments/{id} [put]
+func (h *AssignmentHandler) Update(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	p := access.FromContext(r.Context())
+
+	id, _ := strconv.Atoi(chi.URLParam(r, "id"))
+	var req assignmentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		helpers.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	a, err := h.svc.Update(ctx, id, req.StaffID, req.HoursPerWeek, p.SchoolScope())
+	if err != nil {
+		writeAssignmentError(w, err, "failed to update assignment")
+		return
+	}
+	helpers.JSON(w, http.StatusOK, a)
+}
+
+// Delete godoc
+// @Summary      Снять назначение
+// @Tags         Assignments
+// @Param        id path int true "ID назначения"
+// @Success      200 {object} map[string]string
+// @Failure      404 {object} helpers.ErrorResponse
+// @Failure      500 {object} helpers.ErrorResponse
+// @Security     BearerAuth
+// @Router       /assignments/{id} [delete]
+func (h *AssignmentHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	p := access.FromContext(r.Context())
+
+	id, _ := strconv.Atoi(chi.URLParam(r, "id"))
+	if err := h.svc.Delete(ctx, id, p.SchoolScope()); err != nil {
+		writeAssignmentError(w, err, "failed to delete assignment")
+		return
+	}
+	helpers.JSON(w, http.StatusOK, map[string]string{"status": "deleted"})
+}
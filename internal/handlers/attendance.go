@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"eduBase/internal/access"
+	"eduBase/internal/helpers"
+	"eduBase/internal/middleware"
+	"eduBase/internal/models"
+	"eduBase/internal/repository"
+	"eduBase/internal/services"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// AttendanceHandler — посещаемость классов
+type AttendanceHandler struct {
+	svc *services.AttendanceService
+	pd  *services.PDAccessService
+}
+
+func NewAttendanceHandler(svc *services.AttendanceService, pd *services.PDAccessService) *AttendanceHandler {
+	return &AttendanceHandler{svc: svc, pd: pd}
+}
+
+func (h *AttendanceHandler) Routes(r chi.Router) {
+	r.Route("/attendance", func(r chi.Router) {
+		r.With(middleware.RequirePermission(access.ClassesRead)).Get("/", h.GetByClass)
+		r.With(middleware.RequirePermission(access.AttendanceWrite)).Put("/", h.Mark)
+	})
+}
+
+type markAttendanceRequest struct {
+	ClassID int                     `json:"class_id" example:"12"`
+	Date    string                  `json:"date" example:"2025-11-17"` // YYYY-MM-DD, по умолчанию сегодня
+	Records []models.AttendanceMark `json:"records"`
+}
+
+// parseDateParam разбирает дату YYYY-MM-DD; пустая строка — сегодня
+func parseDateParam(v string) (time.Time, error) {
+	if v == "" {
+		return time.Parse(time.DateOnly, time.Now().Format(time.DateOnly))
+	}
+	return time.Parse(time.DateOnly, v)
+}
+
+// attendanceAccessFilter — параметры просмотра посещаемости для журнала доступа к персональным данным
+type attendanceAccessFilter struct {
+	ClassID int    `json:"class_id"`
+	Date    string `json:"date"`
+}
+
+func attendanceStudentIDs(list []models.AttendanceRecord) []int {
+	ids := make([]int, 0, len(list))
+	for _, a := range list {
+		ids = append(ids, a.StudentID)
+	}
+	return ids
+}
+
+// attendanceClass загружает класс и проверяет доступ (учитель — только свой класс)
+func (h *AttendanceHandler) attendanceClass(w http.ResponseWriter, r *http.Request, classID int) (*models.Class, bool) {
+	p := access.FromContext(r.Context())
+
+	c, err := h.svc.GetClass(r.Context(), classID)
+	if err != nil {
+		if errors.Is(err, repository.ErrClassNotFound) {
+			helpers.Error(w, http.StatusNotFound, err.Error())
+			return nil, false
+		}
+		helpers.Error(w, http.StatusInternalServerError, "failed to get class")
+		return nil, false
+	}
+	if !p.CanAccessClass(c.SchoolID, c.ID) {
+		helpers.Error(w, http.StatusForbidden, "access denied")
+		return nil, false
+	}
+	return c, true
+}
+
+// GetByClass godoc
+// @Summary Посещаемость класса за день
+// @Description Все обучающиеся класса с отметками; неотмеченные — без status. Учитель — только свой класс.
+// @Description Просмотр фиксируется в журнале доступа к персональным данным
+// @Tags Attendance
+// @Produce json
+// @Param class_id query int true "ID класса"
+// @Param date query string false "Дата YYYY-MM-DD (по умолчанию сегодня)"
+// @Security BearerAuth
+// @Success 200 {object} models.ClassAttendance
+// @Failure 400 {object} helpers.ErrorResponse
+// @Failure 403 {object} helpers.ErrorResponse
+// @Failure 404 {object} helpers.ErrorResponse
+// @Failure 500 {object} helpers.ErrorResponse
+// @Router /attendance [get]
+func (h *AttendanceHandler) GetByClass(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	classID, err := queryInt(r.URL.Query(), "class_id")
+	if err != nil || classID == nil {
+		helpers.Error(w, http.StatusBadRequest, "class_id required")
+		return
+	}
+	date, err := parseDateParam(r.URL.Query().Get("date"))
+	if err != nil {
+		helpers.Error(w, http.StatusBadRequest, "invalid date")
+		return
+	}
+	c, ok := h.attendanceClass(w, r, *classID)
+	if !ok {
+		return
+	}
+
+	res, err := h.svc.GetByClass(ctx, c.ID, date)
+	if err != nil {
+		helpers.Error(w, http.StatusInternalServerError, "failed to get attendance")
+		return
+	}
+	filter := attendanceAccessFilter{ClassID: res.ClassID, Date: res.Date}
+	if err := h.pd.Log(ctx, services.PDActionList, attendanceStudentIDs(res.Records), filter); err != nil {
+		helpers.Error(w, http.StatusInternalServerError, "failed to log access")
+		return
+	}
+	helpers.JSON(w, http.StatusOK, res)
+}
+
+// Mark godoc
+// @Summary Отметить посещаемость класса
+// @Description Пакетная отправка отметок за день по всему классу: present, sick, excused, unexcused, late.
+// @Description Сохраняются все отметки или ни одной; повторная отправка исправляет ранее поставленные. Учитель — только свой класс.
+// @Description Дата — в пределах текущего учебного года и не позже сегодняшней; каждый ученик — не больше одного раза.
+// @Tags Attendance
+// @Accept json
+// @Produce json
+// @Param data body markAttendanceRequest true "Отметки"
+// @Security BearerAuth
+// @Success 200 {object} models.ClassAttendance
+// @Failure 400 {object} helpers.ErrorResponse
+// @Failure 403 {object} helpers.ErrorResponse
+// @Failure 404 {object} helpers.ErrorResponse
+// @Failure 500 {object} helpers.ErrorResponse
+// @Router /attendance [put]
+func (h *AttendanceHandler) Mark(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req markAttendanceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.ClassID == 0 || len(req.Records) == 0 {
+		helpers.Error(w, http.StatusBadRequest, "class_id and records required")
+		return
+	}
+	date, err := parseDateParam(req.Date)
+	if err != nil {
+		helpers.Error(w, http.StatusBadRequest, "invalid date")
+		return
+	}
+	c, ok := h.attendanceClass(w, r, req.ClassID)
+	if !ok {
+		return
+	}
+
+	res, err := h.svc.Mark(ctx, c, date, req.Records)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidAttendanceStatus),
+			errors.Is(err, services.ErrAttendanceStudent),
+			errors.Is(err, services.ErrAttendanceFutureDate),
+			errors.Is(err, services.ErrAttendanceOutsideYear),
+			errors.Is(err, services.ErrAttendanceDuplicate),
+			errors.Is(err, services.ErrClassArchived):
+			helpers.Error(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, repository.ErrNoCurrentYear):
+			helpers.Error(w, http.StatusConflict, err.Error())
+		default:
+			helpers.Error(w, http.StatusInternalServerError, "failed to mark attendance")
+		}
+		return
+	}
+	helpers.JSON(w, http.StatusOK, res)
+}
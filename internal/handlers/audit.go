@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"eduBase/internal/access"
+	"eduBase/internal/helpers"
+	"eduBase/internal/middleware"
+	"eduBase/internal/repository"
+	"eduBase/internal/services"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// AuditHandler — просмотр журнала изменений
+type AuditHandler struct {
+	svc *services.AuditService
+}
+
+func NewAuditHandler(svc *services.AuditService) *AuditHandler {
+	return &AuditHandler{svc: svc}
+}
+
+func (h *AuditHandler) Routes(r chi.Router) {
+	r.With(middleware.RequirePermission(access.AuditRead)).Get("/roo/audit", h.GetAll)
+}
+
+// GetAll godoc
+// @Summary      Журнал изменений
+// @Description  Кто, когда и что изменил. Для update хранятся только изменившиеся поля (before/after). РОО и инспектор.
+// @Tags         Audit
+// @Produce      json
+// @Param        school_id   query int    false "ID школы"
+// @Param        entity_type query string false "Тип сущности" Enums(student, staff, class, school, user)
+// @Param        entity_id   query int    false "ID сущности"
+// @Param        user_id     query int    false "Кто изменил"
+// @Param        from        query string false "С даты (YYYY-MM-DD или RFC3339)"
+// @Param        to          query string false "По дату включительно (YYYY-MM-DD или RFC3339)"
+// @Param        limit       query int    false "Сколько записей (по умолчанию 100, максимум 1000)"
+// @Param        offset      query int    false "Смещение"
+// @Success      200 {array} models.AuditEntry
+// @Failure      400 {object} helpers.ErrorResponse
+// @Failure      500 {object} helpers.ErrorResponse
+// @Security     BearerAuth
+// @Router       /roo/audit [get]
+func (h *AuditHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	f := repository.AuditFilter{EntityType: q.Get("entity_type")}
+
+	var err error
+	if f.SchoolID, err = queryInt(q, "school_id"); err != nil {
+		helpers.Error(w, http.StatusBadRequest, "invalid school_id")
+		return
+	}
+	if f.EntityID, err = queryInt(q, "entity_id"); err != nil {
+		helpers.Error(w, http.StatusBadRequest, "invalid entity_id")
+		return
+	}
+	if f.UserID, err = queryInt(q, "user_id"); err != nil {
+		helpers.Error(w, http.StatusBadRequest, "invalid user_id")
+		return
+	}
+	if f.From, err = parseTimeParam(q.Get("from"), false); err != nil {
+		helpers.Error(w, http.StatusBadRequest, "invalid from")
+		return
+	}
+	if f.To, err = parseTimeParam(q.Get("to"), true); err != nil {
+		helpers.Error(w, http.StatusBadRequest, "invalid to")
+		return
+	}
+	if v := q.Get("limit"); v != "" {
+		if f.Limit, err = strconv.Atoi(v); err != nil {
+			helpers.Error(w, http.StatusBadRequest, "invalid limit")
+			return
+		}
+	}
+	if v := q.Get("offset"); v != "" {
+		if f.Offset, err = strconv.Atoi(v); err != nil {
+			helpers.Error(w, http.StatusBadRequest, "invalid offset")
+			return
+		}
+	}
+
+	list, err := h.svc.GetAll(r.Context(), f)
+	if err != nil {
+		helpers.Error(w, http.StatusInternalServerError, "failed to load audit log")
+		return
+	}
+	helpers.JSON(w, http.StatusOK, list)
+}
+
+// queryInt читает необязательный целый параметр query; nil — параметр не задан
+func queryInt(q url.Values, name string) (*int, error) {
+	v := q.Get(name)
+	if v == "" {
+		return nil, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return nil, err
+	}
+	return &n, nil
+}
+
+// parseTimeParam разбирает дату из query: YYYY-MM-DD или RFC3339.
+// Для верхней границы дата без времени означает "весь этот день включительно".
+func parseTimeParam(v string, upper bool) (*time.Time, error) {
+	if v == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return &t, nil
+	}
+	t, err := time.Parse(time.DateOnly, v)
+	if err != nil {
+		return nil, err
+	}
+	if upper {
+		t = t.AddDate(0, 0, 1)
+	}
+	return &t, nil
+}
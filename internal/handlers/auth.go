@@ -1,9 +1,10 @@
 package handlers
 
 import (
-	"context"
 	"encoding/json"
+	"errors"
 	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/jwtauth/v5"
 	"net/http"
 
 	"eduBase/internal/helpers"
@@ -18,8 +19,16 @@ func NewAuthHandler(svc *services.AuthService) *AuthHandler {
 	return &AuthHandler{svc: svc}
 }
 
+// Routes — публичные маршруты (без access-токена)
 func (h *AuthHandler) Routes(r chi.Router) {
 	r.Post("/auth/login", h.Login)
+	r.Post("/auth/refresh", h.Refresh)
+}
+
+// ProtectedRoutes — маршруты, требующие валидной сессии
+func (h *AuthHandler) ProtectedRoutes(r chi.Router) {
+	r.Post("/auth/logout", h.Logout)
+	r.Put("/auth/me/password", h.ChangePassword)
 }
 
 type loginRequest struct {
@@ -27,15 +36,41 @@ type loginRequest struct {
 	Password string `json:"password"`
 }
 
+type changePasswordRequest struct {
+	OldPassword string `json:"old_password"`
+	NewPassword string `json:"new_password"`
+}
+
+type refreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
 // LoginResponse — структура ответа при успешном входе.
-// @Description JWT токен для дальнейших запросов
+// @Description JWT токен для дальнейших запросов и refresh-токен для его обновления
 type LoginResponse struct {
-	Token string `json:"token" example:"eyJhbGciOiJIUzI1NiIs..."` // JWT-токен
+	Token        string `json:"token" example:"eyJhbGciOiJIUzI1NiIs..."`         // JWT access-токен
+	RefreshToken string `json:"refresh_token" example:"q1w2e3r4t5y6u7i8o9p0..."` // refresh-токен (одноразовый)
+	ExpiresIn    int64  `json:"expires_in" example:"900"`                        // время жизни access-токена, сек
+	// true — до смены пароля через /auth/me/password остальные методы недоступны
+	MustChangePassword bool `json:"must_change_password" example:"false"`
+}
+
+func newLoginResponse(p *services.TokenPair) LoginResponse {
+	return LoginResponse{
+		Token:              p.AccessToken,
+		RefreshToken:       p.RefreshToken,
+		ExpiresIn:          p.ExpiresIn,
+		MustChangePassword: p.MustChangePassword,
+	}
+}
+
+func sessionMeta(r *http.Request) services.SessionMeta {
+	return services.SessionMeta{UserAgent: r.UserAgent(), IP: helpers.ClientIP(r)}
 }
 
 // Login godoc
 // @Summary Авторизация пользователя
-// @Description Авторизация для ROO и School, возвращает JWT токен.
+// @Description Авторизация для всех ролей, возвращает access- и refresh-токены.
 // @Tags Auth
 // @Accept json
 // @Produce json
@@ -43,6 +78,7 @@ type LoginResponse struct {
 // @Success 200 {object} LoginResponse
 // @Failure 400 {object} helpers.ErrorResponse
 // @Failure 401 {object} helpers.ErrorResponse
+// @Failure 403 {object} helpers.ErrorResponse "школа сотрудника в корзине"
 // @Router /auth/login [post]
 func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 	var req loginRequest
@@ -51,11 +87,122 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	token, err := h.svc.Login(context.Background(), req.Email, req.Password)
+	pair, err := h.svc.Login(r.Context(), req.Email, req.Password, sessionMeta(r))
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidCredentials) {
+			helpers.Error(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+		if errors.Is(err, services.ErrAccountDisabled) {
+			helpers.Error(w, http.StatusForbidden, err.Error())
+			return
+		}
+		helpers.Error(w, http.StatusInternalServerError, "failed to login")
+		return
+	}
+
+	helpers.JSON(w, http.StatusOK, newLoginResponse(pair))
+}
+
+// Refresh godoc
+// @Summary Обновление токенов
+// @Description Обменивает refresh-токен на новую пару токенов. Старый refresh-токен становится недействительным.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param input body refreshRequest true "Refresh-токен"
+// @Success 200 {object} LoginResponse
+// @Failure 400 {object} helpers.ErrorResponse
+// @Failure 401 {object} helpers.ErrorResponse
+// @Failure 403 {object} helpers.ErrorResponse "школа сотрудника в корзине"
+// @Router /auth/refresh [post]
+func (h *AuthHandler) Refresh(w http.ResponseWriter, r *http.Request) {
+	var req refreshRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.RefreshToken == "" {
+		helpers.Error(w, http.StatusBadRequest, "refresh_token required")
+		return
+	}
+
+	pair, err := h.svc.Refresh(r.Context(), req.RefreshToken, sessionMeta(r))
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidRefreshToken) {
+			helpers.Error(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+		if errors.Is(err, services.ErrAccountDisabled) {
+			helpers.Error(w, http.StatusForbidden, err.Error())
+			return
+		}
+		helpers.Error(w, http.StatusInternalServerError, "failed to refresh token")
+		return
+	}
+
+	helpers.JSON(w, http.StatusOK, newLoginResponse(pair))
+}
+
+// Logout godoc
+// @Summary Выход из системы
+// @Description Отзывает текущую сессию. С all=true — все сессии пользователя на всех устройствах.
+// @Tags Auth
+// @Produce json
+// @Param all query bool false "Завершить все сессии"
+// @Success 200 {object} map[string]string
+// @Failure 401 {object} helpers.ErrorResponse
+// @Failure 500 {object} helpers.ErrorResponse
+// @Security BearerAuth
+// @Router /auth/logout [post]
+func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	_, claims, _ := jwtauth.FromContext(r.Context())
+
+	var err error
+	if r.URL.Query().Get("all") == "true" {
+		err = h.svc.RevokeAllSessions(ctx, int(claims["user_id"].(float64)))
+	} else {
+		err = h.svc.Logout(ctx, int64(claims["sid"].(float64)))
+	}
+	if err != nil {
+		helpers.Error(w, http.StatusInternalServerError, "failed to logout")
+		return
+	}
+	helpers.JSON(w, http.StatusOK, map[string]string{"status": "logged out"})
+}
+
+// ChangePassword godoc
+// @Summary Смена пароля текущего пользователя
+// @Description Меняет пароль, завершает все остальные сессии и возвращает новую пару токенов.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param input body changePasswordRequest true "Старый и новый пароль"
+// @Success 200 {object} LoginResponse
+// @Failure 400 {object} helpers.ErrorResponse
+// @Failure 401 {object} helpers.ErrorResponse
+// @Failure 500 {object} helpers.ErrorResponse
+// @Security BearerAuth
+// @Router /auth/me/password [put]
+func (h *AuthHandler) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	_, claims, _ := jwtauth.FromContext(r.Context())
+	userID := int(claims["user_id"].(float64))
+
+	var req changePasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		helpers.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	pair, err := h.svc.ChangePassword(r.Context(), userID, req.OldPassword, req.NewPassword, sessionMeta(r))
 	if err != nil {
-		helpers.Error(w, http.StatusUnauthorized, err.Error())
+		switch {
+		case errors.Is(err, services.ErrWeakPassword), errors.Is(err, services.ErrSamePassword):
+			helpers.Error(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, services.ErrWrongPassword):
+			helpers.Error(w, http.StatusUnauthorized, err.Error())
+		default:
+			helpers.Error(w, http.StatusInternalServerError, "failed to change password")
+		}
 		return
 	}
 
-	helpers.JSON(w, http.StatusOK, map[string]string{"token": token})
+	helpers.JSON(w, http.StatusOK, newLoginResponse(pair))
 }
@@ -1,80 +1,103 @@
 package handlers
 
 import (
-	"context"
 	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
 	"strconv"
 
+	"eduBase/internal/access"
 	"eduBase/internal/helpers"
+	"eduBase/internal/middleware"
 	"eduBase/internal/models"
 	"eduBase/internal/repository"
 	"eduBase/internal/services"
 
 	"github.com/go-chi/chi/v5"
-	"github.com/go-chi/jwtauth/v5"
 )
 
 // ClassHandler — обработчик классов
 type ClassHandler struct {
-	svc *services.ClassService
+	svc       *services.ClassService
+	timetable *services.TimetableService
 }
 
-func NewClassHandler(svc *services.ClassService) *ClassHandler {
-	return &ClassHandler{svc: svc}
+func NewClassHandler(svc *services.ClassService, timetable *services.TimetableService) *ClassHandler {
+	return &ClassHandler{svc: svc, timetable: timetable}
 }
 
 func (h *ClassHandler) Routes(r chi.Router) {
 	r.Route("/classes", func(r chi.Router) {
-		r.Get("/", h.GetClasses)
-		r.Get("/{id}", h.GetByID)
-		r.Post("/", h.Create)
-		r.Put("/{id}", h.Update)
-		r.Delete("/{id}", h.Delete)
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.RequirePermission(access.ClassesRead))
+			r.Get("/", h.GetClasses)
+			r.Get("/{id}", h.GetByID)
+			r.Get("/{id}/timetable", h.GetTimetable)
+			r.Get("/export/xlsx", h.Export)
+		})
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.RequirePermission(access.ClassesWrite))
+			r.Post("/", h.Create)
+			r.Put("/{id}", h.Update)
+			r.Delete("/{id}", h.Delete)
+			r.Post("/{id}/timetable", h.CreateLesson)
+			r.Put("/{id}/timetable/{entryID}", h.UpdateLesson)
+			r.Delete("/{id}/timetable/{entryID}", h.DeleteLesson)
+		})
 	})
 }
 
 // GetClasses godoc
 // @Summary Получить список классов
-// @Description ROO — все классы, School — только свои
+// @Description РОО — все классы, школа — только свои, учитель — только свой класс. По умолчанию — текущий учебный год.
 // @Tags Classes
 // @Produce json
+// @Param academic_year query int false "ID учебного года (по умолчанию текущий)"
 // @Success 200 {array} models.Class
+// @Failure 400 {object} helpers.ErrorResponse
 // @Failure 403 {object} helpers.ErrorResponse
 // @Failure 500 {object} helpers.ErrorResponse
 // @Security BearerAuth
 // @Router /classes [get]
 func (h *ClassHandler) GetClasses(w http.ResponseWriter, r *http.Request) {
-	ctx := context.Background()
-	_, claims, _ := jwtauth.FromContext(r.Context())
-	role := claims["role"].(string)
-	userID := int(claims["user_id"].(float64))
+	ctx := r.Context()
+	p := access.FromContext(r.Context())
 
-	var res []models.Class
-	var err error
-
-	if role == "roo" {
-		res, err = h.svc.GetAll(ctx)
-	} else if role == "school" {
-		schoolRepo := repository.NewSchoolRepository(h.svc.RepoDB())
-		school, errGet := schoolRepo.GetByUserID(ctx, userID)
-		if errGet != nil {
-			helpers.Error(w, http.StatusForbidden, "school not found")
-			return
-		}
-		res, err = h.svc.GetBySchool(ctx, school.ID)
+	yearID, err := queryInt(r.URL.Query(), "academic_year")
+	if err != nil {
+		helpers.Error(w, http.StatusBadRequest, "invalid academic_year")
+		return
 	}
 
+	var res []models.Class
+	if schoolID := p.SchoolScope(); schoolID == nil {
+		res, err = h.svc.GetAll(ctx, yearID)
+	} else {
+		res, err = h.svc.GetBySchool(ctx, *schoolID, yearID)
+	}
 	if err != nil {
 		helpers.Error(w, http.StatusInternalServerError, "failed to get classes")
 		return
 	}
+
+	// учитель видит только свой класс
+	if classID := p.ClassScope(); classID != nil {
+		own := make([]models.Class, 0, 1)
+		for _, c := range res {
+			if c.ID == *classID {
+				own = append(own, c)
+			}
+		}
+		res = own
+	}
+
 	helpers.JSON(w, http.StatusOK, res)
 }
 
 // Create godoc
 // @Summary Создать новый класс
-// @Description School создаёт свой класс
+// @Description Школа создаёт свой класс; РОО указывает school_id в теле. academic_year_id по умолчанию — текущий учебный год.
 // @Tags Classes
 // @Accept json
 // @Produce json
@@ -86,10 +109,8 @@ func (h *ClassHandler) GetClasses(w http.ResponseWriter, r *http.Request) {
 // @Security BearerAuth
 // @Router /classes [post]
 func (h *ClassHandler) Create(w http.ResponseWriter, r *http.Request) {
-	ctx := context.Background()
-	_, claims, _ := jwtauth.FromContext(r.Context())
-	role := claims["role"].(string)
-	userID := int(claims["user_id"].(float64))
+	ctx := r.Context()
+	p := access.FromContext(r.Context())
 
 	var c models.Class
 	if err := json.NewDecoder(r.Body).Decode(&c); err != nil {
@@ -97,18 +118,19 @@ func (h *ClassHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if role == "school" {
-		schoolRepo := repository.NewSchoolRepository(h.svc.RepoDB())
-		school, err := schoolRepo.GetByUserID(ctx, userID)
-		if err != nil {
-			helpers.Error(w, http.StatusForbidden, "school not found")
-			return
-		}
-		c.SchoolID = school.ID
+	if schoolID := p.SchoolScope(); schoolID != nil {
+		c.SchoolID = *schoolID
 	}
 
 	if err := h.svc.Create(ctx, &c); err != nil {
-		helpers.Error(w, http.StatusInternalServerError, "failed to create class")
+		switch {
+		case errors.Is(err, repository.ErrAcademicYearNotFound), errors.Is(err, services.ErrStaffNotInSchool):
+			helpers.Error(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, repository.ErrNoCurrentYear):
+			helpers.Error(w, http.StatusConflict, err.Error())
+		default:
+			helpers.Error(w, http.StatusInternalServerError, "failed to create class")
+		}
 		return
 	}
 	helpers.JSON(w, http.StatusCreated, c)
@@ -116,7 +138,8 @@ func (h *ClassHandler) Create(w http.ResponseWriter, r *http.Request) {
 
 // Update godoc
 // @Summary Обновить класс
-// @Description ROO — может обновить любой, School — только свой
+// @Description РОО — может обновить любой, школа — только свой. class_teacher_id — классный руководитель из сотрудников школы:
+// @Description если поле не передано, руководитель не меняется; null — снять руководителя
 // @Tags Classes
 // @Accept json
 // @Produce json
@@ -130,30 +153,31 @@ func (h *ClassHandler) Create(w http.ResponseWriter, r *http.Request) {
 // @Security BearerAuth
 // @Router /classes/{id} [put]
 func (h *ClassHandler) Update(w http.ResponseWriter, r *http.Request) {
-	ctx := context.Background()
-	_, claims, _ := jwtauth.FromContext(r.Context())
-	role := claims["role"].(string)
-	userID := int(claims["user_id"].(float64))
+	ctx := r.Context()
+	p := access.FromContext(r.Context())
 
 	id, _ := strconv.Atoi(chi.URLParam(r, "id"))
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		helpers.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
 	var c models.Class
-	if err := json.NewDecoder(r.Body).Decode(&c); err != nil {
+	// классный руководитель меняется, только если поле передано (null — снять)
+	var fields struct {
+		ClassTeacherID json.RawMessage `json:"class_teacher_id"`
+	}
+	if json.Unmarshal(body, &c) != nil || json.Unmarshal(body, &fields) != nil {
 		helpers.Error(w, http.StatusBadRequest, "invalid request")
 		return
 	}
 
-	if role == "school" {
-		schoolRepo := repository.NewSchoolRepository(h.svc.RepoDB())
-		school, err := schoolRepo.GetByUserID(ctx, userID)
-		if err != nil {
-			helpers.Error(w, http.StatusForbidden, "school not found")
+	ok, err := h.svc.Update(ctx, id, &c, fields.ClassTeacherID != nil, p.SchoolScope())
+	if err != nil {
+		if errors.Is(err, services.ErrStaffNotInSchool) {
+			helpers.Error(w, http.StatusBadRequest, err.Error())
 			return
 		}
-		c.SchoolID = school.ID
-	}
-
-	ok, err := h.svc.Update(ctx, id, &c, role)
-	if err != nil {
 		helpers.Error(w, http.StatusInternalServerError, "failed to update class")
 		return
 	}
@@ -167,7 +191,7 @@ func (h *ClassHandler) Update(w http.ResponseWriter, r *http.Request) {
 
 // Delete godoc
 // @Summary Удалить класс
-// @Description School — только свои, ROO — любые
+// @Description Переносит класс с учениками в корзину (/trash). Школа — только свои, РОО — любые
 // @Tags Classes
 // @Param id path int true "ID класса"
 // @Success 200 {object} map[string]string
@@ -177,34 +201,26 @@ func (h *ClassHandler) Update(w http.ResponseWriter, r *http.Request) {
 // @Security BearerAuth
 // @Router /classes/{id} [delete]
 func (h *ClassHandler) Delete(w http.ResponseWriter, r *http.Request) {
-	ctx := context.Background()
-	_, claims, _ := jwtauth.FromContext(r.Context())
-	role := claims["role"].(string)
-	userID := int(claims["user_id"].(float64))
+	ctx := r.Context()
+	p := access.FromContext(r.Context())
 
 	id, _ := strconv.Atoi(chi.URLParam(r, "id"))
-	schoolID := 0
 
-	if role == "school" {
-		schoolRepo := repository.NewSchoolRepository(h.svc.RepoDB())
-		school, err := schoolRepo.GetByUserID(ctx, userID)
-		if err != nil {
-			helpers.Error(w, http.StatusForbidden, "school not found")
-			return
-		}
-		schoolID = school.ID
-	}
-
-	if err := h.svc.Delete(ctx, id, schoolID); err != nil {
+	ok, err := h.svc.Delete(ctx, id, p.SchoolScope())
+	if err != nil {
 		helpers.Error(w, http.StatusInternalServerError, "failed to delete class")
 		return
 	}
+	if !ok {
+		helpers.Error(w, http.StatusNotFound, "class not found or not yours")
+		return
+	}
 	helpers.JSON(w, http.StatusOK, map[string]string{"status": "deleted"})
 }
 
 // GetByID godoc
 // @Summary Получить класс по ID
-// @Description ROO — любой класс, School — только свой
+// @Description РОО — любой класс, школа — только свой, учитель — только свой класс
 // @Tags Classes
 // @Produce json
 // @Param id path int true "ID класса"
@@ -215,31 +231,31 @@ func (h *ClassHandler) Delete(w http.ResponseWriter, r *http.Request) {
 // @Security BearerAuth
 // @Router /classes/{id} [get]
 func (h *ClassHandler) GetByID(w http.ResponseWriter, r *http.Request) {
-	ctx := context.Background()
-	_, claims, _ := jwtauth.FromContext(r.Context())
-	role := claims["role"].(string)
-	userID := int(claims["user_id"].(float64))
+	class, ok := h.ownClass(w, r, false)
+	if !ok {
+		return
+	}
+	helpers.JSON(w, http.StatusOK, class)
+}
 
+// ownClass загружает класс {id} и проверяет доступ к нему: на чтение школа видит только свои классы,
+// учитель — только свой; на запись — только классы своей школы. При ошибке пишет ответ и возвращает ok=false.
+func (h *ClassHandler) ownClass(w http.ResponseWriter, r *http.Request, write bool) (*models.Class, bool) {
+	p := access.FromContext(r.Context())
 	id, _ := strconv.Atoi(chi.URLParam(r, "id"))
-	class, err := h.svc.GetByID(ctx, id)
+
+	class, err := h.svc.GetByID(r.Context(), id)
 	if err != nil {
 		helpers.Error(w, http.StatusNotFound, "class not found")
-		return
+		return nil, false
 	}
-
-	// Проверка доступа: если School, то только свой класс
-	if role == "school" {
-		schoolRepo := repository.NewSchoolRepository(h.svc.RepoDB())
-		school, err := schoolRepo.GetByUserID(ctx, userID)
-		if err != nil {
-			helpers.Error(w, http.StatusForbidden, "school not found")
-			return
-		}
-		if class.SchoolID != school.ID {
-			helpers.Error(w, http.StatusForbidden, "access denied")
-			return
-		}
+	allowed := p.CanAccessClass(class.SchoolID, class.ID)
+	if write {
+		allowed = p.CanAccessSchool(class.SchoolID)
 	}
-
-	helpers.JSON(w, http.StatusOK, class)
+	if !allowed {
+		helpers.Error(w, http.StatusForbidden, "access denied")
+		return nil, false
+	}
+	return class, true
 }
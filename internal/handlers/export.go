@@ -0,0 +1,261 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"eduBase/internal/access"
+	"eduBase/internal/helpers"
+	"eduBase/internal/models"
+	"eduBase/internal/repository"
+	"eduBase/internal/services"
+	"eduBase/internal/utils"
+)
+
+var (
+	genderLabels        = map[string]string{"male": "М", "female": "Ж"}
+	studentStatusLabels = map[string]string{
+		models.StudentActive: "учится", models.StudentGraduated: "окончил", models.StudentLeft: "выбыл",
+	}
+)
+
+// xlsxDate — дата для ячейки выгрузки (ДД.ММ.ГГГГ) или пустая ячейка
+func xlsxDate(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format("02.01.2006")
+}
+
+// xlsxInt — число для ячейки выгрузки или пустая ячейка
+func xlsxInt(n *int) any {
+	if n == nil {
+		return nil
+	}
+	return *n
+}
+
+// schoolSheets раскладывает строки выгрузки по листам — лист на школу (по названию школы, в порядке списка школ).
+// Если строк нет, книга состоит из одного пустого листа title.
+func schoolSheets(ctx context.Context, db repository.DBTX, title string, header []string, rows map[int][][]any) ([]utils.Sheet, error) {
+	schools, err := repository.NewSchoolRepository(db).GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	used := map[string]bool{}
+	var sheets []utils.Sheet
+	for _, s := range schools {
+		if len(rows[s.ID]) == 0 {
+			continue
+		}
+		sheets = append(sheets, utils.Sheet{Name: utils.SheetName(s.Name, used), Header: header, Rows: rows[s.ID]})
+	}
+	if len(sheets) == 0 {
+		sheets = append(sheets, utils.Sheet{Name: title, Header: header})
+	}
+	return sheets, nil
+}
+
+// exportStaff — сотрудники для выгрузки: своей школы или, для РОО и инспектора (schoolID == nil), всего района
+func exportStaff(ctx context.Context, db repository.DBTX, schoolID *int, f repository.StaffFilter) ([]models.Staff, error) {
+	repo := repository.NewStaffRepository(db)
+	if schoolID == nil {
+		return repo.GetAllDistrict(ctx, f)
+	}
+	return repo.GetAll(ctx, schoolID, f)
+}
+
+// ExportXLSX godoc
+// @Summary Экспорт учеников в xlsx
+// @Description Фильтры — как у списка /students; для РОО и инспектора — лист на каждую школу. Школа выгружает своих учеников.
+// @Description Выгрузка фиксируется в журнале доступа к персональным данным
+// @Tags Students
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param full_name query string false "ФИО"
+// @Param gender query string false "Пол (male/female)"
+// @Param class_id query int false "ID класса"
+// @Param academic_year query int false "ID учебного года (по умолчанию текущий)"
+// @Security BearerAuth
+// @Success 200 {file} file "xlsx"
+// @Failure 400 {object} helpers.ErrorResponse
+// @Failure 403 {object} helpers.ErrorResponse
+// @Failure 500 {object} helpers.ErrorResponse
+// @Router /students/export/xlsx [get]
+func (h *StudentHandler) ExportXLSX(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	p := access.FromContext(r.Context())
+
+	f, ok := studentFilter(w, r)
+	if !ok {
+		return
+	}
+	list, err := h.svc.GetAll(ctx, p.SchoolScope(), f)
+	if err != nil {
+		helpers.Error(w, http.StatusInternalServerError, "failed to export")
+		return
+	}
+	guardians, err := h.guardians.GetByStudents(ctx, studentIDs(list))
+	if err != nil {
+		helpers.Error(w, http.StatusInternalServerError, "failed to export")
+		return
+	}
+
+	rows := map[int][][]any{}
+	for _, s := range list {
+		rows[s.SchoolID] = append(rows[s.SchoolID], []any{
+			s.FullName, xlsxDate(s.BirthDate), genderLabels[deref(s.Gender)], s.ClassName, studentStatusLabels[s.Status],
+			deref(s.Phone), deref(s.Address), guardiansCell(guardians[s.ID]), deref(s.Note),
+		})
+	}
+	sheets, err := schoolSheets(ctx, h.svc.SchoolRepoDB(), "Ученики", []string{
+		"ФИО", "Дата рождения", "Пол", "Класс", "Статус",
+		"Телефон", "Адрес", "Родители и законные представители", "Примечание",
+	}, rows)
+	if err != nil {
+		helpers.Error(w, http.StatusInternalServerError, "failed to export")
+		return
+	}
+	filter := studentAccessFilter{SchoolID: p.SchoolScope(), StudentFilter: f}
+	if err := h.pd.Log(ctx, services.PDActionExport, studentIDs(list), filter); err != nil {
+		helpers.Error(w, http.StatusInternalServerError, "failed to log access")
+		return
+	}
+	writeXLSX(w, "students.xlsx", sheets...)
+}
+
+// Export godoc
+// @Summary Экспорт сотрудников в xlsx
+// @Description Фильтры — как у списка /staff; для РОО и инспектора — лист на каждую школу. Школа выгружает своих сотрудников.
+// @Tags Staff
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param full_name query string false "ФИО"
+// @Param phone query string false "Телефон"
+// @Param position query string false "Должность"
+// @Param subject query string false "Предмет"
+// @Param education query string false "Образование"
+// @Param category query string false "Категория"
+// @Security BearerAuth
+// @Success 200 {file} file "xlsx"
+// @Failure 403 {object} helpers.ErrorResponse
+// @Failure 500 {object} helpers.ErrorResponse
+// @Router /staff/export/xlsx [get]
+func (h *StaffHandler) Export(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	p := access.FromContext(r.Context())
+
+	list, err := exportStaff(ctx, h.svc.RepoDB(), p.SchoolScope(), staffFilter(r))
+	if err != nil {
+		helpers.Error(w, http.StatusInternalServerError, "failed to export")
+		return
+	}
+
+	rows := map[int][][]any{}
+	for _, s := range list {
+		rows[s.SchoolID] = append(rows[s.SchoolID], []any{
+			s.FullName, xlsxDate(s.BirthDate), s.Phone, s.Position, deref(s.Subject), deref(s.Education), deref(s.Category),
+			xlsxInt(s.PedExperience), xlsxInt(s.TotalExperience), xlsxDate(s.WorkStart), deref(s.Note),
+		})
+	}
+	sheets, err := schoolSheets(ctx, h.svc.RepoDB(), "Сотрудники", []string{
+		"ФИО", "Дата рождения", "Телефон", "Должность", "Предмет", "Образование", "Категория",
+		"Педагогический стаж", "Общий стаж", "Дата начала работы", "Примечание",
+	}, rows)
+	if err != nil {
+		helpers.Error(w, http.StatusInternalServerError, "failed to export")
+		return
+	}
+	writeXLSX(w, "staff.xlsx", sheets...)
+}
+
+// Export godoc
+// @Summary Экспорт классов в xlsx
+// @Description Для РОО и инспектора — лист на каждую школу; школа выгружает свои классы, учитель — свой класс.
+// @Tags Classes
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param academic_year query int false "ID учебного года (по умолчанию текущий)"
+// @Security BearerAuth
+// @Success 200 {file} file "xlsx"
+// @Failure 400 {object} helpers.ErrorResponse
+// @Failure 403 {object} helpers.ErrorResponse
+// @Failure 500 {object} helpers.ErrorResponse
+// @Router /classes/export/xlsx [get]
+func (h *ClassHandler) Export(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	p := access.FromContext(r.Context())
+
+	yearID, err := queryInt(r.URL.Query(), "academic_year")
+	if err != nil {
+		helpers.Error(w, http.StatusBadRequest, "invalid academic_year")
+		return
+	}
+	var list []models.Class
+	if schoolID := p.SchoolScope(); schoolID == nil {
+		list, err = h.svc.GetAll(ctx, yearID)
+	} else {
+		list, err = h.svc.GetBySchool(ctx, *schoolID, yearID)
+	}
+	if err != nil {
+		helpers.Error(w, http.StatusInternalServerError, "failed to export")
+		return
+	}
+	staff, err := exportStaff(ctx, h.svc.RepoDB(), p.SchoolScope(), repository.StaffFilter{})
+	if err != nil {
+		helpers.Error(w, http.StatusInternalServerError, "failed to export")
+		return
+	}
+	teachers := make(map[int]string, len(staff))
+	for _, s := range staff {
+		teachers[s.ID] = s.FullName
+	}
+
+	rows := map[int][][]any{}
+	for _, c := range list {
+		// учитель видит только свой класс
+		if classID := p.ClassScope(); classID != nil && c.ID != *classID {
+			continue
+		}
+		teacher := ""
+		if c.ClassTeacherID != nil {
+			teacher = teachers[*c.ClassTeacherID]
+		}
+		rows[c.SchoolID] = append(rows[c.SchoolID], []any{c.Name, c.Grade, teacher, c.StudentCount})
+	}
+	sheets, err := schoolSheets(ctx, h.svc.RepoDB(), "Классы",
+		[]string{"Класс", "Параллель", "Классный руководитель", "Учеников"}, rows)
+	if err != nil {
+		helpers.Error(w, http.StatusInternalServerError, "failed to export")
+		return
+	}
+	writeXLSX(w, "classes.xlsx", sheets...)
+}
+
+// Export godoc
+// @Summary      Экспорт списка школ в xlsx
+// @Description  РОО и инспектор
+// @Tags         Schools
+// @Produce      application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Success      200 {file} file "xlsx"
+// @Failure      500 {object} helpers.ErrorResponse
+// @Security     BearerAuth
+// @Router       /roo/schools/export/xlsx [get]
+func (h *RooSchoolHandler) Export(w http.ResponseWriter, r *http.Request) {
+	list, err := h.svc.GetAll(r.Context())
+	if err != nil {
+		helpers.Error(w, http.StatusInternalServerError, "failed to export")
+		return
+	}
+	rows := make([][]any, 0, len(list))
+	for _, s := range list {
+		login := ""
+		if s.User != nil {
+			login = s.User.Email
+		}
+		rows = append(rows, []any{s.Name, s.Director, s.ClassCount, s.StudentCount, login, xlsxDate(&s.CreatedAt)})
+	}
+	writeXLSX(w, "schools.xlsx", utils.Sheet{
+		Name:   "Школы",
+		Header: []string{"Название", "Директор", "Классов", "Учеников", "Логин", "Дата добавления"},
+		Rows:   rows,
+	})
+}
@@ -0,0 +1,192 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"eduBase/internal/access"
+	"eduBase/internal/helpers"
+	"eduBase/internal/models"
+	"eduBase/internal/repository"
+	"eduBase/internal/services"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// ownStudent загружает ученика из URL и проверяет доступ: на чтение — как GetByID
+// (учитель — только свой класс), на изменение — только ученики своей школы
+func (h *StudentHandler) ownStudent(w http.ResponseWriter, r *http.Request, write bool) (*models.Student, bool) {
+	p := access.FromContext(r.Context())
+	id, _ := strconv.Atoi(chi.URLParam(r, "id"))
+
+	st, err := h.svc.GetByID(r.Context(), id)
+	if err != nil {
+		helpers.Error(w, http.StatusNotFound, "student not found")
+		return nil, false
+	}
+	allowed := p.CanAccessClass(st.SchoolID, st.ClassID)
+	if write {
+		allowed = p.CanAccessSchool(st.SchoolID)
+	}
+	if !allowed {
+		helpers.Error(w, http.StatusForbidden, "access denied")
+		return nil, false
+	}
+	return st, true
+}
+
+func writeGuardianError(w http.ResponseWriter, err error, fallback string) {
+	switch {
+	case errors.Is(err, services.ErrInvalidGuardian):
+		helpers.Error(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, repository.ErrGuardianNotFound):
+		helpers.Error(w, http.StatusNotFound, err.Error())
+	default:
+		helpers.Error(w, http.StatusInternalServerError, fallback)
+	}
+}
+
+// GetGuardians godoc
+// @Summary Родители и законные представители ученика
+// @Description Доступ как к самому ученику; просмотр фиксируется в журнале доступа к персональным данным
+// @Tags Guardians
+// @Produce json
+// @Param id path int true "ID ученика"
+// @Security BearerAuth
+// @Success 200 {array} models.Guardian
+// @Failure 403 {object} helpers.ErrorResponse
+// @Failure 404 {object} helpers.ErrorResponse
+// @Failure 500 {object} helpers.ErrorResponse
+// @Router /students/{id}/guardians [get]
+func (h *StudentHandler) GetGuardians(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	st, ok := h.ownStudent(w, r, false)
+	if !ok {
+		return
+	}
+
+	list, err := h.guardians.GetByStudent(ctx, st.ID)
+	if err != nil {
+		helpers.Error(w, http.StatusInternalServerError, "failed to get guardians")
+		return
+	}
+	if err := h.pd.Log(ctx, services.PDActionView, []int{st.ID}, nil); err != nil {
+		helpers.Error(w, http.StatusInternalServerError, "failed to log access")
+		return
+	}
+	helpers.JSON(w, http.StatusOK, list)
+}
+
+// AddGuardian godoc
+// @Summary Добавить представителя ученику
+// @Description Новый представитель — по полям full_name, relation и т.д.; существующий (родитель брата или сестры) — по id.
+// @Description is_primary делает представителя основным контактом ученика.
+// @Tags Guardians
+// @Accept json
+// @Produce json
+// @Param id path int true "ID ученика"
+// @Param data body models.Guardian true "Представитель"
+// @Security BearerAuth
+// @Success 201 {object} models.Guardian
+// @Failure 400 {object} helpers.ErrorResponse
+// @Failure 403 {object} helpers.ErrorResponse
+// @Failure 404 {object} helpers.ErrorResponse
+// @Failure 500 {object} helpers.ErrorResponse
+// @Router /students/{id}/guardians [post]
+func (h *StudentHandler) AddGuardian(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	p := access.FromContext(r.Context())
+
+	st, ok := h.ownStudent(w, r, true)
+	if !ok {
+		return
+	}
+	var g models.Guardian
+	if err := json.NewDecoder(r.Body).Decode(&g); err != nil {
+		helpers.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	if err := h.guardians.Add(ctx, st, &g, p.SchoolScope()); err != nil {
+		writeGuardianError(w, err, "failed to add guardian")
+		return
+	}
+	helpers.JSON(w, http.StatusCreated, g)
+}
+
+// UpdateGuardian godoc
+// @Summary Обновить представителя
+// @Description Данные представителя общие для всех его детей; is_primary относится к этому ученику
+// @Tags Guardians
+// @Accept json
+// @Produce json
+// @Param id path int true "ID ученика"
+// @Param guardianID path int true "ID представителя"
+// @Param data body models.Guardian true "Новые данные"
+// @Security BearerAuth
+// @Success 200 {object} models.Guardian
+// @Failure 400 {object} helpers.ErrorResponse
+// @Failure 403 {object} helpers.ErrorResponse
+// @Failure 404 {object} helpers.ErrorResponse
+// @Failure 500 {object} helpers.ErrorResponse
+// @Router /students/{id}/guardians/{guardianID} [put]
+func (h *StudentHandler) UpdateGuardian(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	st, ok := h.ownStudent(w, r, true)
+	if !ok {
+		return
+	}
+	guardianID, _ := strconv.Atoi(chi.URLParam(r, "guardianID"))
+
+	var g models.Guardian
+	if err := json.NewDecoder(r.Body).Decode(&g); err != nil {
+		helpers.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	if err := h.guardians.Update(ctx, st, guardianID, &g); err != nil {
+		writeGuardianError(w, err, "failed to update guardian")
+		return
+	}
+	helpers.JSON(w, http.StatusOK, g)
+}
+
+// RemoveGuardian godoc
+// @Summary Убрать представителя у ученика
+// @Description Представитель, не связанный больше ни с одним учеником, удаляется
+// @Tags Guardians
+// @Param id path int true "ID ученика"
+// @Param guardianID path int true "ID представителя"
+// @Security BearerAuth
+// @Success 200 {object} map[string]string
+// @Failure 403 {object} helpers.ErrorResponse
+// @Failure 404 {object} helpers.ErrorResponse
+// @Failure 500 {object} helpers.ErrorResponse
+// @Router /students/{id}/guardians/{guardianID} [delete]
+func (h *StudentHandler) RemoveGuardian(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	st, ok := h.ownStudent(w, r, true)
+	if !ok {
+		return
+	}
+	guardianID, _ := strconv.Atoi(chi.URLParam(r, "guardianID"))
+
+	if err := h.guardians.Remove(ctx, st, guardianID); err != nil {
+		writeGuardianError(w, err, "failed to remove guardian")
+		return
+	}
+	helpers.JSON(w, http.StatusOK, map[string]string{"status": "removed"})
+}
+
+// guardiansCell — представители ученика одной ячейкой выгрузки: «ФИО (mother, +7...); ...»
+func guardiansCell(list []models.Guardian) string {
+	parts := make([]string, 0, len(list))
+	for _, g := range list {
+		info := append([]string{g.Relation}, g.Phones...)
+		parts = append(parts, g.FullName+" ("+strings.Join(info, ", ")+")")
+	}
+	return strings.Join(parts, "; ")
+}
@@ -0,0 +1,297 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"eduBase/internal/access"
+	"eduBase/internal/helpers"
+	"eduBase/internal/middleware"
+	"eduBase/internal/models"
+	"eduBase/internal/repository"
+	"eduBase/internal/services"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// MarkHandler — журнал отметок классов
+type MarkHandler struct {
+	svc *services.MarkService
+	pd  *services.PDAccessService
+}
+
+func NewMarkHandler(svc *services.MarkService, pd *services.PDAccessService) *MarkHandler {
+	return &MarkHandler{svc: svc, pd: pd}
+}
+
+func (h *MarkHandler) Routes(r chi.Router) {
+	r.Route("/marks", func(r chi.Router) {
+		r.With(middleware.RequirePermission(access.StudentsRead)).Get("/", h.GetJournal)
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.RequirePermission(access.MarksWrite))
+			r.Put("/", h.Mark)
+			r.Put("/terms", h.MarkTerm)
+		})
+	})
+}
+
+type markRequest struct {
+	ClassID   int                `json:"class_id" example:"12"`
+	SubjectID int                `json:"subject_id" example:"3"`
+	Date      string             `json:"date" example:"2025-11-20"` // YYYY-MM-DD, по умолчанию сегодня
+	Marks     []models.MarkEntry `json:"marks"`
+}
+
+type termMarkRequest struct {
+	ClassID   int                `json:"class_id" example:"12"`
+	SubjectID int                `json:"subject_id" example:"3"`
+	Period    string             `json:"period" example:"q1"` // q1–q4, h1, h2, year
+	Marks     []models.MarkEntry `json:"marks"`
+}
+
+// journalAccessFilter — параметры просмотра журнала для журнала доступа к персональным данным
+type journalAccessFilter struct {
+	ClassID   int    `json:"class_id"`
+	SubjectID int    `json:"subject_id"`
+	From      string `json:"from"`
+	To        string `json:"to"`
+}
+
+// maxJournalDays — наибольший период журнала в одном запросе
+const maxJournalDays = 366
+
+// markClass загружает класс и проверяет доступ (учитель — только свой класс)
+func (h *MarkHandler) markClass(w http.ResponseWriter, r *http.Request, classID int) (*models.Class, bool) {
+	p := access.FromContext(r.Context())
+
+	c, err := h.svc.GetClass(r.Context(), classID)
+	if err != nil {
+		if errors.Is(err, repository.ErrClassNotFound) {
+			helpers.Error(w, http.StatusNotFound, err.Error())
+			return nil, false
+		}
+		helpers.Error(w, http.StatusInternalServerError, "failed to get class")
+		return nil, false
+	}
+	if !p.CanAccessClass(c.SchoolID, c.ID) {
+		helpers.Error(w, http.StatusForbidden, "access denied")
+		return nil, false
+	}
+	return c, true
+}
+
+func writeMarkError(w http.ResponseWriter, err error, fallback string) {
+	switch {
+	case errors.Is(err, services.ErrInvalidMark),
+		errors.Is(err, services.ErrInvalidPeriod),
+		errors.Is(err, services.ErrMarkStudent),
+		errors.Is(err, services.ErrMarkFutureDate),
+		errors.Is(err, services.ErrMarkOutsideYear),
+		errors.Is(err, services.ErrMarkDuplicate),
+		errors.Is(err, services.ErrClassArchived),
+		errors.Is(err, repository.ErrSubjectNotFound):
+		helpers.Error(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, repository.ErrNoCurrentYear):
+		helpers.Error(w, http.StatusConflict, err.Error())
+	default:
+		helpers.Error(w, http.StatusInternalServerError, fallback)
+	}
+}
+
+// GetJournal godoc
+// @Summary Журнал класса по предмету
+// @Description Ученики класса с отметками за период и итоговыми отметками по предмету. По умолчанию — текущий месяц, не больше года.
+// @Description Учитель — только свой класс. Просмотр фиксируется в журнале доступа к персональным данным
+// @Tags Marks
+// @Produce json
+// @Param class_id query int true "ID класса"
+// @Param subject_id query int true "ID предмета"
+// @Param from query string false "Начало периода YYYY-MM-DD (по умолчанию первое число месяца)"
+// @Param to query string false "Конец периода YYYY-MM-DD (включительно, по умолчанию сегодня)"
+// @Security BearerAuth
+// @Success 200 {object} models.ClassJournal
+// @Failure 400 {object} helpers.ErrorResponse
+// @Failure 403 {object} helpers.ErrorResponse
+// @Failure 404 {object} helpers.ErrorResponse
+// @Failure 500 {object} helpers.ErrorResponse
+// @Router /marks [get]
+func (h *MarkHandler) GetJournal(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	q := r.URL.Query()
+
+	classID, err := queryInt(q, "class_id")
+	if err != nil || classID == nil {
+		helpers.Error(w, http.StatusBadRequest, "class_id required")
+		return
+	}
+	subjectID, err := queryInt(q, "subject_id")
+	if err != nil || subjectID == nil {
+		helpers.Error(w, http.StatusBadRequest, "subject_id required")
+		return
+	}
+	to, err := parseDateParam(q.Get("to"))
+	if err != nil {
+		helpers.Error(w, http.StatusBadRequest, "invalid to")
+		return
+	}
+	from := to.AddDate(0, 0, 1-to.Day())
+	if v := q.Get("from"); v != "" {
+		if from, err = time.Parse(time.DateOnly, v); err != nil {
+			helpers.Error(w, http.StatusBadRequest, "invalid from")
+			return
+		}
+	}
+	if from.After(to) || to.Sub(from) >= maxJournalDays*24*time.Hour {
+		helpers.Error(w, http.StatusBadRequest, "period must be from 1 to 366 days")
+		return
+	}
+	c, ok := h.markClass(w, r, *classID)
+	if !ok {
+		return
+	}
+
+	res, err := h.svc.Journal(ctx, c.ID, *subjectID, from, to)
+	if err != nil {
+		helpers.Error(w, http.StatusInternalServerError, "failed to get marks")
+		return
+	}
+	ids := make([]int, 0, len(res.Students))
+	for _, st := range res.Students {
+		ids = append(ids, st.StudentID)
+	}
+	filter := journalAccessFilter{ClassID: res.ClassID, SubjectID: res.SubjectID, From: res.From, To: res.To}
+	if err := h.pd.Log(ctx, services.PDActionList, ids, filter); err != nil {
+		helpers.Error(w, http.StatusInternalServerError, "failed to log access")
+		return
+	}
+	helpers.JSON(w, http.StatusOK, res)
+}
+
+// Mark godoc
+// @Summary Поставить отметки классу
+// @Description Пакетная отправка отметок 2–5 по предмету за дату урока; отметка без mark снимается.
+// @Description Сохраняются все отметки или ни одной; повторная отправка исправляет ранее поставленные. Учитель — только свой класс.
+// @Description Дата — в пределах текущего учебного года и не позже сегодняшней; каждый ученик — не больше одного раза.
+// @Tags Marks
+// @Accept json
+// @Produce json
+// @Param data body markRequest true "Отметки"
+// @Security BearerAuth
+// @Success 200 {object} models.ClassJournal
+// @Failure 400 {object} helpers.ErrorResponse
+// @Failure 403 {object} helpers.ErrorResponse
+// @Failure 404 {object} helpers.ErrorResponse
+// @Failure 409 {object} helpers.ErrorResponse
+// @Failure 500 {object} helpers.ErrorResponse
+// @Router /marks [put]
+func (h *MarkHandler) Mark(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req markRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.ClassID == 0 || req.SubjectID == 0 || len(req.Marks) == 0 {
+		helpers.Error(w, http.StatusBadRequest, "class_id, subject_id and marks required")
+		return
+	}
+	date, err := parseDateParam(req.Date)
+	if err != nil {
+		helpers.Error(w, http.StatusBadRequest, "invalid date")
+		return
+	}
+	c, ok := h.markClass(w, r, req.ClassID)
+	if !ok {
+		return
+	}
+
+	res, err := h.svc.Mark(ctx, c, req.SubjectID, date, req.Marks)
+	if err != nil {
+		writeMarkError(w, err, "failed to save marks")
+		return
+	}
+	helpers.JSON(w, http.StatusOK, res)
+}
+
+// MarkTerm godoc
+// @Summary Поставить итоговые отметки классу
+// @Description Пакетная отправка отметок за четверть (q1–q4), полугодие (h1, h2) или год (year) по предмету; отметка без mark снимается.
+// @Description Сохраняются все отметки или ни одной; каждый ученик — не больше одного раза. Учитель — только свой класс.
+// @Tags Marks
+// @Accept json
+// @Produce json
+// @Param data body termMarkRequest true "Итоговые отметки"
+// @Security BearerAuth
+// @Success 200 {array} models.TermMark
+// @Failure 400 {object} helpers.ErrorResponse
+// @Failure 403 {object} helpers.ErrorResponse
+// @Failure 404 {object} helpers.ErrorResponse
+// @Failure 409 {object} helpers.ErrorResponse
+// @Failure 500 {object} helpers.ErrorResponse
+// @Router /marks/terms [put]
+func (h *MarkHandler) MarkTerm(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req termMarkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.ClassID == 0 || req.SubjectID == 0 || len(req.Marks) == 0 {
+		helpers.Error(w, http.StatusBadRequest, "class_id, subject_id and marks required")
+		return
+	}
+	c, ok := h.markClass(w, r, req.ClassID)
+	if !ok {
+		return
+	}
+
+	res, err := h.svc.MarkTerm(ctx, c, req.SubjectID, req.Period, req.Marks)
+	if err != nil {
+		writeMarkError(w, err, "failed to save term marks")
+		return
+	}
+	helpers.JSON(w, http.StatusOK, res)
+}
+
+// GetMarks godoc
+// @Summary Успеваемость ученика
+// @Description Отметки и итоговые отметки ученика по предметам за учебный год. Учитель — только свой класс.
+// @Description Просмотр фиксируется в журнале доступа к персональным данным
+// @Tags Marks
+// @Produce json
+// @Param id path int true "ID ученика"
+// @Param academic_year query int false "ID учебного года (по умолчанию текущий)"
+// @Security BearerAuth
+// @Success 200 {object} models.StudentMarks
+// @Failure 400 {object} helpers.ErrorResponse
+// @Failure 403 {object} helpers.ErrorResponse
+// @Failure 404 {object} helpers.ErrorResponse
+// @Failure 500 {object} helpers.ErrorResponse
+// @Router /students/{id}/marks [get]
+func (h *StudentHandler) GetMarks(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	yearID, err := queryInt(r.URL.Query(), "academic_year")
+	if err != nil {
+		helpers.Error(w, http.StatusBadRequest, "invalid academic_year")
+		return
+	}
+	st, ok := h.ownStudent(w, r, false)
+	if !ok {
+		return
+	}
+
+	res, err := h.marks.ByStudent(ctx, st.ID, yearID)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrAcademicYearNotFound):
+			helpers.Error(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, repository.ErrNoCurrentYear):
+			helpers.Error(w, http.StatusConflict, err.Error())
+		default:
+			helpers.Error(w, http.StatusInternalServerError, "failed to get marks")
+		}
+		return
+	}
+	if err := h.pd.Log(ctx, services.PDActionView, []int{st.ID}, nil); err != nil {
+		helpers.Error(w, http.StatusInternalServerError, "failed to log access")
+		return
+	}
+	helpers.JSON(w, http.StatusOK, res)
+}
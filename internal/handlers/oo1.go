@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"eduBase/internal/helpers"
+	"eduBase/internal/models"
+	"eduBase/internal/repository"
+	"eduBase/internal/utils"
+)
+
+// oo1Report считает отчёт ОО-1 по параметрам запроса. При ошибке пишет ответ и возвращает nil.
+func (h *StatsHandler) oo1Report(w http.ResponseWriter, r *http.Request) *models.OO1Report {
+	schoolID, ok := h.resolveSchool(w, r)
+	if !ok {
+		return nil
+	}
+	date, err := parseDateParam(r.URL.Query().Get("date"))
+	if err != nil {
+		helpers.Error(w, http.StatusBadRequest, "invalid date")
+		return nil
+	}
+	if date.After(time.Now()) {
+		helpers.Error(w, http.StatusBadRequest, "date is in the future")
+		return nil
+	}
+
+	res, err := h.svc.GetOO1(r.Context(), schoolID, date)
+	if err != nil {
+		if errors.Is(err, repository.ErrAcademicYearNotFound) {
+			helpers.Error(w, http.StatusBadRequest, "no academic year covers this date")
+			return nil
+		}
+		helpers.Error(w, http.StatusInternalServerError, "failed to get stats")
+		return nil
+	}
+	return res
+}
+
+// OO1 godoc
+// @Summary Форма ОО-1 на дату
+// @Description Классы и обучающиеся по параллелям, обучающиеся по возрасту (на 1 января учебного года), полу и параллелям,
+// @Description работники и учителя по образованию, квалификационной категории и педагогическому стажу.
+// @Description Обучающиеся — по книге движения на дату (школа и класс последнего прибытия или смены класса); работники — принятые не позже даты;
+// @Description учитель — должность «учитель» или назначения учебного года, как в /stats/staff.
+// @Description РОО и инспектор — весь район или по school_id; школа — только своя.
+// @Tags Stats
+// @Produce json
+// @Param school_id query int false "Фильтрация по школе (только для РОО и инспектора)"
+// @Param date query string false "Дата отчёта YYYY-MM-DD (по умолчанию сегодня)"
+// @Security BearerAuth
+// @Success 200 {object} models.OO1Report
+// @Failure 400 {object} helpers.ErrorResponse
+// @Failure 403 {object} helpers.ErrorResponse
+// @Failure 500 {object} helpers.ErrorResponse
+// @Router /stats/oo1 [get]
+func (h *StatsHandler) OO1(w http.ResponseWriter, r *http.Request) {
+	if res := h.oo1Report(w, r); res != nil {
+		helpers.JSON(w, http.StatusOK, res)
+	}
+}
+
+// OO1XLSX godoc
+// @Summary Форма ОО-1 на дату (xlsx)
+// @Description Те же разделы, что /stats/oo1, — по листу на раздел, с итоговыми строками.
+// @Tags Stats
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param school_id query int false "Фильтрация по школе (только для РОО и инспектора)"
+// @Param date query string false "Дата отчёта YYYY-MM-DD (по умолчанию сегодня)"
+// @Security BearerAuth
+// @Success 200 {file} file "xlsx"
+// @Failure 400 {object} helpers.ErrorResponse
+// @Failure 403 {object} helpers.ErrorResponse
+// @Failure 500 {object} helpers.ErrorResponse
+// @Router /stats/oo1/xlsx [get]
+func (h *StatsHandler) OO1XLSX(w http.ResponseWriter, r *http.Request) {
+	res := h.oo1Report(w, r)
+	if res == nil {
+		return
+	}
+	date, _ := time.Parse(time.DateOnly, res.Date)
+	ageDate, _ := time.Parse(time.DateOnly, res.AgeDate)
+
+	school := res.SchoolName
+	if res.SchoolID == nil {
+		school = "Все школы района"
+	}
+	summary := utils.Sheet{Name: "Сводка", Header: []string{"Показатель", "Значение"}, Rows: [][]any{
+		{"Дата отчёта", xlsxDate(&date)},
+		{"Школа", school},
+		{"Классов", res.Classes},
+		{"Обучающихся", res.Students},
+		{"из них девочек", res.Girls},
+		{"Работников", res.Staff},
+		{"из них учителей", res.Teachers},
+	}}
+
+	grades := utils.Sheet{Name: "Параллели", Header: []string{"Параллель", "Классов", "Обучающихся", "из них девочек"}}
+	for _, g := range res.Grades {
+		grades.Rows = append(grades.Rows, []any{g.Grade, g.Classes, g.Students, g.Girls})
+	}
+	grades.Rows = append(grades.Rows, []any{"Итого", res.Classes, res.Students, res.Girls})
+
+	ages := utils.Sheet{Name: "Возраст", Header: []string{"Возраст на " + xlsxDate(&ageDate), "Обучающихся", "из них девочек"}}
+	for _, g := range res.Grades {
+		ages.Header = append(ages.Header, strconv.Itoa(g.Grade)+" класс")
+	}
+	for _, a := range res.Ages {
+		row := []any{a.Age, a.Students, a.Girls}
+		for _, g := range res.Grades {
+			row = append(row, a.ByGrade[g.Grade])
+		}
+		ages.Rows = append(ages.Rows, row)
+	}
+	total := []any{"Итого", res.Students, res.Girls}
+	for _, g := range res.Grades {
+		total = append(total, g.Students)
+	}
+	ages.Rows = append(ages.Rows, total)
+
+	staffSheet := func(name, title string, rows []models.OO1StaffRow) utils.Sheet {
+		sh := utils.Sheet{Name: name, Header: []string{title, "Работников", "из них учителей"}}
+		for _, row := range rows {
+			sh.Rows = append(sh.Rows, []any{row.Label, row.Staff, row.Teachers})
+		}
+		sh.Rows = append(sh.Rows, []any{"Итого", res.Staff, res.Teachers})
+		return sh
+	}
+
+	writeXLSX(w, "oo1_"+res.Date+".xlsx", summary, grades, ages,
+		staffSheet("Образование", "Уровень образования", res.Education),
+		staffSheet("Категория", "Квалификационная категория", res.Categories),
+		staffSheet("Стаж", "Педагогический стаж", res.Experience),
+	)
+}
@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"eduBase/internal/access"
+	"eduBase/internal/helpers"
+	"eduBase/internal/middleware"
+	"eduBase/internal/repository"
+	"eduBase/internal/services"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// PDAccessHandler — отчёт о доступе к персональным данным учеников
+type PDAccessHandler struct {
+	svc *services.PDAccessService
+}
+
+func NewPDAccessHandler(svc *services.PDAccessService) *PDAccessHandler {
+	return &PDAccessHandler{svc: svc}
+}
+
+func (h *PDAccessHandler) Routes(r chi.Router) {
+	r.With(middleware.RequirePermission(access.AuditRead)).Get("/roo/pd-access", h.GetAll)
+}
+
+// GetAll godoc
+// @Summary      Журнал доступа к персональным данным учеников
+// @Description  Кто и когда просматривал карточки, списки и выгрузки учеников. Например: student_id=15&from=2025-09-01&to=2025-09-30 — кто смотрел ученика 15 в сентябре.
+// @Tags         Audit
+// @Produce      json
+// @Param        student_id query int    false "ID ученика"
+// @Param        user_id    query int    false "Кто просматривал"
+// @Param        from       query string false "С даты (YYYY-MM-DD или RFC3339)"
+// @Param        to         query string false "По дату включительно (YYYY-MM-DD или RFC3339)"
+// @Param        limit      query int    false "Сколько записей (по умолчанию 100, максимум 1000)"
+// @Param        offset     query int    false "Смещение"
+// @Success      200 {array} models.PDAccessEntry
+// @Failure      400 {object} helpers.ErrorResponse
+// @Failure      500 {object} helpers.ErrorResponse
+// @Security     BearerAuth
+// @Router       /roo/pd-access [get]
+func (h *PDAccessHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	var f repository.PDAccessFilter
+
+	var err error
+	if f.StudentID, err = queryInt(q, "student_id"); err != nil {
+		helpers.Error(w, http.StatusBadRequest, "invalid student_id")
+		return
+	}
+	if f.UserID, err = queryInt(q, "user_id"); err != nil {
+		helpers.Error(w, http.StatusBadRequest, "invalid user_id")
+		return
+	}
+	if f.From, err = parseTimeParam(q.Get("from"), false); err != nil {
+		helpers.Error(w, http.StatusBadRequest, "invalid from")
+		return
+	}
+	if f.To, err = parseTimeParam(q.Get("to"), true); err != nil {
+		helpers.Error(w, http.StatusBadRequest, "invalid to")
+		return
+	}
+	if v := q.Get("limit"); v != "" {
+		if f.Limit, err = strconv.Atoi(v); err != nil {
+			helpers.Error(w, http.StatusBadRequest, "invalid limit")
+			return
+		}
+	}
+	if v := q.Get("offset"); v != "" {
+		if f.Offset, err = strconv.Atoi(v); err != nil {
+			helpers.Error(w, http.StatusBadRequest, "invalid offset")
+			return
+		}
+	}
+
+	list, err := h.svc.GetAll(r.Context(), f)
+	if err != nil {
+		helpers.Error(w, http.StatusInternalServerError, "failed to load access log")
+		return
+	}
+	helpers.JSON(w, http.StatusOK, list)
+}
@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"eduBase/internal/access"
+	"eduBase/internal/helpers"
+	"eduBase/internal/middleware"
+	"eduBase/internal/models"
+	"eduBase/internal/repository"
+	"eduBase/internal/services"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// PromotionHandler — перевод на следующий учебный год
+type PromotionHandler struct {
+	svc *services.PromotionService
+}
+
+func NewPromotionHandler(svc *services.PromotionService) *PromotionHandler {
+	return &PromotionHandler{svc: svc}
+}
+
+func (h *PromotionHandler) Routes(r chi.Router) {
+	r.With(
+		middleware.RequirePermission(access.ClassesWrite),
+		middleware.RequirePermission(access.StudentsWrite),
+	).Post("/promotion", h.Promote)
+}
+
+// Promote godoc
+// @Summary      Перевод классов на следующий учебный год
+// @Description  Переводит классы текущего года в целевой: 5А → 6А (класс создаётся, если его ещё нет), ученики выпускного класса (11, в основной школе — 9) получают статус graduated.
+// @Description  repeaters остаются в классе той же параллели, leavers получают статус left. Школа переводит только свои классы, РОО — указанную школу или весь район.
+// @Description  С dry_run=true возвращает план без изменений. После перевода РОО делает целевой год текущим: PUT /roo/academic-years/{id}/current.
+// @Tags         Promotion
+// @Accept       json
+// @Produce      json
+// @Param        data body models.PromotionRequest true "Параметры перевода"
+// @Success      200 {object} models.PromotionResult
+// @Failure      400 {object} helpers.ErrorResponse
+// @Failure      403 {object} helpers.ErrorResponse
+// @Failure      409 {object} helpers.ErrorResponse "перевод уже выполнен или нет текущего года"
+// @Failure      500 {object} helpers.ErrorResponse
+// @Security     BearerAuth
+// @Router       /promotion [post]
+func (h *PromotionHandler) Promote(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	p := access.FromContext(r.Context())
+
+	var req models.PromotionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.TargetAcademicYearID == 0 {
+		helpers.Error(w, http.StatusBadRequest, "target_academic_year_id required")
+		return
+	}
+	if schoolID := p.SchoolScope(); schoolID != nil {
+		req.SchoolID = schoolID
+	}
+
+	res, err := h.svc.Promote(ctx, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrAcademicYearNotFound),
+			errors.Is(err, services.ErrInvalidTargetYear),
+			errors.Is(err, services.ErrPromotionStudent),
+			errors.Is(err, services.ErrPromotionStudentTwice):
+			helpers.Error(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, services.ErrAlreadyPromoted), errors.Is(err, repository.ErrNoCurrentYear):
+			helpers.Error(w, http.StatusConflict, err.Error())
+		default:
+			helpers.Error(w, http.StatusInternalServerError, "failed to promote classes")
+		}
+		return
+	}
+	helpers.JSON(w, http.StatusOK, res)
+}
@@ -1,9 +1,9 @@
 package handlers
 
 import (
-	"context"
+	"eduBase/internal/access"
 	"eduBase/internal/helpers"
-	"eduBase/internal/repository"
+	"eduBase/internal/middleware"
 	"eduBase/internal/services"
 	"encoding/json"
 	"net/http"
@@ -13,19 +13,18 @@ import (
 
 // RooHandler управляет действиями Районного отдела образования (ROO).
 type RooHandler struct {
-	svc        *services.AuthService
-	schoolRepo *repository.SchoolRepository
+	svc *services.AuthService
 }
 
 // NewRooHandler конструктор.
-func NewRooHandler(authSvc *services.AuthService, schoolRepo *repository.SchoolRepository) *RooHandler {
-	return &RooHandler{svc: authSvc, schoolRepo: schoolRepo}
+func NewRooHandler(authSvc *services.AuthService) *RooHandler {
+	return &RooHandler{svc: authSvc}
 }
 
 // Routes регистрирует роуты ROO.
 func (h *RooHandler) Routes(r chi.Router) {
 	r.Route("/roo", func(r chi.Router) {
-		r.Post("/register_school", h.RegisterSchool)
+		r.With(middleware.RequirePermission(access.SchoolsWrite)).Post("/register_school", h.RegisterSchool)
 	})
 }
 
@@ -57,11 +56,10 @@ func (h *RooHandler) RegisterSchool(w http.ResponseWriter, r *http.Request) {
 	}
 
 	password, err := h.svc.RegisterSchool(
-		context.Background(),
+		r.Context(),
 		req.Email,
 		req.Name,
 		req.Director,
-		h.schoolRepo,
 	)
 	if err != nil {
 		helpers.Error(w, http.StatusInternalServerError, "failed to register school")
@@ -1,37 +1,50 @@
 package handlers
 
 import (
-	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
 
+	"eduBase/internal/access"
 	"eduBase/internal/helpers"
+	"eduBase/internal/middleware"
 	"eduBase/internal/models"
+	"eduBase/internal/repository"
 	"eduBase/internal/services"
 	"github.com/go-chi/chi/v5"
 )
 
 type RooSchoolHandler struct {
-	svc *services.SchoolService
+	svc     *services.SchoolService
+	authSvc *services.AuthService
 }
 
-func NewRooSchoolHandler(svc *services.SchoolService) *RooSchoolHandler {
-	return &RooSchoolHandler{svc: svc}
+func NewRooSchoolHandler(svc *services.SchoolService, authSvc *services.AuthService) *RooSchoolHandler {
+	return &RooSchoolHandler{svc: svc, authSvc: authSvc}
 }
 
 func (h *RooSchoolHandler) Routes(r chi.Router) {
 	r.Route("/roo/schools", func(r chi.Router) {
-		r.Get("/", h.GetAll)
-		r.Get("/{id}", h.GetByID)
-		r.Put("/{id}", h.Update)
-		r.Delete("/{id}", h.Delete)
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.RequirePermission(access.SchoolsRead))
+			r.Get("/", h.GetAll)
+			r.Get("/{id}", h.GetByID)
+			r.Get("/export/xlsx", h.Export)
+		})
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.RequirePermission(access.SchoolsWrite))
+			r.Put("/{id}", h.Update)
+			r.Delete("/{id}", h.Delete)
+			r.Post("/{id}/reset-password", h.ResetPassword)
+			r.Post("/{id}/revoke-sessions", h.RevokeSessions)
+		})
 	})
 }
 
 // GetAll godoc
 // @Summary      Получить все школы
-// @Description  Возвращает список всех школ (только для ROO)
+// @Description  Возвращает список всех школ (РОО; чтение — также инспектор)
 // @Tags         Schools
 // @Produce      json
 // @Success      200 {array} models.School
@@ -39,7 +52,7 @@ func (h *RooSchoolHandler) Routes(r chi.Router) {
 // @Security     BearerAuth
 // @Router       /roo/schools [get]
 func (h *RooSchoolHandler) GetAll(w http.ResponseWriter, r *http.Request) {
-	list, err := h.svc.GetAll(context.Background())
+	list, err := h.svc.GetAll(r.Context())
 	if err != nil {
 		helpers.Error(w, http.StatusInternalServerError, "failed to load schools")
 		return
@@ -49,7 +62,7 @@ func (h *RooSchoolHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 
 // GetByID godoc
 // @Summary      Получить школу по ID
-// @Description  Возвращает данные конкретной школы (только для ROO)
+// @Description  Возвращает данные конкретной школы (РОО; чтение — также инспектор)
 // @Tags         Schools
 // @Produce      json
 // @Param        id path int true "ID школы"
@@ -60,7 +73,7 @@ func (h *RooSchoolHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 // @Router       /roo/schools/{id} [get]
 func (h *RooSchoolHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 	id, _ := strconv.Atoi(chi.URLParam(r, "id"))
-	school, err := h.svc.GetByID(context.Background(), id)
+	school, err := h.svc.GetByID(r.Context(), id)
 	if err != nil {
 		helpers.Error(w, http.StatusNotFound, "school not found")
 		return
@@ -88,7 +101,7 @@ func (h *RooSchoolHandler) Update(w http.ResponseWriter, r *http.Request) {
 		helpers.Error(w, http.StatusBadRequest, "invalid request body")
 		return
 	}
-	if err := h.svc.Update(context.Background(), id, &req); err != nil {
+	if err := h.svc.Update(r.Context(), id, &req); err != nil {
 		helpers.Error(w, http.StatusInternalServerError, "failed to update school")
 		return
 	}
@@ -97,19 +110,88 @@ func (h *RooSchoolHandler) Update(w http.ResponseWriter, r *http.Request) {
 
 // Delete godoc
 // @Summary      Удалить школу
-// @Description  Удаляет школу по ID (только для ROO)
+// @Description  Переносит школу с классами, сотрудниками и учениками в корзину (/trash) и отзывает сессии её сотрудников (только для ROO)
 // @Tags         Schools
 // @Produce      json
 // @Param        id path int true "ID школы"
 // @Success      200 {object} map[string]string
+// @Failure      404 {object} helpers.ErrorResponse
 // @Failure      500 {object} helpers.ErrorResponse
 // @Security     BearerAuth
 // @Router       /roo/schools/{id} [delete]
 func (h *RooSchoolHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	id, _ := strconv.Atoi(chi.URLParam(r, "id"))
-	if err := h.svc.Delete(context.Background(), id); err != nil {
+	if err := h.svc.Delete(r.Context(), id); err != nil {
+		if errors.Is(err, repository.ErrSchoolNotFound) {
+			helpers.Error(w, http.StatusNotFound, "school not found")
+			return
+		}
 		helpers.Error(w, http.StatusInternalServerError, "failed to delete school")
 		return
 	}
 	helpers.JSON(w, http.StatusOK, map[string]string{"status": "deleted"})
 }
+
+// ResetPasswordResponse — новый пароль школы, показывается один раз.
+type ResetPasswordResponse struct {
+	SchoolID int    `json:"school_id" example:"1"`
+	Email    string `json:"email" example:"school1@example.com"`
+	Password string `json:"password" example:"aB3dE5fG"`
+}
+
+// ResetPassword godoc
+// @Summary      Сбросить пароль школы
+// @Description  Генерирует новый пароль и возвращает его один раз; в базе хранится только хэш (только для ROO)
+// @Tags         Schools
+// @Produce      json
+// @Param        id path int true "ID школы"
+// @Success      200 {object} ResetPasswordResponse
+// @Failure      404 {object} helpers.ErrorResponse
+// @Failure      500 {object} helpers.ErrorResponse
+// @Security     BearerAuth
+// @Router       /roo/schools/{id}/reset-password [post]
+func (h *RooSchoolHandler) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.Atoi(chi.URLParam(r, "id"))
+	school, password, err := h.authSvc.ResetSchoolPassword(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, repository.ErrSchoolNotFound) {
+			helpers.Error(w, http.StatusNotFound, "school not found")
+			return
+		}
+		helpers.Error(w, http.StatusInternalServerError, "failed to reset password")
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	helpers.JSON(w, http.StatusOK, ResetPasswordResponse{
+		SchoolID: school.ID,
+		Email:    school.User.Email,
+		Password: password,
+	})
+}
+
+// RevokeSessions godoc
+// @Summary      Завершить все сессии школы
+// @Description  Отзывает все access/refresh токены учётной записи школы, например при компрометации (только для ROO)
+// @Tags         Schools
+// @Produce      json
+// @Param        id path int true "ID школы"
+// @Success      200 {object} map[string]string
+// @Failure      404 {object} helpers.ErrorResponse
+// @Failure      500 {object} helpers.ErrorResponse
+// @Security     BearerAuth
+// @Router       /roo/schools/{id}/revoke-sessions [post]
+func (h *RooSchoolHandler) RevokeSessions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, _ := strconv.Atoi(chi.URLParam(r, "id"))
+	school, err := h.svc.GetByID(ctx, id)
+	if err != nil {
+		helpers.Error(w, http.StatusNotFound, "school not found")
+		return
+	}
+	if err := h.authSvc.RevokeAllSessions(ctx, school.UserID); err != nil {
+		helpers.Error(w, http.StatusInternalServerError, "failed to revoke sessions")
+		return
+	}
+	helpers.JSON(w, http.StatusOK, map[string]string{"status": "sessions revoked"})
+}
@@ -1,36 +1,48 @@
 package handlers
 
 import (
-	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
 
+	"eduBase/internal/access"
 	"eduBase/internal/helpers"
+	"eduBase/internal/middleware"
 	"eduBase/internal/models"
 	"eduBase/internal/repository"
 	"eduBase/internal/services"
 
 	"github.com/go-chi/chi/v5"
-	"github.com/go-chi/jwtauth/v5"
 )
 
 type StaffHandler struct {
-	svc *services.StaffService
+	svc       *services.StaffService
+	timetable *services.TimetableService
 }
 
-func NewStaffHandler(svc *services.StaffService) *StaffHandler {
-	return &StaffHandler{svc: svc}
+func NewStaffHandler(svc *services.StaffService, timetable *services.TimetableService) *StaffHandler {
+	return &StaffHandler{svc: svc, timetable: timetable}
 }
 
 func (h *StaffHandler) Routes(r chi.Router) {
 	r.Route("/staff", func(r chi.Router) {
-		r.Get("/", h.GetAll)
-		r.Get("/{id}", h.GetByID)
-		r.Get("/stats", h.GetStats)
-		r.Post("/", h.Create)
-		r.Put("/{id}", h.Update)
-		r.Delete("/{id}", h.Delete)
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.RequirePermission(access.StaffRead))
+			r.Get("/", h.GetAll)
+			r.Get("/{id}", h.GetByID)
+			r.Get("/{id}/timetable", h.GetTimetable)
+			r.Get("/export/xlsx", h.Export)
+		})
+		r.With(middleware.RequirePermission(access.StatsRead), middleware.RequireDistrict).Get("/stats", h.GetStats)
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.RequirePermission(access.StaffWrite))
+			r.Post("/", h.Create)
+			r.Get("/import/template", h.ImportTemplate)
+			r.Post("/import", h.Import)
+			r.Put("/{id}", h.Update)
+			r.Delete("/{id}", h.Delete)
+		})
 	})
 }
 
@@ -46,11 +58,9 @@ func (h *StaffHandler) Routes(r chi.Router) {
 // @Failure 404 {object} helpers.ErrorResponse
 // @Router /staff/{id} [get]
 func (h *StaffHandler) GetByID(w http.ResponseWriter, r *http.Request) {
-	ctx := context.Background()
+	ctx := r.Context()
 	id, _ := strconv.Atoi(chi.URLParam(r, "id"))
-	_, claims, _ := jwtauth.FromContext(r.Context())
-	role := claims["role"].(string)
-	userID := int(claims["user_id"].(float64))
+	p := access.FromContext(r.Context())
 
 	staff, err := h.svc.GetByID(ctx, id)
 	if err != nil {
@@ -58,13 +68,9 @@ func (h *StaffHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if role == "school" {
-		schoolRepo := repository.NewSchoolRepository(h.svc.RepoDB())
-		school, err := schoolRepo.GetByUserID(ctx, userID)
-		if err != nil || staff.SchoolID != school.ID {
-			helpers.Error(w, http.StatusForbidden, "access denied")
-			return
-		}
+	if !p.CanAccessSchool(staff.SchoolID) {
+		helpers.Error(w, http.StatusForbidden, "access denied")
+		return
 	}
 
 	helpers.JSON(w, http.StatusOK, staff)
@@ -80,15 +86,14 @@ func (h *StaffHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 // @Param data body models.Staff true "Обновлённые данные"
 // @Security BearerAuth
 // @Success 200 {object} map[string]string
+// @Failure 400 {object} helpers.ErrorResponse
 // @Failure 403 {object} helpers.ErrorResponse
 // @Failure 404 {object} helpers.ErrorResponse
 // @Router /staff/{id} [put]
 func (h *StaffHandler) Update(w http.ResponseWriter, r *http.Request) {
-	ctx := context.Background()
+	ctx := r.Context()
 	id, _ := strconv.Atoi(chi.URLParam(r, "id"))
-	_, claims, _ := jwtauth.FromContext(r.Context())
-	role := claims["role"].(string)
-	userID := int(claims["user_id"].(float64))
+	p := access.FromContext(r.Context())
 
 	var s models.Staff
 	if err := json.NewDecoder(r.Body).Decode(&s); err != nil {
@@ -101,17 +106,11 @@ func (h *StaffHandler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if role == "school" {
-		schoolRepo := repository.NewSchoolRepository(h.svc.RepoDB())
-		school, err := schoolRepo.GetByUserID(ctx, userID)
-		if err != nil {
-			helpers.Error(w, http.StatusForbidden, "school not found")
-			return
-		}
-		s.SchoolID = school.ID
+	ok, err := h.svc.Update(ctx, id, &s, p.SchoolScope())
+	if errors.Is(err, services.ErrInvalidBirthDate) {
+		helpers.Error(w, http.StatusBadRequest, err.Error())
+		return
 	}
-
-	ok, err := h.svc.Update(ctx, id, &s, role)
 	if err != nil {
 		helpers.Error(w, http.StatusInternalServerError, "failed to update staff")
 		return
@@ -126,21 +125,16 @@ func (h *StaffHandler) Update(w http.ResponseWriter, r *http.Request) {
 
 // GetStats godoc
 // @Summary Получить статистику по персоналу (ROO)
-// @Description Кол-во сотрудников по должностям
+// @Description Кол-во сотрудников по должностям. Устарело: кадровый состав — /stats/staff
 // @Tags Staff
+// @Deprecated
 // @Produce json
 // @Security BearerAuth
 // @Success 200 {object} map[string]int
 // @Failure 403 {object} helpers.ErrorResponse
 // @Router /staff/stats [get]
 func (h *StaffHandler) GetStats(w http.ResponseWriter, r *http.Request) {
-	_, claims, _ := jwtauth.FromContext(r.Context())
-	role := claims["role"].(string)
-	if role != "roo" {
-		helpers.Error(w, http.StatusForbidden, "access denied")
-		return
-	}
-	stats, err := h.svc.GetStats(context.Background())
+	stats, err := h.svc.GetStats(r.Context())
 	if err != nil {
 		helpers.Error(w, http.StatusInternalServerError, "failed to get stats")
 		return
@@ -149,31 +143,23 @@ func (h *StaffHandler) GetStats(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *StaffHandler) GetAll(w http.ResponseWriter, r *http.Request) {
-	ctx := context.Background()
-	_, claims, _ := jwtauth.FromContext(r.Context())
-	role := claims["role"].(string)
-	userID := int(claims["user_id"].(float64))
-
-	var schoolID *int
+	ctx := r.Context()
 
 	// 🔒 Школа видит только свои данные
-	if role == "school" {
-		schoolRepo := repository.NewSchoolRepository(h.svc.RepoDB())
-		school, err := schoolRepo.GetByUserID(ctx, userID)
-		if err != nil {
-			helpers.Error(w, http.StatusForbidden, "school not found")
-			return
-		}
-		schoolID = &school.ID
-	}
+	schoolID := access.FromContext(r.Context()).SchoolScope()
 
-	if role != "roo" && role != "school" {
-		helpers.Error(w, http.StatusForbidden, "access denied")
+	list, err := h.svc.GetAll(ctx, schoolID, staffFilter(r))
+	if err != nil {
+		helpers.Error(w, http.StatusInternalServerError, "failed to get staff")
 		return
 	}
 
-	// Фильтры
-	filter := repository.StaffFilter{
+	helpers.JSON(w, http.StatusOK, list)
+}
+
+// staffFilter — фильтры списка сотрудников из строки запроса
+func staffFilter(r *http.Request) repository.StaffFilter {
+	return repository.StaffFilter{
 		FullName:  r.URL.Query().Get("full_name"),
 		Phone:     r.URL.Query().Get("phone"),
 		Position:  r.URL.Query().Get("position"),
@@ -181,19 +167,11 @@ func (h *StaffHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 		Education: r.URL.Query().Get("education"),
 		Category:  r.URL.Query().Get("category"),
 	}
-
-	list, err := h.svc.GetAll(ctx, schoolID, filter)
-	if err != nil {
-		helpers.Error(w, http.StatusInternalServerError, "failed to get staff")
-		return
-	}
-
-	helpers.JSON(w, http.StatusOK, list)
 }
 
 // Create godoc
 // @Summary Добавить сотрудника
-// @Description Только школа может добавлять своих сотрудников
+// @Description Только сотрудники школы с правом staff:write добавляют сотрудников своей школы
 // @Tags Staff
 // @Accept json
 // @Produce json
@@ -205,12 +183,10 @@ func (h *StaffHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 // @Failure 500 {object} helpers.ErrorResponse
 // @Router /staff [post]
 func (h *StaffHandler) Create(w http.ResponseWriter, r *http.Request) {
-	ctx := context.Background()
-	_, claims, _ := jwtauth.FromContext(r.Context())
-	role := claims["role"].(string)
-	userID := int(claims["user_id"].(float64))
+	ctx := r.Context()
+	p := access.FromContext(r.Context())
 
-	if role != "school" {
+	if p.SchoolID == nil {
 		helpers.Error(w, http.StatusForbidden, "only schools can add staff")
 		return
 	}
@@ -226,15 +202,13 @@ func (h *StaffHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	schoolRepo := repository.NewSchoolRepository(h.svc.RepoDB())
-	school, err := schoolRepo.GetByUserID(ctx, userID)
-	if err != nil {
-		helpers.Error(w, http.StatusForbidden, "school not found")
-		return
-	}
-	s.SchoolID = school.ID
+	s.SchoolID = *p.SchoolID
 
 	if err := h.svc.Create(ctx, &s); err != nil {
+		if errors.Is(err, services.ErrInvalidBirthDate) {
+			helpers.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
 		helpers.Error(w, http.StatusInternalServerError, "failed to create staff")
 		return
 	}
@@ -244,36 +218,29 @@ func (h *StaffHandler) Create(w http.ResponseWriter, r *http.Request) {
 
 // Delete godoc
 // @Summary Удалить сотрудника
-// @Description School — только своего, ROO — любого
+// @Description Переносит сотрудника в корзину (/trash). Школа — только своего, РОО — любого
 // @Tags Staff
 // @Param id path int true "ID сотрудника"
 // @Security BearerAuth
 // @Success 200 {object} map[string]string
 // @Failure 403 {object} helpers.ErrorResponse
+// @Failure 404 {object} helpers.ErrorResponse
 // @Failure 500 {object} helpers.ErrorResponse
 // @Router /staff/{id} [delete]
 func (h *StaffHandler) Delete(w http.ResponseWriter, r *http.Request) {
-	ctx := context.Background()
-	_, claims, _ := jwtauth.FromContext(r.Context())
-	role := claims["role"].(string)
-	userID := int(claims["user_id"].(float64))
+	ctx := r.Context()
+	p := access.FromContext(r.Context())
 
 	id, _ := strconv.Atoi(chi.URLParam(r, "id"))
-	schoolID := 0
-
-	if role == "school" {
-		schoolRepo := repository.NewSchoolRepository(h.svc.RepoDB())
-		school, err := schoolRepo.GetByUserID(ctx, userID)
-		if err != nil {
-			helpers.Error(w, http.StatusForbidden, "school not found")
-			return
-		}
-		schoolID = school.ID
-	}
 
-	if err := h.svc.Delete(ctx, id, schoolID); err != nil {
+	ok, err := h.svc.Delete(ctx, id, p.SchoolScope())
+	if err != nil {
 		helpers.Error(w, http.StatusInternalServerError, "failed to delete staff")
 		return
 	}
+	if !ok {
+		helpers.Error(w, http.StatusNotFound, "staff not found or not yours")
+		return
+	}
 	helpers.JSON(w, http.StatusOK, map[string]string{"status": "deleted"})
 }
@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"eduBase/internal/access"
+	"eduBase/internal/helpers"
+	"eduBase/internal/repository"
+	"eduBase/internal/services"
+	"eduBase/internal/utils"
+)
+
+// ImportTemplate godoc
+// @Summary Шаблон загрузки сотрудников (xlsx)
+// @Description Лист «Сотрудники» — заголовок для заполнения, лист «Пример» — пример строки; обязательные колонки отмечены «*».
+// @Description Стаж — целое число лет, даты рождения и начала работы — ДД.ММ.ГГГГ или дата Excel.
+// @Tags Staff
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Security BearerAuth
+// @Success 200 {file} file "xlsx"
+// @Router /staff/import/template [get]
+func (h *StaffHandler) ImportTemplate(w http.ResponseWriter, r *http.Request) {
+	writeXLSX(w, "staff_template.xlsx",
+		utils.Sheet{Name: "Сотрудники", Header: services.StaffImportColumns},
+		utils.Sheet{Name: "Пример", Header: services.StaffImportColumns, Rows: [][]any{services.StaffImportExample}},
+	)
+}
+
+// Import godoc
+// @Summary Загрузка сотрудников из xlsx или csv
+// @Description Файл по шаблону /staff/import/template (csv — в UTF-8 с теми же колонками, разделитель «;» или «,»), читается первый лист.
+// @Description Строка, совпавшая с сотрудником школы по телефону (match=phone, по умолчанию) или ФИО (match=full_name), обновляет его заполненными ячейками; остальные добавляются.
+// @Description При любой ошибке не загружается ничего (422 с ошибками по строкам); dry_run=true только проверяет файл.
+// @Description Школа загружает своих сотрудников, РОО — сотрудников школы school_id.
+// @Tags Staff
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "Файл xlsx или csv"
+// @Param match query string false "Сопоставление с существующими: phone или full_name" default(phone)
+// @Param school_id query int false "ID школы (обязателен для РОО)"
+// @Param dry_run query bool false "Только проверить, не загружать"
+// @Security BearerAuth
+// @Success 200 {object} models.ImportReport "dry_run: файл без ошибок"
+// @Success 201 {object} models.ImportReport "сотрудники загружены"
+// @Failure 400 {object} helpers.ErrorResponse
+// @Failure 403 {object} helpers.ErrorResponse
+// @Failure 422 {object} models.ImportReport
+// @Failure 500 {object} helpers.ErrorResponse
+// @Router /staff/import [post]
+func (h *StaffHandler) Import(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	p := access.FromContext(r.Context())
+	q := r.URL.Query()
+
+	schoolID := p.SchoolScope()
+	if schoolID == nil {
+		id, err := strconv.Atoi(q.Get("school_id"))
+		if err != nil || id <= 0 {
+			helpers.Error(w, http.StatusBadRequest, "school_id required")
+			return
+		}
+		exists, err := repository.NewStatsRepository(h.svc.RepoDB()).SchoolExists(ctx, id)
+		if err != nil {
+			helpers.Error(w, http.StatusInternalServerError, "db error")
+			return
+		}
+		if !exists {
+			helpers.Error(w, http.StatusBadRequest, "school not found")
+			return
+		}
+		schoolID = &id
+	}
+	match := q.Get("match")
+	if match == "" {
+		match = services.StaffMatchPhone
+	}
+	dryRun := q.Get("dry_run") == "true"
+	rows, ok := readImportFile(w, r)
+	if !ok {
+		return
+	}
+
+	report, err := h.svc.Import(ctx, *schoolID, rows, match, dryRun)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidStaffMatch) || errors.Is(err, services.ErrImportEmpty) || errors.Is(err, services.ErrImportTooLarge) {
+			helpers.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		helpers.Error(w, http.StatusInternalServerError, "failed to import staff")
+		return
+	}
+
+	switch {
+	case len(report.Errors) > 0:
+		helpers.JSON(w, http.StatusUnprocessableEntity, report)
+	case dryRun:
+		helpers.JSON(w, http.StatusOK, report)
+	default:
+		helpers.JSON(w, http.StatusCreated, report)
+	}
+}
@@ -1,16 +1,19 @@
 package handlers
 
 import (
-	"context"
+	"errors"
 	"net/http"
 	"strconv"
+	"time"
 
+	"eduBase/internal/access"
 	"eduBase/internal/helpers"
+	"eduBase/internal/middleware"
+	"eduBase/internal/models"
 	"eduBase/internal/repository"
 	"eduBase/internal/services"
 
 	"github.com/go-chi/chi/v5"
-	"github.com/go-chi/jwtauth/v5"
 )
 
 // StatsHandler — агрегаты по классам/ученикам/учителям
@@ -24,16 +27,25 @@ func NewStatsHandler(svc *services.StatsService) *StatsHandler {
 
 func (h *StatsHandler) Routes(r chi.Router) {
 	r.Route("/stats", func(r chi.Router) {
+		r.Use(middleware.RequirePermission(access.StatsRead))
 		r.Get("/summary", h.Summary)
+		r.Get("/attendance", h.Attendance)
+		r.Get("/teaching-load", h.TeachingLoad)
+		r.Get("/performance", h.Performance)
+		r.Get("/students", h.Students)
+		r.Get("/staff", h.Staff)
+		r.Get("/history", h.History)
+		r.Get("/oo1", h.OO1)
+		r.Get("/oo1/xlsx", h.OO1XLSX)
 	})
 }
 
 // Summary godoc
 // @Summary Сводная статистика (кол-во классов, учеников, учителей)
-// @Description ROO — вся система или по school_id; School — только своя школа (параметр игнорируется)
+// @Description РОО и инспектор — весь район или по school_id; школа — только своя (параметр игнорируется)
 // @Tags Stats
 // @Produce json
-// @Param school_id query int false "Фильтрация по школе (только для ROO)"
+// @Param school_id query int false "Фильтрация по школе (только для РОО и инспектора)"
 // @Security BearerAuth
 // @Success 200 {object} models.StatsSummary "schools, classes, students, teachers, staff_total"
 // @Failure 400 {object} helpers.ErrorResponse
@@ -41,58 +53,320 @@ func (h *StatsHandler) Routes(r chi.Router) {
 // @Failure 500 {object} helpers.ErrorResponse
 // @Router /stats/summary [get]
 func (h *StatsHandler) Summary(w http.ResponseWriter, r *http.Request) {
-	ctx := context.Background()
-	_, claims, _ := jwtauth.FromContext(r.Context())
-	role := claims["role"].(string)
-	userID := int(claims["user_id"].(float64))
-
-	var schoolID *int
-
-	// ROO: может запросить ?school_id=..., иначе вся система
-	if role == "roo" {
-		if v := r.URL.Query().Get("school_id"); v != "" {
-			id, err := strconv.Atoi(v)
-			if err != nil || id <= 0 {
-				helpers.Error(w, http.StatusBadRequest, "invalid school_id")
-				return
-			}
-			// валидация наличия школы
-			exists, err := h.svc.RepoDB().Query(ctx, `SELECT 1`) // ping-like
-			if err != nil {
-				helpers.Error(w, http.StatusInternalServerError, "db error")
-				return
-			}
-			exists.Close() // просто закрываем курсор, без присваивания
-
-			ok, err := repository.NewStatsRepository(h.svc.RepoDB()).SchoolExists(ctx, id)
-			if err != nil {
-				helpers.Error(w, http.StatusInternalServerError, "db error")
-				return
-			}
-			if !ok {
-				helpers.Error(w, http.StatusBadRequest, "school not found")
-				return
-			}
-			schoolID = &id
-		}
-	} else if role == "school" {
-		// School: игнорируем переданный school_id, берём свой по user_id
-		sRepo := repository.NewSchoolRepository(h.svc.RepoDB())
-		school, err := sRepo.GetByUserID(ctx, userID)
-		if err != nil {
-			helpers.Error(w, http.StatusForbidden, "school not found")
+	ctx := r.Context()
+
+	schoolID, ok := h.resolveSchool(w, r)
+	if !ok {
+		return
+	}
+
+	res, err := h.svc.GetSummary(ctx, schoolID)
+	if err != nil {
+		helpers.Error(w, http.StatusInternalServerError, "failed to get stats")
+		return
+	}
+	helpers.JSON(w, http.StatusOK, res)
+}
+
+// maxAttendanceDays — наибольший период сводки посещаемости
+const maxAttendanceDays = 92
+
+// Attendance godoc
+// @Summary Посещаемость по школам и дням
+// @Description Доля присутствовавших (present + late) от отмеченных учеников по каждой школе за каждый день периода.
+// @Description РОО и инспектор — весь район или по school_id; школа — только своя. По умолчанию — последние 7 дней, не больше 92 дней.
+// @Tags Stats
+// @Produce json
+// @Param school_id query int false "Фильтрация по школе (только для РОО и инспектора)"
+// @Param from query string false "Начало периода YYYY-MM-DD"
+// @Param to query string false "Конец периода YYYY-MM-DD (включительно, по умолчанию сегодня)"
+// @Security BearerAuth
+// @Success 200 {array} models.AttendanceDay
+// @Failure 400 {object} helpers.ErrorResponse
+// @Failure 403 {object} helpers.ErrorResponse
+// @Failure 500 {object} helpers.ErrorResponse
+// @Router /stats/attendance [get]
+func (h *StatsHandler) Attendance(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	schoolID, ok := h.resolveSchool(w, r)
+	if !ok {
+		return
+	}
+	to, err := parseDateParam(r.URL.Query().Get("to"))
+	if err != nil {
+		helpers.Error(w, http.StatusBadRequest, "invalid to")
+		return
+	}
+	from := to.AddDate(0, 0, -6)
+	if v := r.URL.Query().Get("from"); v != "" {
+		if from, err = time.Parse(time.DateOnly, v); err != nil {
+			helpers.Error(w, http.StatusBadRequest, "invalid from")
 			return
 		}
-		schoolID = &school.ID
-	} else {
-		helpers.Error(w, http.StatusForbidden, "access denied")
+	}
+	if from.After(to) || to.Sub(from) >= maxAttendanceDays*24*time.Hour {
+		helpers.Error(w, http.StatusBadRequest, "period must be from 1 to 92 days")
 		return
 	}
 
-	res, err := h.svc.GetSummary(ctx, schoolID)
+	res, err := h.svc.GetAttendance(ctx, schoolID, from, to)
+	if err != nil {
+		helpers.Error(w, http.StatusInternalServerError, "failed to get stats")
+		return
+	}
+	helpers.JSON(w, http.StatusOK, res)
+}
+
+// TeachingLoad godoc
+// @Summary Нагрузка учителей и нехватка кадров по предметам
+// @Description Недельные часы каждого учителя по назначениям текущего учебного года и отклонение от ставки (18 ч),
+// @Description вакантные часы (назначения без учителя) по школам и предметам и сколько учителей нужно району по каждому предмету.
+// @Description РОО и инспектор — весь район или по school_id; школа — только своя.
+// @Tags Stats
+// @Produce json
+// @Param school_id query int false "Фильтрация по школе (только для РОО и инспектора)"
+// @Security BearerAuth
+// @Success 200 {object} models.TeachingLoad
+// @Failure 400 {object} helpers.ErrorResponse
+// @Failure 403 {object} helpers.ErrorResponse
+// @Failure 500 {object} helpers.ErrorResponse
+// @Router /stats/teaching-load [get]
+func (h *StatsHandler) TeachingLoad(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	schoolID, ok := h.resolveSchool(w, r)
+	if !ok {
+		return
+	}
+
+	res, err := h.svc.GetTeachingLoad(ctx, schoolID)
+	if err != nil {
+		helpers.Error(w, http.StatusInternalServerError, "failed to get stats")
+		return
+	}
+	helpers.JSON(w, http.StatusOK, res)
+}
+
+// Performance godoc
+// @Summary Успеваемость и качество знаний
+// @Description По итоговым отметкам периода: успеваемость — доля учеников без «2», качество знаний — доля учеников только на «4» и «5».
+// @Description Ученик учитывается, если у него есть хотя бы одна итоговая отметка за период. Разрезы — классы, школы и район (только для всего района).
+// @Description РОО и инспектор — весь район или по school_id; школа — только своя.
+// @Tags Stats
+// @Produce json
+// @Param school_id query int false "Фильтрация по школе (только для РОО и инспектора)"
+// @Param period query string false "Период: q1–q4, h1, h2, year (по умолчанию year)"
+// @Param academic_year query int false "ID учебного года (по умолчанию текущий)"
+// @Security BearerAuth
+// @Success 200 {object} models.PerformanceStats
+// @Failure 400 {object} helpers.ErrorResponse
+// @Failure 403 {object} helpers.ErrorResponse
+// @Failure 409 {object} helpers.ErrorResponse
+// @Failure 500 {object} helpers.ErrorResponse
+// @Router /stats/performance [get]
+func (h *StatsHandler) Performance(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	schoolID, ok := h.resolveSchool(w, r)
+	if !ok {
+		return
+	}
+	period := r.URL.Query().Get("period")
+	if period == "" {
+		period = models.PeriodYear
+	}
+	if !services.ValidPeriod(period) {
+		helpers.Error(w, http.StatusBadRequest, services.ErrInvalidPeriod.Error())
+		return
+	}
+	yearID, err := queryInt(r.URL.Query(), "academic_year")
+	if err != nil {
+		helpers.Error(w, http.StatusBadRequest, "invalid academic_year")
+		return
+	}
+
+	res, err := h.svc.GetPerformance(ctx, schoolID, yearID, period)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrAcademicYearNotFound):
+			helpers.Error(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, repository.ErrNoCurrentYear):
+			helpers.Error(w, http.StatusConflict, err.Error())
+		default:
+			helpers.Error(w, http.StatusInternalServerError, "failed to get stats")
+		}
+		return
+	}
+	helpers.JSON(w, http.StatusOK, res)
+}
+
+// Students godoc
+// @Summary Состав учеников
+// @Description Возрастная пирамида (полных лет на дату, по полу), пол по параллелям, наполняемость классов по интервалам и разрез по школам.
+// @Description Ученики — по зачислениям учебного года: для текущего года — только обучающиеся, для прошлых — все зачисленные в тот год.
+// @Description РОО и инспектор — весь район или по school_id; школа — только своя.
+// @Tags Stats
+// @Produce json
+// @Param school_id query int false "Фильтрация по школе (только для РОО и инспектора)"
+// @Param grade query int false "Параллель"
+// @Param academic_year query int false "ID учебного года (по умолчанию текущий)"
+// @Param date query string false "Дата, на которую считается возраст, YYYY-MM-DD (по умолчанию сегодня)"
+// @Security BearerAuth
+// @Success 200 {object} models.StudentStats
+// @Failure 400 {object} helpers.ErrorResponse
+// @Failure 403 {object} helpers.ErrorResponse
+// @Failure 409 {object} helpers.ErrorResponse
+// @Failure 500 {object} helpers.ErrorResponse
+// @Router /stats/students [get]
+func (h *StatsHandler) Students(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	schoolID, ok := h.resolveSchool(w, r)
+	if !ok {
+		return
+	}
+	grade, err := queryInt(r.URL.Query(), "grade")
 	if err != nil {
+		helpers.Error(w, http.StatusBadRequest, "invalid grade")
+		return
+	}
+	yearID, err := queryInt(r.URL.Query(), "academic_year")
+	if err != nil {
+		helpers.Error(w, http.StatusBadRequest, "invalid academic_year")
+		return
+	}
+	date, err := parseDateParam(r.URL.Query().Get("date"))
+	if err != nil {
+		helpers.Error(w, http.StatusBadRequest, "invalid date")
+		return
+	}
+
+	res, err := h.svc.GetStudentStats(ctx, schoolID, grade, yearID, date)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrAcademicYearNotFound):
+			helpers.Error(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, repository.ErrNoCurrentYear):
+			helpers.Error(w, http.StatusConflict, err.Error())
+		default:
+			helpers.Error(w, http.StatusInternalServerError, "failed to get stats")
+		}
+		return
+	}
+	helpers.JSON(w, http.StatusOK, res)
+}
+
+// Staff godoc
+// @Summary Кадровый состав
+// @Description Учителя по квалификационной категории, уровню образования и педагогическому стажу (до 3, 3–5, 5–10, 10–20, 20 лет и более),
+// @Description предпенсионного и пенсионного возраста (55 лет и старше) и молодые специалисты (стаж меньше 3 лет, моложе 35 лет); доли — в процентах от учителей.
+// @Description Учитель — сотрудник с должностью «учитель» или с назначениями текущего учебного года. Разрез по школам.
+// @Description РОО и инспектор — весь район или по school_id; школа — только своя.
+// @Tags Stats
+// @Produce json
+// @Param school_id query int false "Фильтрация по школе (только для РОО и инспектора)"
+// @Security BearerAuth
+// @Success 200 {object} models.StaffStats
+// @Failure 400 {object} helpers.ErrorResponse
+// @Failure 403 {object} helpers.ErrorResponse
+// @Failure 500 {object} helpers.ErrorResponse
+// @Router /stats/staff [get]
+func (h *StatsHandler) Staff(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	schoolID, ok := h.resolveSchool(w, r)
+	if !ok {
+		return
+	}
+
+	res, err := h.svc.GetStaffStats(ctx, schoolID)
+	if err != nil {
+		helpers.Error(w, http.StatusInternalServerError, "failed to get stats")
+		return
+	}
+	helpers.JSON(w, http.StatusOK, res)
+}
+
+// History godoc
+// @Summary Динамика сводной статистики
+// @Description Значения показателя по ежедневным (или ежемесячным — STATS_SNAPSHOT_PERIOD) снимкам за период, по возрастанию даты.
+// @Description Показатели: schools, classes, students, teachers, staff_total. По умолчанию — последний год.
+// @Description РОО и инспектор — весь район или по school_id; школа — только своя.
+// @Tags Stats
+// @Produce json
+// @Param metric query string true "Показатель: schools, classes, students, teachers, staff_total"
+// @Param school_id query int false "Фильтрация по школе (только для РОО и инспектора)"
+// @Param from query string false "Начало периода YYYY-MM-DD (по умолчанию год назад)"
+// @Param to query string false "Конец периода YYYY-MM-DD (включительно, по умолчанию сегодня)"
+// @Security BearerAuth
+// @Success 200 {object} models.StatsHistory
+// @Failure 400 {object} helpers.ErrorResponse
+// @Failure 403 {object} helpers.ErrorResponse
+// @Failure 500 {object} helpers.ErrorResponse
+// @Router /stats/history [get]
+func (h *StatsHandler) History(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	schoolID, ok := h.resolveSchool(w, r)
+	if !ok {
+		return
+	}
+	to, err := parseDateParam(r.URL.Query().Get("to"))
+	if err != nil {
+		helpers.Error(w, http.StatusBadRequest, "invalid to")
+		return
+	}
+	from := to.AddDate(-1, 0, 0)
+	if v := r.URL.Query().Get("from"); v != "" {
+		if from, err = time.Parse(time.DateOnly, v); err != nil {
+			helpers.Error(w, http.StatusBadRequest, "invalid from")
+			return
+		}
+	}
+
+	res, err := h.svc.GetHistory(ctx, r.URL.Query().Get("metric"), schoolID, from, to)
+	if err != nil {
+		if errors.Is(err, repository.ErrUnknownMetric) || errors.Is(err, services.ErrInvalidHistoryRange) {
+			helpers.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
 		helpers.Error(w, http.StatusInternalServerError, "failed to get stats")
 		return
 	}
 	helpers.JSON(w, http.StatusOK, res)
 }
+
+// resolveSchool определяет школу для статистики:
+// РОО/инспектор — ?school_id=... или весь район (nil), сотрудник школы — всегда своя школа.
+// При ошибке пишет ответ и возвращает ok=false.
+func (h *StatsHandler) resolveSchool(w http.ResponseWriter, r *http.Request) (*int, bool) {
+	ctx := r.Context()
+	p := access.FromContext(r.Context())
+
+	if !p.IsDistrict() {
+		// School: игнорируем переданный school_id, берём свой
+		return p.SchoolScope(), true
+	}
+
+	v := r.URL.Query().Get("school_id")
+	if v == "" {
+		return nil, true
+	}
+	id, err := strconv.Atoi(v)
+	if err != nil || id <= 0 {
+		helpers.Error(w, http.StatusBadRequest, "invalid school_id")
+		return nil, false
+	}
+	// валидация наличия школы
+	exists, err := repository.NewStatsRepository(h.svc.RepoDB()).SchoolExists(ctx, id)
+	if err != nil {
+		helpers.Error(w, http.StatusInternalServerError, "db error")
+		return nil, false
+	}
+	if !exists {
+		helpers.Error(w, http.StatusBadRequest, "school not found")
+		return nil, false
+	}
+	return &id, true
+}
@@ -1,44 +1,81 @@
 package handlers
 
 import (
-	"context"
+	"encoding/csv"
 	"encoding/json"
-	"fmt"
+	"errors"
 	"net/http"
 	"strconv"
 	"time"
 
+	"eduBase/internal/access"
 	"eduBase/internal/helpers"
+	"eduBase/internal/middleware"
 	"eduBase/internal/models"
 	"eduBase/internal/repository"
 	"eduBase/internal/services"
 
 	"github.com/go-chi/chi/v5"
-	"github.com/go-chi/jwtauth/v5"
 )
 
 type StudentHandler struct {
-	svc *services.StudentService
+	svc       *services.StudentService
+	guardians *services.GuardianService
+	marks     *services.MarkService
+	pd        *services.PDAccessService
 }
 
-func NewStudentHandler(svc *services.StudentService) *StudentHandler {
-	return &StudentHandler{svc: svc}
+func NewStudentHandler(svc *services.StudentService, guardians *services.GuardianService, marks *services.MarkService, pd *services.PDAccessService) *StudentHandler {
+	return &StudentHandler{svc: svc, guardians: guardians, marks: marks, pd: pd}
+}
+
+// studentAccessFilter — фильтр выборки, сохраняемый в журнале доступа к ПДн
+type studentAccessFilter struct {
+	SchoolID *int `json:"school_id,omitempty"`
+	repository.StudentFilter
+}
+
+func studentIDs(list []models.Student) []int {
+	ids := make([]int, 0, len(list))
+	for _, s := range list {
+		ids = append(ids, s.ID)
+	}
+	return ids
 }
 
 func (h *StudentHandler) Routes(r chi.Router) {
 	r.Route("/students", func(r chi.Router) {
-		r.Get("/", h.GetAll)
-		r.Get("/{id}", h.GetByID)
-		r.Get("/stats", h.GetStats)
-		r.Get("/export", h.ExportCSV)
-		r.Post("/", h.Create)
-		r.Put("/{id}", h.Update)
-		r.Delete("/{id}", h.Delete)
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.RequirePermission(access.StudentsRead))
+			r.Get("/", h.GetAll)
+			r.Get("/{id}", h.GetByID)
+			r.Get("/{id}/movements", h.GetMovements)
+			r.Get("/{id}/guardians", h.GetGuardians)
+			r.Get("/{id}/marks", h.GetMarks)
+		})
+		r.With(middleware.RequirePermission(access.StatsRead), middleware.RequireDistrict).Get("/stats", h.GetStats)
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.RequirePermission(access.StudentsExport))
+			r.Get("/export", h.ExportCSV)
+			r.Get("/export/xlsx", h.ExportXLSX)
+		})
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.RequirePermission(access.StudentsWrite))
+			r.Post("/", h.Create)
+			r.Get("/import/template", h.ImportTemplate)
+			r.Post("/import", h.Import)
+			r.Put("/{id}", h.Update)
+			r.Delete("/{id}", h.Delete)
+			r.Post("/{id}/guardians", h.AddGuardian)
+			r.Put("/{id}/guardians/{guardianID}", h.UpdateGuardian)
+			r.Delete("/{id}/guardians/{guardianID}", h.RemoveGuardian)
+		})
 	})
 }
 
 // GetByID godoc
 // @Summary Получить ученика по ID
+// @Description Просмотр фиксируется в журнале доступа к персональным данным
 // @Tags Students
 // @Produce json
 // @Param id path int true "ID ученика"
@@ -48,11 +85,9 @@ func (h *StudentHandler) Routes(r chi.Router) {
 // @Failure 404 {object} helpers.ErrorResponse
 // @Router /students/{id} [get]
 func (h *StudentHandler) GetByID(w http.ResponseWriter, r *http.Request) {
-	ctx := context.Background()
+	ctx := r.Context()
 	id, _ := strconv.Atoi(chi.URLParam(r, "id"))
-	_, claims, _ := jwtauth.FromContext(r.Context())
-	role := claims["role"].(string)
-	userID := int(claims["user_id"].(float64))
+	p := access.FromContext(r.Context())
 
 	st, err := h.svc.GetByID(ctx, id)
 	if err != nil {
@@ -60,16 +95,62 @@ func (h *StudentHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if role == "school" {
-		schoolRepo := repository.NewSchoolRepository(h.svc.SchoolRepoDB())
-		school, err := schoolRepo.GetByUserID(ctx, userID)
-		if err != nil || st.SchoolID != school.ID {
+	if !p.CanAccessClass(st.SchoolID, st.ClassID) {
+		helpers.Error(w, http.StatusForbidden, "access denied")
+		return
+	}
+
+	if err := h.pd.Log(ctx, services.PDActionView, []int{st.ID}, nil); err != nil {
+		helpers.Error(w, http.StatusInternalServerError, "failed to log access")
+		return
+	}
+	helpers.JSON(w, http.StatusOK, st)
+}
+
+// GetMovements godoc
+// @Summary Книга движения ученика
+// @Description Прибытия, выбытия и смены класса. Историю видят РОО и все школы, в которых ученик числился; учитель — только для своего класса.
+// @Description Просмотр фиксируется в журнале доступа к персональным данным
+// @Tags Students
+// @Produce json
+// @Param id path int true "ID ученика"
+// @Security BearerAuth
+// @Success 200 {array} models.StudentMovement
+// @Failure 403 {object} helpers.ErrorResponse
+// @Failure 404 {object} helpers.ErrorResponse
+// @Failure 500 {object} helpers.ErrorResponse
+// @Router /students/{id}/movements [get]
+func (h *StudentHandler) GetMovements(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, _ := strconv.Atoi(chi.URLParam(r, "id"))
+	p := access.FromContext(r.Context())
+
+	if p.ClassScope() != nil {
+		st, err := h.svc.GetByID(ctx, id)
+		if err != nil {
+			helpers.Error(w, http.StatusNotFound, "student not found")
+			return
+		}
+		if !p.CanAccessClass(st.SchoolID, st.ClassID) {
 			helpers.Error(w, http.StatusForbidden, "access denied")
 			return
 		}
 	}
 
-	helpers.JSON(w, http.StatusOK, st)
+	list, err := h.svc.Movements(ctx, id, p.SchoolScope())
+	if err != nil {
+		helpers.Error(w, http.StatusInternalServerError, "failed to get movements")
+		return
+	}
+	if len(list) == 0 {
+		helpers.Error(w, http.StatusNotFound, "student not found")
+		return
+	}
+	if err := h.pd.Log(ctx, services.PDActionView, []int{id}, nil); err != nil {
+		helpers.Error(w, http.StatusInternalServerError, "failed to log access")
+		return
+	}
+	helpers.JSON(w, http.StatusOK, list)
 }
 
 // Update godoc
@@ -86,11 +167,9 @@ func (h *StudentHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 // @Failure 404 {object} helpers.ErrorResponse
 // @Router /students/{id} [put]
 func (h *StudentHandler) Update(w http.ResponseWriter, r *http.Request) {
-	ctx := context.Background()
+	ctx := r.Context()
 	id, _ := strconv.Atoi(chi.URLParam(r, "id"))
-	_, claims, _ := jwtauth.FromContext(r.Context())
-	role := claims["role"].(string)
-	userID := int(claims["user_id"].(float64))
+	p := access.FromContext(r.Context())
 
 	var s models.Student
 	if err := json.NewDecoder(r.Body).Decode(&s); err != nil {
@@ -103,18 +182,16 @@ func (h *StudentHandler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if role == "school" {
-		schoolRepo := repository.NewSchoolRepository(h.svc.SchoolRepoDB())
-		school, err := schoolRepo.GetByUserID(ctx, userID)
-		if err != nil {
-			helpers.Error(w, http.StatusForbidden, "school not found")
+	ok, err := h.svc.Update(ctx, id, &s, p.SchoolScope())
+	if err != nil {
+		if errors.Is(err, services.ErrClassNotInSchool) || errors.Is(err, services.ErrClassArchived) {
+			helpers.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.Is(err, repository.ErrNoCurrentYear) {
+			helpers.Error(w, http.StatusConflict, err.Error())
 			return
 		}
-		s.SchoolID = school.ID
-	}
-
-	ok, err := h.svc.Update(ctx, id, &s, role)
-	if err != nil {
 		helpers.Error(w, http.StatusInternalServerError, "failed to update student")
 		return
 	}
@@ -128,21 +205,16 @@ func (h *StudentHandler) Update(w http.ResponseWriter, r *http.Request) {
 
 // GetStats godoc
 // @Summary Получить статистику по ученикам
-// @Description Только для ROO (по полу, школам и т.д.)
+// @Description Только для ROO (по полу, школам и т.д.). Устарело: подробный состав учеников — /stats/students
 // @Tags Students
+// @Deprecated
 // @Produce json
 // @Security BearerAuth
 // @Success 200 {object} map[string]int
 // @Failure 403 {object} helpers.ErrorResponse
 // @Router /students/stats [get]
 func (h *StudentHandler) GetStats(w http.ResponseWriter, r *http.Request) {
-	_, claims, _ := jwtauth.FromContext(r.Context())
-	role := claims["role"].(string)
-	if role != "roo" {
-		helpers.Error(w, http.StatusForbidden, "access denied")
-		return
-	}
-	stats, err := h.svc.GetStats(context.Background())
+	stats, err := h.svc.GetStats(r.Context())
 	if err != nil {
 		helpers.Error(w, http.StatusInternalServerError, "failed to get stats")
 		return
@@ -151,35 +223,56 @@ func (h *StudentHandler) GetStats(w http.ResponseWriter, r *http.Request) {
 }
 
 // ExportCSV godoc
-// @Summary Экспорт учеников в CSV (только ROO)
+// @Summary Экспорт учеников в CSV
+// @Description Вместе с родителями и законными представителями; фильтры — как у списка /students. Школа выгружает своих учеников, РОО и инспектор — всех.
+// @Description Выгрузка фиксируется в журнале доступа к персональным данным
 // @Tags Students
 // @Produce text/csv
+// @Param full_name query string false "ФИО"
+// @Param gender query string false "Пол (male/female)"
+// @Param class_id query int false "ID класса"
+// @Param academic_year query int false "ID учебного года (по умолчанию текущий)"
 // @Security BearerAuth
 // @Success 200 {string} string "csv file"
+// @Failure 400 {object} helpers.ErrorResponse
+// @Failure 403 {object} helpers.ErrorResponse
 // @Router /students/export [get]
 func (h *StudentHandler) ExportCSV(w http.ResponseWriter, r *http.Request) {
-	_, claims, _ := jwtauth.FromContext(r.Context())
-	role := claims["role"].(string)
-	if role != "roo" {
-		helpers.Error(w, http.StatusForbidden, "access denied")
+	ctx := r.Context()
+	p := access.FromContext(r.Context())
+
+	f, ok := studentFilter(w, r)
+	if !ok {
 		return
 	}
-
-	ctx := context.Background()
-	list, err := h.svc.GetAll(ctx, nil, repository.StudentFilter{})
+	list, err := h.svc.GetAll(ctx, p.SchoolScope(), f)
 	if err != nil {
 		helpers.Error(w, http.StatusInternalServerError, "failed to export")
 		return
 	}
+	guardians, err := h.guardians.GetByStudents(ctx, studentIDs(list))
+	if err != nil {
+		helpers.Error(w, http.StatusInternalServerError, "failed to export")
+		return
+	}
+	filter := studentAccessFilter{SchoolID: p.SchoolScope(), StudentFilter: f}
+	if err := h.pd.Log(ctx, services.PDActionExport, studentIDs(list), filter); err != nil {
+		helpers.Error(w, http.StatusInternalServerError, "failed to log access")
+		return
+	}
 
 	w.Header().Set("Content-Type", "text/csv")
 	w.Header().Set("Content-Disposition", "attachment; filename=students.csv")
 
-	fmt.Fprintln(w, "ID,Full Name,Gender,Class ID,School ID,Created At")
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{"ID", "Full Name", "Gender", "Class ID", "School ID", "Created At", "Guardians"})
 	for _, s := range list {
-		fmt.Fprintf(w, "%d,%s,%s,%d,%d,%s\n",
-			s.ID, s.FullName, deref(s.Gender), s.ClassID, s.SchoolID, s.CreatedAt.Format(time.RFC3339))
+		_ = cw.Write([]string{
+			strconv.Itoa(s.ID), s.FullName, deref(s.Gender), strconv.Itoa(s.ClassID), strconv.Itoa(s.SchoolID),
+			s.CreatedAt.Format(time.RFC3339), guardiansCell(guardians[s.ID]),
+		})
 	}
+	cw.Flush()
 }
 
 func deref(p *string) string {
@@ -191,31 +284,43 @@ func deref(p *string) string {
 
 // GetAll godoc
 // @Summary Получить список учеников
+// @Description Выборка (фильтр и ID учеников) фиксируется в журнале доступа к персональным данным
 // @Tags Students
 // @Produce json
 // @Param full_name query string false "ФИО"
 // @Param gender query string false "Пол (male/female)"
 // @Param class_id query int false "ID класса"
+// @Param academic_year query int false "ID учебного года (по умолчанию текущий); для архивного года — класс и школа того года"
 // @Security BearerAuth
 // @Success 200 {array} models.Student
+// @Failure 400 {object} helpers.ErrorResponse
 // @Failure 500 {object} helpers.ErrorResponse
 // @Router /students [get]
 func (h *StudentHandler) GetAll(w http.ResponseWriter, r *http.Request) {
-	ctx := context.Background()
-	_, claims, _ := jwtauth.FromContext(r.Context())
-	role := claims["role"].(string)
-	userID := int(claims["user_id"].(float64))
-
-	var schoolID *int
-	if role == "school" {
-		schoolRepo := repository.NewSchoolRepository(h.svc.SchoolRepoDB())
-		school, err := schoolRepo.GetByUserID(ctx, userID)
-		if err != nil {
-			helpers.Error(w, http.StatusForbidden, "school not found")
-			return
-		}
-		schoolID = &school.ID
+	ctx := r.Context()
+	p := access.FromContext(r.Context())
+
+	f, ok := studentFilter(w, r)
+	if !ok {
+		return
+	}
+	list, err := h.svc.GetAll(ctx, p.SchoolScope(), f)
+	if err != nil {
+		helpers.Error(w, http.StatusInternalServerError, "failed to get students")
+		return
 	}
+	filter := studentAccessFilter{SchoolID: p.SchoolScope(), StudentFilter: f}
+	if err := h.pd.Log(ctx, services.PDActionList, studentIDs(list), filter); err != nil {
+		helpers.Error(w, http.StatusInternalServerError, "failed to log access")
+		return
+	}
+	helpers.JSON(w, http.StatusOK, list)
+}
+
+// studentFilter разбирает фильтры списка учеников; учитель ограничен своим классом.
+// При ошибке пишет ответ и возвращает ok=false.
+func studentFilter(w http.ResponseWriter, r *http.Request) (repository.StudentFilter, bool) {
+	p := access.FromContext(r.Context())
 
 	f := repository.StudentFilter{
 		FullName: r.URL.Query().Get("full_name"),
@@ -225,13 +330,17 @@ func (h *StudentHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 		id, _ := strconv.Atoi(v)
 		f.ClassID = &id
 	}
-
-	list, err := h.svc.GetAll(ctx, schoolID, f)
+	yearID, err := queryInt(r.URL.Query(), "academic_year")
 	if err != nil {
-		helpers.Error(w, http.StatusInternalServerError, "failed to get students")
-		return
+		helpers.Error(w, http.StatusBadRequest, "invalid academic_year")
+		return f, false
 	}
-	helpers.JSON(w, http.StatusOK, list)
+	f.AcademicYearID = yearID
+	// учитель видит только свой класс
+	if classID := p.ClassScope(); classID != nil {
+		f.ClassID = classID
+	}
+	return f, true
 }
 
 // Create godoc
@@ -247,12 +356,10 @@ func (h *StudentHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 // @Failure 500 {object} helpers.ErrorResponse
 // @Router /students [post]
 func (h *StudentHandler) Create(w http.ResponseWriter, r *http.Request) {
-	ctx := context.Background()
-	_, claims, _ := jwtauth.FromContext(r.Context())
-	role := claims["role"].(string)
-	userID := int(claims["user_id"].(float64))
+	ctx := r.Context()
+	p := access.FromContext(r.Context())
 
-	if role != "school" {
+	if p.SchoolID == nil {
 		helpers.Error(w, http.StatusForbidden, "only schools can add students")
 		return
 	}
@@ -268,15 +375,17 @@ func (h *StudentHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	schoolRepo := repository.NewSchoolRepository(h.svc.SchoolRepoDB())
-	school, err := schoolRepo.GetByUserID(ctx, userID)
-	if err != nil {
-		helpers.Error(w, http.StatusForbidden, "school not found")
-		return
-	}
-	s.SchoolID = school.ID
+	s.SchoolID = *p.SchoolID
 
 	if err := h.svc.Create(ctx, &s); err != nil {
+		if errors.Is(err, services.ErrClassNotInSchool) || errors.Is(err, services.ErrClassArchived) {
+			helpers.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.Is(err, repository.ErrNoCurrentYear) {
+			helpers.Error(w, http.StatusConflict, err.Error())
+			return
+		}
 		helpers.Error(w, http.StatusInternalServerError, "failed to create student")
 		return
 	}
@@ -285,31 +394,27 @@ func (h *StudentHandler) Create(w http.ResponseWriter, r *http.Request) {
 
 // Delete godoc
 // @Summary Удалить ученика
+// @Description Переносит ученика в корзину (/trash)
 // @Tags Students
 // @Param id path int true "ID ученика"
 // @Security BearerAuth
 // @Success 200 {object} map[string]string
+// @Failure 404 {object} helpers.ErrorResponse
 // @Failure 500 {object} helpers.ErrorResponse
 // @Router /students/{id} [delete]
 func (h *StudentHandler) Delete(w http.ResponseWriter, r *http.Request) {
-	ctx := context.Background()
-	_, claims, _ := jwtauth.FromContext(r.Context())
-	userID := int(claims["user_id"].(float64))
+	ctx := r.Context()
+	p := access.FromContext(r.Context())
 
 	id, _ := strconv.Atoi(chi.URLParam(r, "id"))
-	schoolRepo := repository.NewSchoolRepository(h.svc.SchoolRepoDB())
-	school, err := schoolRepo.GetByUserID(ctx, userID)
+
+	ok, err := h.svc.Delete(ctx, id, p.SchoolScope())
 	if err != nil {
-		helpers.Error(w, http.StatusForbidden, "school not found")
+		helpers.Error(w, http.StatusInternalServerError, "failed to delete student")
 		return
 	}
-
-	// определяем class_id для корректного обновления счётчиков
-	var classID int
-	h.svc.ClassRepoDB().QueryRow(ctx, `SELECT class_id FROM students WHERE id=$1`, id).Scan(&classID)
-
-	if err := h.svc.Delete(ctx, id, school.ID, classID); err != nil {
-		helpers.Error(w, http.StatusInternalServerError, "failed to delete student")
+	if !ok {
+		helpers.Error(w, http.StatusNotFound, "student not found or not yours")
 		return
 	}
 	helpers.JSON(w, http.StatusOK, map[string]string{"status": "deleted"})
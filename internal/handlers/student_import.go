@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"eduBase/internal/access"
+	"eduBase/internal/helpers"
+	"eduBase/internal/repository"
+	"eduBase/internal/services"
+	"eduBase/internal/utils"
+)
+
+// maxImportSize — наибольший размер загружаемого файла
+const maxImportSize = 5 << 20
+
+// readImportFile читает xlsx (или csv — по расширению имени файла) из поля file формы multipart/form-data.
+// При ошибке пишет ответ и возвращает ok=false.
+func readImportFile(w http.ResponseWriter, r *http.Request) ([][]string, bool) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportSize)
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		helpers.Error(w, http.StatusBadRequest, "file required (multipart field \"file\", up to 5 MB)")
+		return nil, false
+	}
+	defer file.Close()
+
+	var rows [][]string
+	if strings.EqualFold(filepath.Ext(header.Filename), ".csv") {
+		rows, err = utils.ReadCSV(file)
+	} else {
+		rows, err = utils.ReadXLSX(file)
+	}
+	if err != nil {
+		helpers.Error(w, http.StatusBadRequest, "invalid xlsx or csv file")
+		return nil, false
+	}
+	return rows, true
+}
+
+// writeXLSX отдаёт книгу xlsx как вложение
+func writeXLSX(w http.ResponseWriter, filename string, sheets ...utils.Sheet) {
+	w.Header().Set("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+	w.Header().Set("Content-Disposition", "attachment; filename="+filename)
+	_ = utils.WriteXLSX(w, sheets...)
+}
+
+// ImportTemplate godoc
+// @Summary Шаблон загрузки учеников (xlsx)
+// @Description Лист «Ученики» — заголовок для заполнения, лист «Пример» — пример строки; обязательные колонки отмечены «*». Дата рождения — ДД.ММ.ГГГГ или дата Excel, пол — М или Ж.
+// @Description Лист «Классы» — классы школы в текущем учебном году, названия которых нужно указывать в колонке «Класс».
+// @Tags Students
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Security BearerAuth
+// @Success 200 {file} file "xlsx"
+// @Failure 403 {object} helpers.ErrorResponse
+// @Failure 500 {object} helpers.ErrorResponse
+// @Router /students/import/template [get]
+func (h *StudentHandler) ImportTemplate(w http.ResponseWriter, r *http.Request) {
+	p := access.FromContext(r.Context())
+	if p.SchoolID == nil {
+		helpers.Error(w, http.StatusForbidden, "only schools can import students")
+		return
+	}
+
+	classes, err := repository.NewClassRepository(h.svc.ClassRepoDB()).GetBySchool(r.Context(), *p.SchoolID, nil)
+	if err != nil {
+		helpers.Error(w, http.StatusInternalServerError, "failed to get classes")
+		return
+	}
+	classRows := make([][]any, 0, len(classes))
+	for _, c := range classes {
+		classRows = append(classRows, []any{c.Name, c.Grade})
+	}
+
+	writeXLSX(w, "students_template.xlsx",
+		utils.Sheet{Name: "Ученики", Header: services.StudentImportColumns},
+		utils.Sheet{Name: "Пример", Header: services.StudentImportColumns, Rows: [][]any{services.StudentImportExample}},
+		utils.Sheet{Name: "Классы", Header: []string{"Класс", "Параллель"}, Rows: classRows},
+	)
+}
+
+// Import godoc
+// @Summary Загрузка учеников из xlsx
+// @Description Файл по шаблону /students/import/template, читается первый лист. Классы сопоставляются по названию среди классов школы текущего учебного года.
+// @Description Проверяются все строки: ФИО и класс обязательны, дата рождения, пол, дубли в файле и среди обучающихся школы.
+// @Description При любой ошибке не загружается ничего (422 с ошибками по строкам); dry_run=true только проверяет файл. Только для школы.
+// @Tags Students
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "Файл xlsx (или csv в UTF-8)"
+// @Param dry_run query bool false "Только проверить, не загружать"
+// @Security BearerAuth
+// @Success 200 {object} models.ImportReport "dry_run: файл без ошибок"
+// @Success 201 {object} models.ImportReport "ученики загружены"
+// @Failure 400 {object} helpers.ErrorResponse
+// @Failure 403 {object} helpers.ErrorResponse
+// @Failure 422 {object} models.ImportReport
+// @Failure 500 {object} helpers.ErrorResponse
+// @Router /students/import [post]
+func (h *StudentHandler) Import(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	p := access.FromContext(r.Context())
+
+	if p.SchoolID == nil {
+		helpers.Error(w, http.StatusForbidden, "only schools can import students")
+		return
+	}
+	dryRun := r.URL.Query().Get("dry_run") == "true"
+	rows, ok := readImportFile(w, r)
+	if !ok {
+		return
+	}
+
+	report, err := h.svc.Import(ctx, *p.SchoolID, rows, dryRun)
+	if err != nil {
+		if errors.Is(err, services.ErrImportEmpty) || errors.Is(err, services.ErrImportTooLarge) {
+			helpers.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		helpers.Error(w, http.StatusInternalServerError, "failed to import students")
+		return
+	}
+
+	switch {
+	case len(report.Errors) > 0:
+		helpers.JSON(w, http.StatusUnprocessableEntity, report)
+	case dryRun:
+		helpers.JSON(w, http.StatusOK, report)
+	default:
+		helpers.JSON(w, http.StatusCreated, report)
+	}
+}
@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"eduBase/internal/access"
+	"eduBase/internal/helpers"
+	"eduBase/internal/middleware"
+	"eduBase/internal/models"
+	"eduBase/internal/repository"
+	"eduBase/internal/services"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// SubjectHandler — справочник предметов
+type SubjectHandler struct {
+	svc *services.SubjectService
+}
+
+func NewSubjectHandler(svc *services.SubjectService) *SubjectHandler {
+	return &SubjectHandler{svc: svc}
+}
+
+func (h *SubjectHandler) Routes(r chi.Router) {
+	r.Get("/subjects", h.GetAll)
+	r.Route("/roo/subjects", func(r chi.Router) {
+		r.Use(middleware.RequirePermission(access.SubjectsManage))
+		r.Post("/", h.Create)
+		r.Put("/{id}", h.Update)
+		r.Delete("/{id}", h.Delete)
+	})
+}
+
+func writeSubjectError(w http.ResponseWriter, err error, fallback string) {
+	switch {
+	case errors.Is(err, services.ErrSubjectNameRequired):
+		helpers.Error(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, repository.ErrSubjectNotFound):
+		helpers.Error(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, repository.ErrSubjectExists), errors.Is(err, repository.ErrSubjectInUse):
+		helpers.Error(w, http.StatusConflict, err.Error())
+	default:
+		helpers.Error(w, http.StatusInternalServerError, fallback)
+	}
+}
+
+// GetAll godoc
+// @Summary      Справочник предметов
+// @Tags         Subjects
+// @Produce      json
+// @Success      200 {array} models.Subject
+// @Failure      500 {object} helpers.ErrorResponse
+// @Security     BearerAuth
+// @Router       /subjects [get]
+func (h *SubjectHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	list, err := h.svc.GetAll(r.Context())
+	if err != nil {
+		helpers.Error(w, http.StatusInternalServerError, "failed to get subjects")
+		return
+	}
+	helpers.JSON(w, http.StatusOK, list)
+}
+
+// Create godoc
+// @Summary      Добавить предмет
+// @Description  Только РОО
+// @Tags         Subjects
+// @Accept       json
+// @Produce      json
+// @Param        data body models.Subject true "Предмет"
+// @Success      201 {object} models.Subject
+// @Failure      400 {object} helpers.ErrorResponse
+// @Failure      409 {object} helpers.ErrorResponse
+// @Failure      500 {object} helpers.ErrorResponse
+// @Security     BearerAuth
+// @Router       /roo/subjects [post]
+func (h *SubjectHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var s models.Subject
+	if err := json.NewDecoder(r.Body).Decode(&s); err != nil {
+		helpers.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+	if err := h.svc.Create(r.Context(), &s); err != nil {
+		writeSubjectError(w, err, "failed to create subject")
+		return
+	}
+	helpers.JSON(w, http.StatusCreated, s)
+}
+
+// Update godoc
+// @Summary      Переименовать предмет
+// @Description  Только РОО
+// @Tags         Subjects
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "ID предмета"
+// @Param        data body models.Subject true "Предмет"
+// @Success      200 {object} models.Subject
+// @Failure      400 {object} helpers.ErrorResponse
+// @Failure      404 {object} helpers.ErrorResponse
+// @Failure      409 {object} helpers.ErrorResponse
+// @Failure      500 {object} helpers.ErrorResponse
+// @Security     BearerAuth
+// @Router       /roo/subjects/{id} [put]
+func (h *SubjectHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.Atoi(chi.URLParam(r, "id"))
+	var s models.Subject
+	if err := json.NewDecoder(r.Body).Decode(&s); err != nil {
+		helpers.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+	if err := h.svc.Update(r.Context(), id, &s); err != nil {
+		writeSubjectError(w, err, "failed to update subject")
+		return
+	}
+	helpers.JSON(w, http.StatusOK, s)
+}
+
+// Delete godoc
+// @Summary      Удалить предмет
+// @Description  Только РОО; предмет, который используется в назначениях, расписании или оценках, удалить нельзя
+// @Tags         Subjects
+// @Param        id path int true "ID предмета"
+// @Success      200 {object} map[string]string
+// @Failure      404 {object} helpers.ErrorResponse
+// @Failure      409 {object} helpers.ErrorResponse
+// @Failure      500 {object} helpers.ErrorResponse
+// @Security     BearerAuth
+// @Router       /roo/subjects/{id} [delete]
+func (h *SubjectHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.Atoi(chi.URLParam(r, "id"))
+	if err := h.svc.Delete(r.Context(), id); err != nil {
+		writeSubjectError(w, err, "failed to delete subject")
+		return
+	}
+	helpers.JSON(w, http.StatusOK, map[string]string{"status": "deleted"})
+}
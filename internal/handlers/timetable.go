@@ -0,0 +1,219 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"eduBase/internal/access"
+	"eduBase/internal/helpers"
+	"eduBase/internal/models"
+	"eduBase/internal/repository"
+	"eduBase/internal/services"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// lessonRequest — урок в расписании класса
+type lessonRequest struct {
+	Weekday   int     `json:"weekday" example:"1"` // 1 — понедельник … 6 — суббота
+	Lesson    int     `json:"lesson" example:"3"`  // номер урока, 1–12
+	SubjectID int     `json:"subject_id" example:"3"`
+	StaffID   *int    `json:"staff_id,omitempty"`
+	Room      *string `json:"room,omitempty" example:"204"`
+}
+
+func (l lessonRequest) entry() *models.TimetableEntry {
+	return &models.TimetableEntry{
+		Weekday: l.Weekday, Lesson: l.Lesson, SubjectID: l.SubjectID, StaffID: l.StaffID, Room: l.Room,
+	}
+}
+
+func writeTimetableError(w http.ResponseWriter, err error, fallback string) {
+	switch {
+	case errors.Is(err, services.ErrInvalidTimetableSlot),
+		errors.Is(err, services.ErrStaffNotInSchool),
+		errors.Is(err, services.ErrClassArchived),
+		errors.Is(err, repository.ErrSubjectNotFound):
+		helpers.Error(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, repository.ErrTimetableEntryNotFound):
+		helpers.Error(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, services.ErrTimetableConflict), errors.Is(err, repository.ErrTimetableSlotTaken),
+		errors.Is(err, repository.ErrNoCurrentYear):
+		helpers.Error(w, http.StatusConflict, err.Error())
+	default:
+		helpers.Error(w, http.StatusInternalServerError, fallback)
+	}
+}
+
+// GetTimetable godoc
+// @Summary Расписание класса
+// @Description Уроки класса на неделю по дням и номерам уроков. РОО — любой класс, школа — свой, учитель — только свой класс
+// @Tags Timetable
+// @Produce json
+// @Param id path int true "ID класса"
+// @Success 200 {array} models.TimetableEntry
+// @Failure 403 {object} helpers.ErrorResponse
+// @Failure 404 {object} helpers.ErrorResponse
+// @Failure 500 {object} helpers.ErrorResponse
+// @Security BearerAuth
+// @Router /classes/{id}/timetable [get]
+func (h *ClassHandler) GetTimetable(w http.ResponseWriter, r *http.Request) {
+	class, ok := h.ownClass(w, r, false)
+	if !ok {
+		return
+	}
+
+	list, err := h.timetable.GetByClass(r.Context(), class.ID)
+	if err != nil {
+		helpers.Error(w, http.StatusInternalServerError, "failed to get timetable")
+		return
+	}
+	helpers.JSON(w, http.StatusOK, list)
+}
+
+// CreateLesson godoc
+// @Summary Добавить урок в расписание класса
+// @Description Школа — только в свои классы текущего учебного года. Учитель и кабинет не могут быть заняты в это время другим уроком того же учебного года (409);
+// @Description класс — тоже, кроме подгрупп: уроков одного предмета у разных учителей.
+// @Tags Timetable
+// @Accept json
+// @Produce json
+// @Param id path int true "ID класса"
+// @Param data body lessonRequest true "Урок"
+// @Success 201 {object} models.TimetableEntry
+// @Failure 400 {object} helpers.ErrorResponse
+// @Failure 403 {object} helpers.ErrorResponse
+// @Failure 404 {object} helpers.ErrorResponse
+// @Failure 409 {object} helpers.ErrorResponse
+// @Failure 500 {object} helpers.ErrorResponse
+// @Security BearerAuth
+// @Router /classes/{id}/timetable [post]
+func (h *ClassHandler) CreateLesson(w http.ResponseWriter, r *http.Request) {
+	class, ok := h.ownClass(w, r, true)
+	if !ok {
+		return
+	}
+
+	var req lessonRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		helpers.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	e := req.entry()
+	if err := h.timetable.Create(r.Context(), class, e); err != nil {
+		writeTimetableError(w, err, "failed to create lesson")
+		return
+	}
+	helpers.JSON(w, http.StatusCreated, e)
+}
+
+// UpdateLesson godoc
+// @Summary Изменить урок в расписании класса
+// @Description Школа — только в своих классах; проверки занятости те же, что при добавлении
+// @Tags Timetable
+// @Accept json
+// @Produce json
+// @Param id path int true "ID класса"
+// @Param entryID path int true "ID урока"
+// @Param data body lessonRequest true "Урок"
+// @Success 200 {object} models.TimetableEntry
+// @Failure 400 {object} helpers.ErrorResponse
+// @Failure 403 {object} helpers.ErrorResponse
+// @Failure 404 {object} helpers.ErrorResponse
+// @Failure 409 {object} helpers.ErrorResponse
+// @Failure 500 {object} helpers.ErrorResponse
+// @Security BearerAuth
+// @Router /classes/{id}/timetable/{entryID} [put]
+func (h *ClassHandler) UpdateLesson(w http.ResponseWriter, r *http.Request) {
+	class, ok := h.ownClass(w, r, true)
+	if !ok {
+		return
+	}
+
+	entryID, _ := strconv.Atoi(chi.URLParam(r, "entryID"))
+	var req lessonRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		helpers.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	e, err := h.timetable.Update(r.Context(), class, entryID, req.entry())
+	if err != nil {
+		writeTimetableError(w, err, "failed to update lesson")
+		return
+	}
+	helpers.JSON(w, http.StatusOK, e)
+}
+
+// DeleteLesson godoc
+// @Summary Убрать урок из расписания класса
+// @Description Школа — только в своих классах текущего учебного года
+// @Tags Timetable
+// @Param id path int true "ID класса"
+// @Param entryID path int true "ID урока"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} helpers.ErrorResponse
+// @Failure 403 {object} helpers.ErrorResponse
+// @Failure 404 {object} helpers.ErrorResponse
+// @Failure 500 {object} helpers.ErrorResponse
+// @Security BearerAuth
+// @Router /classes/{id}/timetable/{entryID} [delete]
+func (h *ClassHandler) DeleteLesson(w http.ResponseWriter, r *http.Request) {
+	class, ok := h.ownClass(w, r, true)
+	if !ok {
+		return
+	}
+
+	entryID, _ := strconv.Atoi(chi.URLParam(r, "entryID"))
+	if err := h.timetable.Delete(r.Context(), class, entryID); err != nil {
+		writeTimetableError(w, err, "failed to delete lesson")
+		return
+	}
+	helpers.JSON(w, http.StatusOK, map[string]string{"status": "deleted"})
+}
+
+// GetTimetable godoc
+// @Summary Расписание учителя
+// @Description Уроки сотрудника во всех классах за учебный год. РОО — любого, школа — только своего
+// @Tags Timetable
+// @Produce json
+// @Param id path int true "ID сотрудника"
+// @Param academic_year query int false "ID учебного года (по умолчанию текущий)"
+// @Success 200 {array} models.TimetableEntry
+// @Failure 400 {object} helpers.ErrorResponse
+// @Failure 403 {object} helpers.ErrorResponse
+// @Failure 404 {object} helpers.ErrorResponse
+// @Failure 500 {object} helpers.ErrorResponse
+// @Security BearerAuth
+// @Router /staff/{id}/timetable [get]
+func (h *StaffHandler) GetTimetable(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	p := access.FromContext(r.Context())
+
+	id, _ := strconv.Atoi(chi.URLParam(r, "id"))
+	yearID, err := queryInt(r.URL.Query(), "academic_year")
+	if err != nil {
+		helpers.Error(w, http.StatusBadRequest, "invalid academic_year")
+		return
+	}
+
+	staff, err := h.svc.GetByID(ctx, id)
+	if err != nil {
+		helpers.Error(w, http.StatusNotFound, "staff not found")
+		return
+	}
+	if !p.CanAccessSchool(staff.SchoolID) {
+		helpers.Error(w, http.StatusForbidden, "access denied")
+		return
+	}
+
+	list, err := h.timetable.GetByStaff(ctx, staff.ID, yearID)
+	if err != nil {
+		helpers.Error(w, http.StatusInternalServerError, "failed to get timetable")
+		return
+	}
+	helpers.JSON(w, http.StatusOK, list)
+}
@@ -0,0 +1,305 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"eduBase/internal/access"
+	"eduBase/internal/helpers"
+	"eduBase/internal/middleware"
+	"eduBase/internal/models"
+	"eduBase/internal/repository"
+	"eduBase/internal/services"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// TransferHandler — переводы учеников между школами района
+type TransferHandler struct {
+	svc *services.TransferService
+	pd  *services.PDAccessService
+}
+
+func NewTransferHandler(svc *services.TransferService, pd *services.PDAccessService) *TransferHandler {
+	return &TransferHandler{svc: svc, pd: pd}
+}
+
+func (h *TransferHandler) Routes(r chi.Router) {
+	r.Route("/transfers", func(r chi.Router) {
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.RequirePermission(access.StudentsRead))
+			r.Get("/", h.GetAll)
+			r.Get("/{id}", h.GetByID)
+		})
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.RequirePermission(access.StudentsWrite))
+			r.Post("/", h.Create)
+			r.Post("/{id}/accept", h.Accept)
+			r.Post("/{id}/reject", h.Reject)
+			r.Post("/{id}/cancel", h.Cancel)
+		})
+	})
+}
+
+type createTransferRequest struct {
+	StudentID  int     `json:"student_id" example:"15"`
+	ToSchoolID int     `json:"to_school_id" example:"3"`
+	Reason     *string `json:"reason,omitempty" example:"смена места жительства"`
+	// только РОО: класс принимающей школы — перевод проводится сразу
+	ClassID *int `json:"class_id,omitempty"`
+}
+
+type acceptTransferRequest struct {
+	ClassID int `json:"class_id" example:"42"`
+}
+
+type closeTransferRequest struct {
+	Note *string `json:"note,omitempty"`
+}
+
+func transferStudentIDs(list []models.StudentTransfer) []int {
+	ids := make([]int, 0, len(list))
+	for _, t := range list {
+		ids = append(ids, t.StudentID)
+	}
+	return ids
+}
+
+// writeTransferError — общие ответы на ошибки операций с заявкой
+func writeTransferError(w http.ResponseWriter, err error, fallback string) {
+	switch {
+	case errors.Is(err, repository.ErrTransferNotFound), errors.Is(err, repository.ErrStudentNotFound):
+		helpers.Error(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, repository.ErrSchoolNotFound),
+		errors.Is(err, services.ErrTransferSameSchool),
+		errors.Is(err, services.ErrTransferStudent),
+		errors.Is(err, services.ErrClassNotInSchool),
+		errors.Is(err, services.ErrClassArchived):
+		helpers.Error(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, repository.ErrTransferPending),
+		errors.Is(err, services.ErrTransferNotPending),
+		errors.Is(err, services.ErrTransferOutdated),
+		errors.Is(err, repository.ErrNoCurrentYear):
+		helpers.Error(w, http.StatusConflict, err.Error())
+	default:
+		helpers.Error(w, http.StatusInternalServerError, fallback)
+	}
+}
+
+// GetAll godoc
+// @Summary      Заявки на перевод
+// @Description  Школа видит заявки, где она отправляет или принимает ученика; РОО — все. Учителям недоступно.
+// @Description  Выборка фиксируется в журнале доступа к персональным данным.
+// @Tags         Transfers
+// @Produce      json
+// @Param        direction query string false "incoming — входящие, outgoing — исходящие (для школы)"
+// @Param        status query string false "pending | accepted | rejected | cancelled"
+// @Param        student_id query int false "ID ученика"
+// @Success      200 {array} models.StudentTransfer
+// @Failure      400 {object} helpers.ErrorResponse
+// @Failure      403 {object} helpers.ErrorResponse
+// @Failure      500 {object} helpers.ErrorResponse
+// @Security     BearerAuth
+// @Router       /transfers [get]
+func (h *TransferHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	p := access.FromContext(r.Context())
+
+	// учитель видит только свой класс — заявки других классов ему не показываются
+	if p.IsTeacher() {
+		helpers.Error(w, http.StatusForbidden, "access denied")
+		return
+	}
+
+	q := r.URL.Query()
+	f := repository.TransferFilter{
+		SchoolID:  p.SchoolScope(),
+		Direction: q.Get("direction"),
+		Status:    q.Get("status"),
+	}
+	studentID, err := queryInt(q, "student_id")
+	if err != nil {
+		helpers.Error(w, http.StatusBadRequest, "invalid student_id")
+		return
+	}
+	f.StudentID = studentID
+
+	list, err := h.svc.GetAll(ctx, f)
+	if err != nil {
+		helpers.Error(w, http.StatusInternalServerError, "failed to get transfers")
+		return
+	}
+	if err := h.pd.Log(ctx, services.PDActionList, transferStudentIDs(list), f); err != nil {
+		helpers.Error(w, http.StatusInternalServerError, "failed to log access")
+		return
+	}
+	helpers.JSON(w, http.StatusOK, list)
+}
+
+// GetByID godoc
+// @Summary      Заявка на перевод
+// @Tags         Transfers
+// @Produce      json
+// @Param        id path int true "ID заявки"
+// @Success      200 {object} models.StudentTransfer
+// @Failure      403 {object} helpers.ErrorResponse
+// @Failure      404 {object} helpers.ErrorResponse
+// @Failure      500 {object} helpers.ErrorResponse
+// @Security     BearerAuth
+// @Router       /transfers/{id} [get]
+func (h *TransferHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	p := access.FromContext(r.Context())
+
+	if p.IsTeacher() {
+		helpers.Error(w, http.StatusForbidden, "access denied")
+		return
+	}
+
+	id, _ := strconv.Atoi(chi.URLParam(r, "id"))
+	t, err := h.svc.GetByID(ctx, id, p.SchoolScope())
+	if err != nil {
+		writeTransferError(w, err, "failed to get transfer")
+		return
+	}
+	if err := h.pd.Log(ctx, services.PDActionView, []int{t.StudentID}, nil); err != nil {
+		helpers.Error(w, http.StatusInternalServerError, "failed to log access")
+		return
+	}
+	helpers.JSON(w, http.StatusOK, t)
+}
+
+// Create godoc
+// @Summary      Начать перевод ученика в другую школу
+// @Description  Школа ученика создаёт заявку, принимающая школа подтверждает её через /transfers/{id}/accept.
+// @Description  РОО может создать заявку за любую школу, а с class_id — сразу провести перевод.
+// @Tags         Transfers
+// @Accept       json
+// @Produce      json
+// @Param        data body createTransferRequest true "Заявка"
+// @Success      201 {object} models.StudentTransfer
+// @Failure      400 {object} helpers.ErrorResponse
+// @Failure      403 {object} helpers.ErrorResponse
+// @Failure      404 {object} helpers.ErrorResponse
+// @Failure      409 {object} helpers.ErrorResponse "у ученика уже есть незавершённая заявка"
+// @Failure      500 {object} helpers.ErrorResponse
+// @Security     BearerAuth
+// @Router       /transfers [post]
+func (h *TransferHandler) Create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	p := access.FromContext(r.Context())
+
+	var req createTransferRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.StudentID == 0 || req.ToSchoolID == 0 {
+		helpers.Error(w, http.StatusBadRequest, "student_id and to_school_id required")
+		return
+	}
+	if req.ClassID != nil && !p.IsDistrict() {
+		helpers.Error(w, http.StatusForbidden, "only district can transfer without confirmation")
+		return
+	}
+
+	t := models.StudentTransfer{StudentID: req.StudentID, ToSchoolID: req.ToSchoolID, Reason: req.Reason}
+	if err := h.svc.Create(ctx, &t, p.SchoolScope(), req.ClassID); err != nil {
+		writeTransferError(w, err, "failed to create transfer")
+		return
+	}
+	helpers.JSON(w, http.StatusCreated, t)
+}
+
+// Accept godoc
+// @Summary      Принять ученика по заявке
+// @Description  Принимающая школа зачисляет ученика в свой класс текущего учебного года; РОО может провести любую заявку.
+// @Description  Выбытие и прибытие записываются в книгу движения обеих школ.
+// @Tags         Transfers
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "ID заявки"
+// @Param        data body acceptTransferRequest true "Класс"
+// @Success      200 {object} models.StudentTransfer
+// @Failure      400 {object} helpers.ErrorResponse
+// @Failure      404 {object} helpers.ErrorResponse
+// @Failure      409 {object} helpers.ErrorResponse "заявка закрыта или ученик уже выбыл"
+// @Failure      500 {object} helpers.ErrorResponse
+// @Security     BearerAuth
+// @Router       /transfers/{id}/accept [post]
+func (h *TransferHandler) Accept(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	p := access.FromContext(r.Context())
+
+	id, _ := strconv.Atoi(chi.URLParam(r, "id"))
+	var req acceptTransferRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.ClassID == 0 {
+		helpers.Error(w, http.StatusBadRequest, services.ErrTransferClassNeeded.Error())
+		return
+	}
+
+	t, err := h.svc.Accept(ctx, id, req.ClassID, p.SchoolScope())
+	if err != nil {
+		writeTransferError(w, err, "failed to accept transfer")
+		return
+	}
+	helpers.JSON(w, http.StatusOK, t)
+}
+
+// Reject godoc
+// @Summary      Отказать в переводе
+// @Description  Принимающая школа или РОО
+// @Tags         Transfers
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "ID заявки"
+// @Param        data body closeTransferRequest false "Причина отказа"
+// @Success      200 {object} models.StudentTransfer
+// @Failure      404 {object} helpers.ErrorResponse
+// @Failure      409 {object} helpers.ErrorResponse
+// @Failure      500 {object} helpers.ErrorResponse
+// @Security     BearerAuth
+// @Router       /transfers/{id}/reject [post]
+func (h *TransferHandler) Reject(w http.ResponseWriter, r *http.Request) {
+	h.close(w, r, h.svc.Reject)
+}
+
+// Cancel godoc
+// @Summary      Отозвать заявку на перевод
+// @Description  Отправляющая школа или РОО
+// @Tags         Transfers
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "ID заявки"
+// @Param        data body closeTransferRequest false "Комментарий"
+// @Success      200 {object} models.StudentTransfer
+// @Failure      404 {object} helpers.ErrorResponse
+// @Failure      409 {object} helpers.ErrorResponse
+// @Failure      500 {object} helpers.ErrorResponse
+// @Security     BearerAuth
+// @Router       /transfers/{id}/cancel [post]
+func (h *TransferHandler) Cancel(w http.ResponseWriter, r *http.Request) {
+	h.close(w, r, h.svc.Cancel)
+}
+
+type closeTransferFunc func(ctx context.Context, id int, note *string, schoolID *int) (*models.StudentTransfer, error)
+
+func (h *TransferHandler) close(w http.ResponseWriter, r *http.Request, fn closeTransferFunc) {
+	ctx := r.Context()
+	p := access.FromContext(r.Context())
+
+	id, _ := strconv.Atoi(chi.URLParam(r, "id"))
+	var req closeTransferRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			helpers.Error(w, http.StatusBadRequest, "invalid request")
+			return
+		}
+	}
+
+	t, err := fn(ctx, id, req.Note, p.SchoolScope())
+	if err != nil {
+		writeTransferError(w, err, "failed to update transfer")
+		return
+	}
+	helpers.JSON(w, http.StatusOK, t)
+}
@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"eduBase/internal/access"
+	"eduBase/internal/helpers"
+	"eduBase/internal/repository"
+	"eduBase/internal/services"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// TrashHandler — корзина удалённых записей
+type TrashHandler struct {
+	svc *services.TrashService
+	pd  *services.PDAccessService
+}
+
+func NewTrashHandler(svc *services.TrashService, pd *services.PDAccessService) *TrashHandler {
+	return &TrashHandler{svc: svc, pd: pd}
+}
+
+// trashKinds — раздел корзины в URL, тип записи и право, нужное для её удаления и восстановления
+var trashKinds = []struct {
+	path   string
+	entity string
+	perm   access.Permission
+}{
+	{"schools", repository.TrashSchool, access.SchoolsWrite},
+	{"classes", repository.TrashClass, access.ClassesWrite},
+	{"staff", repository.TrashStaff, access.StaffWrite},
+	{"students", repository.TrashStudent, access.StudentsWrite},
+}
+
+func (h *TrashHandler) Routes(r chi.Router) {
+	r.Route("/trash", func(r chi.Router) {
+		r.Get("/", h.List)
+		r.Post("/{kind}/{id}/restore", h.Restore)
+	})
+}
+
+// trashAccessFilter — параметры просмотра корзины для журнала доступа к персональным данным
+type trashAccessFilter struct {
+	Kind     string `json:"kind,omitempty"`
+	SchoolID *int   `json:"school_id,omitempty"`
+}
+
+// List godoc
+// @Summary      Корзина
+// @Description  Удалённые записи, которые пользователь может восстановить: РОО — всё, школа — классы, сотрудники и ученики своей школы (по правам роли). Записи хранятся в корзине ограниченное время (TRASH_RETENTION_DAYS), затем удаляются окончательно.
+// @Description  Просмотр удалённых учеников фиксируется в журнале доступа к персональным данным
+// @Tags         Trash
+// @Produce      json
+// @Param        kind query string false "Раздел" Enums(schools, classes, staff, students)
+// @Success      200 {array} models.TrashItem
+// @Failure      400 {object} helpers.ErrorResponse
+// @Failure      403 {object} helpers.ErrorResponse
+// @Failure      500 {object} helpers.ErrorResponse
+// @Security     BearerAuth
+// @Router       /trash [get]
+func (h *TrashHandler) List(w http.ResponseWriter, r *http.Request) {
+	p := access.FromContext(r.Context())
+	kind := r.URL.Query().Get("kind")
+
+	var types []string
+	known := kind == ""
+	for _, k := range trashKinds {
+		if kind != "" && kind != k.path {
+			continue
+		}
+		known = true
+		if p.Can(k.perm) {
+			types = append(types, k.entity)
+		}
+	}
+	if !known {
+		helpers.Error(w, http.StatusBadRequest, "invalid kind")
+		return
+	}
+	if len(types) == 0 {
+		helpers.Error(w, http.StatusForbidden, "access denied")
+		return
+	}
+
+	list, err := h.svc.List(r.Context(), types, p.SchoolScope())
+	if err != nil {
+		helpers.Error(w, http.StatusInternalServerError, "failed to load trash")
+		return
+	}
+	var students []int
+	for _, it := range list {
+		if it.EntityType == repository.TrashStudent {
+			students = append(students, it.ID)
+		}
+	}
+	if len(students) > 0 {
+		filter := trashAccessFilter{Kind: kind, SchoolID: p.SchoolScope()}
+		if err := h.pd.Log(r.Context(), services.PDActionList, students, filter); err != nil {
+			helpers.Error(w, http.StatusInternalServerError, "failed to log access")
+			return
+		}
+	}
+	helpers.JSON(w, http.StatusOK, list)
+}
+
+// Restore godoc
+// @Summary      Восстановить из корзины
+// @Description  Школа восстанавливается вместе с классами, сотрудниками и учениками, удалёнными вместе с ней; класс — вместе со своими учениками. Нельзя восстановить запись, пока в корзине её школа или класс.
+// @Tags         Trash
+// @Produce      json
+// @Param        kind path string true "Раздел" Enums(schools, classes, staff, students)
+// @Param        id   path int    true "ID записи"
+// @Success      200 {object} map[string]string
+// @Failure      403 {object} helpers.ErrorResponse
+// @Failure      404 {object} helpers.ErrorResponse
+// @Failure      409 {object} helpers.ErrorResponse
+// @Failure      500 {object} helpers.ErrorResponse
+// @Security     BearerAuth
+// @Router       /trash/{kind}/{id}/restore [post]
+func (h *TrashHandler) Restore(w http.ResponseWriter, r *http.Request) {
+	p := access.FromContext(r.Context())
+	kind := chi.URLParam(r, "kind")
+	id, _ := strconv.Atoi(chi.URLParam(r, "id"))
+
+	for _, k := range trashKinds {
+		if k.path != kind {
+			continue
+		}
+		if !p.Can(k.perm) {
+			helpers.Error(w, http.StatusForbidden, "access denied")
+			return
+		}
+
+		ok, err := h.svc.Restore(r.Context(), k.entity, id, p.SchoolScope())
+		if err != nil {
+			if errors.Is(err, services.ErrParentDeleted) {
+				helpers.Error(w, http.StatusConflict, err.Error())
+				return
+			}
+			helpers.Error(w, http.StatusInternalServerError, "failed to restore")
+			return
+		}
+		if !ok {
+			helpers.Error(w, http.StatusNotFound, "not found in trash")
+			return
+		}
+		helpers.JSON(w, http.StatusOK, map[string]string{"status": "restored"})
+		return
+	}
+	helpers.Error(w, http.StatusNotFound, "unknown trash section")
+}
@@ -0,0 +1,252 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"eduBase/internal/access"
+	"eduBase/internal/helpers"
+	"eduBase/internal/middleware"
+	"eduBase/internal/models"
+	"eduBase/internal/repository"
+	"eduBase/internal/services"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// UserHandler — учётные записи пользователей.
+// /roo/users — районные (roo, inspector), /school/users — сотрудники своей школы.
+// Зона определяется текущим пользователем: у РОО школы нет, у директора — своя.
+type UserHandler struct {
+	svc *services.UserService
+}
+
+func NewUserHandler(svc *services.UserService) *UserHandler {
+	return &UserHandler{svc: svc}
+}
+
+func (h *UserHandler) Routes(r chi.Router) {
+	r.With(middleware.RequirePermission(access.UsersManage)).Route("/roo/users", h.routes)
+	r.With(middleware.RequirePermission(access.SchoolUsersManage)).Route("/school/users", h.routes)
+}
+
+func (h *UserHandler) routes(r chi.Router) {
+	r.Get("/", h.GetAll)
+	r.Get("/{id}", h.GetByID)
+	r.Post("/", h.Create)
+	r.Put("/{id}", h.Update)
+	r.Delete("/{id}", h.Delete)
+	r.Post("/{id}/reset-password", h.ResetPassword)
+}
+
+type userRequest struct {
+	Email    string  `json:"email" example:"inspector@roo.ru"`
+	FullName *string `json:"full_name,omitempty" example:"Петрова Анна Сергеевна"`
+	Role     string  `json:"role" example:"inspector"`      // roo|inspector для РОО; deputy|secretary|teacher для школы
+	ClassID  *int    `json:"class_id,omitempty"`            // класс учителя
+	Password string  `json:"password,omitempty" example:""` // пусто — сгенерировать
+}
+
+// UserCreatedResponse — созданный пользователь и его временный пароль (показывается один раз)
+type UserCreatedResponse struct {
+	User     models.User `json:"user"`
+	Password string      `json:"password" example:"aB3dE5fG7hJ9"`
+}
+
+// GetAll godoc
+// @Summary      Список пользователей
+// @Tags         Users
+// @Produce      json
+// @Success      200 {array} models.User
+// @Failure      500 {object} helpers.ErrorResponse
+// @Security     BearerAuth
+// @Router       /roo/users [get]
+// @Router       /school/users [get]
+func (h *UserHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	list, err := h.svc.GetAll(r.Context(), access.FromContext(r.Context()).SchoolScope())
+	if err != nil {
+		helpers.Error(w, http.StatusInternalServerError, "failed to load users")
+		return
+	}
+	helpers.JSON(w, http.StatusOK, list)
+}
+
+// GetByID godoc
+// @Summary      Пользователь по ID
+// @Tags         Users
+// @Produce      json
+// @Param        id path int true "ID пользователя"
+// @Success      200 {object} models.User
+// @Failure      404 {object} helpers.ErrorResponse
+// @Security     BearerAuth
+// @Router       /roo/users/{id} [get]
+// @Router       /school/users/{id} [get]
+func (h *UserHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.Atoi(chi.URLParam(r, "id"))
+	u, err := h.svc.GetByID(r.Context(), access.FromContext(r.Context()).SchoolScope(), id)
+	if err != nil {
+		helpers.Error(w, http.StatusNotFound, "user not found")
+		return
+	}
+	helpers.JSON(w, http.StatusOK, u)
+}
+
+// Create godoc
+// @Summary      Создать пользователя
+// @Description  Если пароль не передан — генерируется. Пароль возвращается один раз и должен быть сменён при первом входе.
+// @Tags         Users
+// @Accept       json
+// @Produce      json
+// @Param        input body userRequest true "Данные пользователя"
+// @Success      201 {object} UserCreatedResponse
+// @Failure      400 {object} helpers.ErrorResponse
+// @Failure      409 {object} helpers.ErrorResponse
+// @Failure      500 {object} helpers.ErrorResponse
+// @Security     BearerAuth
+// @Router       /roo/users [post]
+// @Router       /school/users [post]
+func (h *UserHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req userRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		helpers.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	req.Email = strings.TrimSpace(req.Email)
+	if req.Email == "" {
+		helpers.Error(w, http.StatusBadRequest, "email required")
+		return
+	}
+
+	u := models.User{Email: req.Email, FullName: req.FullName, Role: req.Role, ClassID: req.ClassID}
+	password, err := h.svc.Create(r.Context(), access.FromContext(r.Context()).SchoolScope(), &u, req.Password)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrWeakPassword), errors.Is(err, services.ErrInvalidRole),
+			errors.Is(err, services.ErrClassNotInSchool):
+			helpers.Error(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, repository.ErrEmailTaken):
+			helpers.Error(w, http.StatusConflict, err.Error())
+		default:
+			helpers.Error(w, http.StatusInternalServerError, "failed to create user")
+		}
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	helpers.JSON(w, http.StatusCreated, UserCreatedResponse{User: u, Password: password})
+}
+
+// Update godoc
+// @Summary      Обновить пользователя
+// @Description  Меняет email, ФИО, роль и класс учителя. Пароль меняется через /auth/me/password или сброс.
+// @Tags         Users
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "ID пользователя"
+// @Param        input body userRequest true "Данные пользователя"
+// @Success      200 {object} map[string]string
+// @Failure      400 {object} helpers.ErrorResponse
+// @Failure      404 {object} helpers.ErrorResponse
+// @Failure      409 {object} helpers.ErrorResponse
+// @Failure      500 {object} helpers.ErrorResponse
+// @Security     BearerAuth
+// @Router       /roo/users/{id} [put]
+// @Router       /school/users/{id} [put]
+func (h *UserHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.Atoi(chi.URLParam(r, "id"))
+	var req userRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		helpers.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	req.Email = strings.TrimSpace(req.Email)
+	if req.Email == "" {
+		helpers.Error(w, http.StatusBadRequest, "email required")
+		return
+	}
+
+	u := models.User{Email: req.Email, FullName: req.FullName, Role: req.Role, ClassID: req.ClassID}
+	err := h.svc.Update(r.Context(), access.FromContext(r.Context()).SchoolScope(), id, &u)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrUserNotFound):
+			helpers.Error(w, http.StatusNotFound, "user not found")
+		case errors.Is(err, services.ErrInvalidRole), errors.Is(err, services.ErrClassNotInSchool),
+			errors.Is(err, services.ErrProtectedAccount), errors.Is(err, services.ErrLastRooUser):
+			helpers.Error(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, repository.ErrEmailTaken):
+			helpers.Error(w, http.StatusConflict, err.Error())
+		default:
+			helpers.Error(w, http.StatusInternalServerError, "failed to update user")
+		}
+		return
+	}
+	helpers.JSON(w, http.StatusOK, map[string]string{"status": "updated"})
+}
+
+// Delete godoc
+// @Summary      Удалить пользователя
+// @Description  Нельзя удалить себя, последнего администратора РОО и директора школы
+// @Tags         Users
+// @Produce      json
+// @Param        id path int true "ID пользователя"
+// @Success      200 {object} map[string]string
+// @Failure      400 {object} helpers.ErrorResponse
+// @Failure      404 {object} helpers.ErrorResponse
+// @Failure      500 {object} helpers.ErrorResponse
+// @Security     BearerAuth
+// @Router       /roo/users/{id} [delete]
+// @Router       /school/users/{id} [delete]
+func (h *UserHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	p := access.FromContext(r.Context())
+	id, _ := strconv.Atoi(chi.URLParam(r, "id"))
+
+	if err := h.svc.Delete(r.Context(), p.SchoolScope(), id, p.UserID); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrUserNotFound):
+			helpers.Error(w, http.StatusNotFound, "user not found")
+		case errors.Is(err, services.ErrCannotDeleteSelf), errors.Is(err, services.ErrLastRooUser),
+			errors.Is(err, services.ErrProtectedAccount):
+			helpers.Error(w, http.StatusBadRequest, err.Error())
+		default:
+			helpers.Error(w, http.StatusInternalServerError, "failed to delete user")
+		}
+		return
+	}
+	helpers.JSON(w, http.StatusOK, map[string]string{"status": "deleted"})
+}
+
+// ResetPassword godoc
+// @Summary      Сбросить пароль пользователя
+// @Description  Генерирует временный пароль (показывается один раз), завершает сессии и требует смены пароля при входе
+// @Tags         Users
+// @Produce      json
+// @Param        id path int true "ID пользователя"
+// @Success      200 {object} map[string]string
+// @Failure      404 {object} helpers.ErrorResponse
+// @Failure      500 {object} helpers.ErrorResponse
+// @Security     BearerAuth
+// @Router       /roo/users/{id}/reset-password [post]
+// @Router       /school/users/{id}/reset-password [post]
+func (h *UserHandler) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.Atoi(chi.URLParam(r, "id"))
+	password, err := h.svc.ResetPassword(r.Context(), access.FromContext(r.Context()).SchoolScope(), id)
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			helpers.Error(w, http.StatusNotFound, "user not found")
+			return
+		}
+		if errors.Is(err, services.ErrProtectedAccount) {
+			helpers.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		helpers.Error(w, http.StatusInternalServerError, "failed to reset password")
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	helpers.JSON(w, http.StatusOK, map[string]string{"password": password})
+}
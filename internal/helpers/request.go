@@ -0,0 +1,54 @@
+package helpers
+
+import (
+	"net"
+	"net/http"
+	"strings"
+)
+
+// trustedProxies — адреса обратных прокси, которым доверяем заголовки X-Forwarded-For и X-Real-IP
+var trustedProxies []*net.IPNet
+
+// SetTrustedProxies задаёт доверенные прокси; вызывается один раз при старте, до приёма запросов
+func SetTrustedProxies(nets []*net.IPNet) {
+	trustedProxies = nets
+}
+
+func trusted(ip net.IP) bool {
+	for _, n := range trustedProxies {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// ClientIP возвращает IP клиента. Заголовки прокси (X-Forwarded-For, X-Real-IP) учитываются,
+// только если запрос пришёл от доверенного прокси — иначе их может подделать сам клиент.
+// В X-Forwarded-For берётся самый правый адрес, не принадлежащий доверенным прокси.
+func ClientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	if ip := net.ParseIP(host); ip == nil || !trusted(ip) {
+		return host
+	}
+	if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
+		hops := strings.Split(xff, ",")
+		for i := len(hops) - 1; i >= 0; i-- {
+			hop := strings.TrimSpace(hops[i])
+			ip := net.ParseIP(hop)
+			if ip == nil {
+				break
+			}
+			if !trusted(ip) || i == 0 {
+				return hop
+			}
+		}
+	}
+	if ip := strings.TrimSpace(r.Header.Get("X-Real-IP")); net.ParseIP(ip) != nil {
+		return ip
+	}
+	return host
+}
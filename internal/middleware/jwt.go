@@ -1,8 +1,13 @@
 package middleware
 
 import (
+	"context"
 	"net/http"
 
+	"eduBase/internal/access"
+	"eduBase/internal/audit"
+	"eduBase/internal/helpers"
+
 	"github.com/go-chi/jwtauth/v5"
 )
 
@@ -11,44 +16,88 @@ func JWTVerifier(tokenAuth *jwtauth.JWTAuth) func(http.Handler) http.Handler {
 	return jwtauth.Verifier(tokenAuth)
 }
 
-// Authenticator — требует наличие валидного токена
-func Authenticator(tokenAuth *jwtauth.JWTAuth) func(http.Handler) http.Handler {
-	return jwtauth.Authenticator(tokenAuth)
+// SessionChecker — проверка, что сессия токена не отозвана
+type SessionChecker interface {
+	IsActive(ctx context.Context, sessionID int64) (bool, error)
 }
 
-// Проверка конкретной роли
-func RequireRole(role string) func(http.Handler) http.Handler {
+// Authenticator — требует наличие валидного токена и активной серверной сессии (claim sid)
+func Authenticator(tokenAuth *jwtauth.JWTAuth, sessions SessionChecker) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
-		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		return jwtauth.Authenticator(tokenAuth)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			_, claims, _ := jwtauth.FromContext(r.Context())
-			if claims["role"] != role {
-				w.WriteHeader(http.StatusForbidden)
-				_, _ = w.Write([]byte(`{"error":"access denied"}`))
+			sid, ok := claims["sid"].(float64)
+			if !ok {
+				http.Error(w, `{"error":"unauthorized"}`, http.StatusUnauthorized)
+				return
+			}
+
+			active, err := sessions.IsActive(r.Context(), int64(sid))
+			if err != nil {
+				http.Error(w, `{"error":"internal error"}`, http.StatusInternalServerError)
+				return
+			}
+			if !active {
+				http.Error(w, `{"error":"session revoked"}`, http.StatusUnauthorized)
 				return
 			}
 			next.ServeHTTP(w, r)
-		})
+		}))
 	}
 }
 
-func RequireAnyRole(roles ...string) func(http.Handler) http.Handler {
+// RequirePasswordChanged — не пускает с токеном, выданным до обязательной смены пароля (claim pwd_change)
+func RequirePasswordChanged(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, claims, _ := jwtauth.FromContext(r.Context())
+		if mustChange, _ := claims["pwd_change"].(bool); mustChange {
+			http.Error(w, `{"error":"password change required"}`, http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// RequirePermission — пускает, только если у роли пользователя есть все перечисленные права
+func RequirePermission(perms ...access.Permission) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			_, claims, _ := jwtauth.FromContext(r.Context())
-			userRole, ok := claims["role"].(string)
-			if !ok {
+			p := access.FromContext(r.Context())
+			if p.Role == "" {
 				http.Error(w, `{"error":"unauthorized"}`, http.StatusUnauthorized)
 				return
 			}
-
-			for _, role := range roles {
-				if userRole == role {
-					next.ServeHTTP(w, r)
+			for _, perm := range perms {
+				if !p.Can(perm) {
+					http.Error(w, `{"error":"forbidden"}`, http.StatusForbidden)
 					return
 				}
 			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
 
+// RequireDistrict — только пользователи уровня района (roo, inspector)
+func RequireDistrict(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !access.FromContext(r.Context()).IsDistrict() {
 			http.Error(w, `{"error":"forbidden"}`, http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// AuditActor — кладёт в контекст исполнителя запроса (пользователь, роль, IP) для журнала изменений
+func AuditActor(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p := access.FromContext(r.Context())
+		ctx := audit.WithActor(r.Context(), audit.Actor{
+			UserID: p.UserID,
+			Role:   p.Role,
+			IP:     helpers.ClientIP(r),
 		})
-	}
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
 }
@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// AcademicYear — учебный год; текущий задаёт РОО
+type AcademicYear struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name" example:"2025/2026"`
+	StartDate time.Time `json:"start_date"`
+	EndDate   time.Time `json:"end_date"`
+	IsCurrent bool      `json:"is_current"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,51 @@
+package models
+
+import "time"
+
+// Отметки посещаемости
+const (
+	AttendancePresent   = "present"   // присутствовал
+	AttendanceSick      = "sick"      // отсутствовал по болезни
+	AttendanceExcused   = "excused"   // отсутствовал по уважительной причине
+	AttendanceUnexcused = "unexcused" // отсутствовал без уважительной причины
+	AttendanceLate      = "late"      // опоздал
+)
+
+// AttendanceRecord — ученик класса и его отметка за день (nil — не отмечен)
+type AttendanceRecord struct {
+	StudentID int        `json:"student_id"`
+	FullName  string     `json:"full_name"`
+	Status    *string    `json:"status,omitempty" example:"present"`
+	Note      *string    `json:"note,omitempty"`
+	MarkedBy  *int       `json:"marked_by,omitempty"`
+	MarkedAt  *time.Time `json:"marked_at,omitempty"`
+}
+
+// ClassAttendance — посещаемость класса за день
+type ClassAttendance struct {
+	ClassID int                `json:"class_id"`
+	Date    string             `json:"date" example:"2025-11-17"`
+	Records []AttendanceRecord `json:"records"`
+}
+
+// AttendanceMark — отметка ученика в пакетной отправке
+type AttendanceMark struct {
+	StudentID int     `json:"student_id"`
+	Status    string  `json:"status" example:"present"` // present | sick | excused | unexcused | late
+	Note      *string `json:"note,omitempty"`
+}
+
+// AttendanceDay — посещаемость школы за день для сводки РОО
+type AttendanceDay struct {
+	Date       string  `json:"date" example:"2025-11-17"`
+	SchoolID   int     `json:"school_id"`
+	SchoolName string  `json:"school_name"`
+	Students   int     `json:"students"` // обучающихся в школе сейчас
+	Marked     int     `json:"marked"`   // отмечено за день
+	Present    int     `json:"present"`
+	Late       int     `json:"late"`
+	Sick       int     `json:"sick"`
+	Excused    int     `json:"excused"`
+	Unexcused  int     `json:"unexcused"`
+	Rate       float64 `json:"rate" example:"94.5"` // доля присутствовавших (present + late) от отмеченных, %
+}
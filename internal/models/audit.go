@@ -0,0 +1,21 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// AuditEntry — запись журнала изменений: кто, что и как поменял
+type AuditEntry struct {
+	ID         int64           `json:"id"`
+	UserID     *int            `json:"user_id,omitempty"`
+	Role       *string         `json:"role,omitempty"`
+	SchoolID   *int            `json:"school_id,omitempty"`
+	EntityType string          `json:"entity_type"` // student | staff | class | school | user
+	EntityID   int             `json:"entity_id"`
+	Action     string          `json:"action"` // create | update | delete | restore | reset_password | change_password
+	Before     json.RawMessage `json:"before,omitempty" swaggertype:"object"`
+	After      json.RawMessage `json:"after,omitempty" swaggertype:"object"`
+	IP         *string         `json:"ip,omitempty"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
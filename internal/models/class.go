@@ -3,10 +3,12 @@ package models
 import "time"
 
 type Class struct {
-	ID           int       `json:"id"`
-	Name         string    `json:"name"`
-	Grade        int       `json:"grade"`
-	SchoolID     int       `json:"school_id"`
-	StudentCount int       `json:"student_count"`
-	CreatedAt    time.Time `json:"created_at"`
+	ID             int       `json:"id"`
+	Name           string    `json:"name"`
+	Grade          int       `json:"grade"`
+	SchoolID       int       `json:"school_id"`
+	AcademicYearID int       `json:"academic_year_id"`           // учебный год; при создании по умолчанию текущий
+	ClassTeacherID *int      `json:"class_teacher_id,omitempty"` // классный руководитель (сотрудник школы)
+	StudentCount   int       `json:"student_count"`
+	CreatedAt      time.Time `json:"created_at"`
 }
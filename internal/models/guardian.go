@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// Guardian — родитель или законный представитель ученика
+type Guardian struct {
+	ID        int       `json:"id"`
+	FullName  string    `json:"full_name" validate:"required"`
+	Relation  string    `json:"relation" example:"mother"` // mother | father | guardian (опекун) | trustee (попечитель) | other
+	Phones    []string  `json:"phones"`
+	Email     *string   `json:"email,omitempty"`
+	Workplace *string   `json:"workplace,omitempty"`
+	IsPrimary bool      `json:"is_primary"` // основной контакт по этому ученику
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,18 @@
+package models
+
+// ImportError — ошибка в строке загружаемого файла
+type ImportError struct {
+	Row     int    `json:"row" example:"7"` // номер строки в файле, как в Excel
+	Column  string `json:"column,omitempty" example:"Дата рождения"`
+	Message string `json:"message" example:"invalid date"`
+}
+
+// ImportReport — результат проверки или загрузки файла.
+// При любой ошибке не загружается ни одна строка.
+type ImportReport struct {
+	DryRun   bool          `json:"dry_run"`
+	Rows     int           `json:"rows"`              // строк с данными
+	Imported int           `json:"imported"`          // загружено (0 при dry_run и ошибках)
+	Updated  int           `json:"updated,omitempty"` // из них обновлено существующих записей
+	Errors   []ImportError `json:"errors"`
+}
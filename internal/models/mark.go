@@ -0,0 +1,105 @@
+package models
+
+import "time"
+
+// Периоды итоговых отметок
+const (
+	PeriodQ1   = "q1"   // I четверть
+	PeriodQ2   = "q2"   // II четверть
+	PeriodQ3   = "q3"   // III четверть
+	PeriodQ4   = "q4"   // IV четверть
+	PeriodH1   = "h1"   // I полугодие
+	PeriodH2   = "h2"   // II полугодие
+	PeriodYear = "year" // годовая
+)
+
+// Mark — отметка ученика по предмету за урок
+type Mark struct {
+	ID        int64     `json:"id"`
+	StudentID int       `json:"student_id"`
+	SubjectID int       `json:"subject_id"`
+	Date      string    `json:"date" example:"2025-11-20"`
+	Mark      int       `json:"mark" example:"5"`
+	Note      *string   `json:"note,omitempty"`
+	MarkedBy  *int      `json:"marked_by,omitempty"`
+	MarkedAt  time.Time `json:"marked_at"`
+}
+
+// TermMark — итоговая отметка за четверть, полугодие или год
+type TermMark struct {
+	ID             int       `json:"id"`
+	StudentID      int       `json:"student_id"`
+	ClassID        int       `json:"class_id"`
+	AcademicYearID int       `json:"academic_year_id"`
+	SubjectID      int       `json:"subject_id"`
+	Period         string    `json:"period" example:"q1"` // q1–q4, h1, h2, year
+	Mark           int       `json:"mark" example:"4"`
+	MarkedBy       *int      `json:"marked_by,omitempty"`
+	MarkedAt       time.Time `json:"marked_at"`
+}
+
+// JournalRow — строка журнала: ученик, его отметки за период и итоговые по предмету
+type JournalRow struct {
+	StudentID int        `json:"student_id"`
+	FullName  string     `json:"full_name"`
+	Marks     []Mark     `json:"marks"`
+	Average   *float64   `json:"average,omitempty" example:"4.25"` // средний балл отметок периода
+	TermMarks []TermMark `json:"term_marks"`
+}
+
+// ClassJournal — журнал класса по предмету за период
+type ClassJournal struct {
+	ClassID   int          `json:"class_id"`
+	SubjectID int          `json:"subject_id"`
+	From      string       `json:"from" example:"2025-11-01"`
+	To        string       `json:"to" example:"2025-11-30"`
+	Students  []JournalRow `json:"students"`
+}
+
+// MarkEntry — отметка ученика в пакетной отправке; Mark == nil снимает отметку
+type MarkEntry struct {
+	StudentID int     `json:"student_id"`
+	Mark      *int    `json:"mark,omitempty" example:"5"`
+	Note      *string `json:"note,omitempty"`
+}
+
+// SubjectMarks — отметки ученика по одному предмету за учебный год
+type SubjectMarks struct {
+	SubjectID   int        `json:"subject_id"`
+	SubjectName string     `json:"subject_name"`
+	Marks       []Mark     `json:"marks"`
+	Average     *float64   `json:"average,omitempty"`
+	TermMarks   []TermMark `json:"term_marks"`
+}
+
+// StudentMarks — успеваемость ученика за учебный год
+type StudentMarks struct {
+	StudentID      int            `json:"student_id"`
+	AcademicYearID int            `json:"academic_year_id"`
+	Subjects       []SubjectMarks `json:"subjects"`
+}
+
+// PerformanceRow — успеваемость класса, школы или района по итоговым отметкам периода.
+// Ученик учитывается, если у него есть хотя бы одна итоговая отметка; оценивается по худшей из них.
+type PerformanceRow struct {
+	SchoolID    *int    `json:"school_id,omitempty"`
+	SchoolName  string  `json:"school_name,omitempty"`
+	ClassID     *int    `json:"class_id,omitempty"`
+	ClassName   string  `json:"class_name,omitempty"`
+	Students    int     `json:"students"`                    // аттестованных учеников
+	Excellent   int     `json:"excellent"`                   // только «5»
+	Good        int     `json:"good"`                        // «4» и «5»
+	Passed      int     `json:"passed"`                      // без «2»
+	Failing     int     `json:"failing"`                     // есть «2»
+	QualityRate float64 `json:"quality_rate" example:"48.5"` // качество знаний: доля учеников на «4» и «5», %
+	SuccessRate float64 `json:"success_rate" example:"97.2"` // успеваемость: доля учеников без «2», %
+}
+
+// PerformanceStats — сводка успеваемости за период
+type PerformanceStats struct {
+	AcademicYearID int              `json:"academic_year_id"`
+	Period         string           `json:"period" example:"q1"`
+	District       *PerformanceRow  `json:"district,omitempty"` // только для всего района
+	Schools        []PerformanceRow `json:"schools"`
+	Classes        []PerformanceRow `json:"classes"`
+}
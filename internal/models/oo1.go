@@ -0,0 +1,62 @@
+package models
+
+// OO1Report — разделы формы федерального статистического наблюдения ОО-1 на дату
+type OO1Report struct {
+	Date           string `json:"date" example:"2025-09-20"`
+	AgeDate        string `json:"age_date" example:"2026-01-01"` // возраст обучающихся — на 1 января учебного года
+	AcademicYearID int    `json:"academic_year_id"`
+	SchoolID       *int   `json:"school_id,omitempty"` // nil — весь район
+	SchoolName     string `json:"school_name,omitempty"`
+
+	Classes  int `json:"classes"`
+	Students int `json:"students"`
+	Girls    int `json:"girls"`
+	Staff    int `json:"staff"`
+	Teachers int `json:"teachers"`
+
+	Grades     []OO1GradeRow `json:"grades"`     // классы и обучающиеся по параллелям
+	Ages       []OO1AgeRow   `json:"ages"`       // обучающиеся по возрасту и параллелям
+	Education  []OO1StaffRow `json:"education"`  // работники по уровню образования
+	Categories []OO1StaffRow `json:"categories"` // работники по квалификационной категории
+	Experience []OO1StaffRow `json:"experience"` // работники по педагогическому стажу
+}
+
+// OO1GradeRow — параллель: число классов и обучающихся
+type OO1GradeRow struct {
+	Grade    int `json:"grade"`
+	Classes  int `json:"classes"`
+	Students int `json:"students"`
+	Girls    int `json:"girls"`
+}
+
+// OO1AgeRow — обучающиеся одного возраста, всего и по параллелям
+type OO1AgeRow struct {
+	Age      string      `json:"age" example:"7 лет"`
+	Students int         `json:"students"`
+	Girls    int         `json:"girls"`
+	ByGrade  map[int]int `json:"by_grade"` // параллель → обучающихся
+}
+
+// OO1StaffRow — строка раздела о работниках: всего и из них учителей
+type OO1StaffRow struct {
+	Label    string `json:"label" example:"Высшее"`
+	Staff    int    `json:"staff"`
+	Teachers int    `json:"teachers"`
+}
+
+// OO1StudentGroup — обучающиеся одной параллели, пола и возраста (строка выборки для ОО-1)
+type OO1StudentGroup struct {
+	Grade  int
+	Gender *string
+	Age    *int
+	Count  int
+}
+
+// OO1StaffGroup — работники с одинаковыми образованием, категорией и стажем (строка выборки для ОО-1)
+type OO1StaffGroup struct {
+	Education     string
+	Category      string
+	PedExperience *int
+	Teacher       bool
+	Count         int
+}
@@ -0,0 +1,19 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// PDAccessEntry — факт просмотра персональных данных учеников
+type PDAccessEntry struct {
+	ID         int64           `json:"id"`
+	UserID     *int            `json:"user_id,omitempty"`
+	UserEmail  *string         `json:"user_email,omitempty"`
+	Role       *string         `json:"role,omitempty"`
+	Action     string          `json:"action"` // view | list | export
+	StudentIDs []int           `json:"student_ids"`
+	Filter     json.RawMessage `json:"filter,omitempty" swaggertype:"object"`
+	IP         *string         `json:"ip,omitempty"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
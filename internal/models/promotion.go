@@ -0,0 +1,60 @@
+package models
+
+// Действия над учеником при переводе на следующий год
+const (
+	PromotionPromote  = "promote"  // переведён в следующий класс
+	PromotionRepeat   = "repeat"   // оставлен на повторное обучение
+	PromotionGraduate = "graduate" // окончил школу
+	PromotionLeave    = "leave"    // выбыл
+)
+
+// PromotionRequest — перевод классов на следующий учебный год
+type PromotionRequest struct {
+	TargetAcademicYearID int   `json:"target_academic_year_id" example:"2"`
+	SchoolID             *int  `json:"school_id,omitempty"` // только РОО; без school_id — все школы района
+	Repeaters            []int `json:"repeaters,omitempty"` // ID учеников, оставленных на повторное обучение
+	Leavers              []int `json:"leavers,omitempty"`   // ID выбывающих учеников (например, после 9 класса)
+	DryRun               bool  `json:"dry_run"`             // true — только показать изменения
+}
+
+// PromotionClass — что происходит с классом текущего года
+type PromotionClass struct {
+	SchoolID    int    `json:"school_id"`
+	FromClassID int    `json:"from_class_id"`
+	FromName    string `json:"from_name" example:"5А"`
+	FromGrade   int    `json:"from_grade" example:"5"`
+	Action      string `json:"action" example:"promote"` // promote | graduate
+	ToClassID   *int   `json:"to_class_id,omitempty"`    // nil — выпуск или класс будет создан (dry_run)
+	ToName      string `json:"to_name,omitempty" example:"6А"`
+	ToGrade     int    `json:"to_grade,omitempty" example:"6"`
+	Created     bool   `json:"created"` // класс нового года создаётся при переводе
+}
+
+// PromotionStudent — что происходит с учеником
+type PromotionStudent struct {
+	StudentID   int    `json:"student_id"`
+	FullName    string `json:"full_name"`
+	SchoolID    int    `json:"school_id"`
+	FromClassID int    `json:"from_class_id"`
+	Action      string `json:"action" example:"promote"` // promote | repeat | graduate | leave
+	ToClassID   *int   `json:"to_class_id,omitempty"`
+	ToClassName string `json:"to_class_name,omitempty"`
+}
+
+type PromotionTotals struct {
+	Promoted       int `json:"promoted"`
+	Repeated       int `json:"repeated"`
+	Graduated      int `json:"graduated"`
+	Left           int `json:"left"`
+	ClassesCreated int `json:"classes_created"`
+}
+
+// PromotionResult — план (dry_run) или итог перевода
+type PromotionResult struct {
+	SourceAcademicYearID int                `json:"source_academic_year_id"`
+	TargetAcademicYearID int                `json:"target_academic_year_id"`
+	DryRun               bool               `json:"dry_run"`
+	Classes              []PromotionClass   `json:"classes"`
+	Students             []PromotionStudent `json:"students"`
+	Totals               PromotionTotals    `json:"totals"`
+}
@@ -3,10 +3,9 @@ package models
 import "time"
 
 type UserInfo struct {
-	ID       int    `json:"id"`
-	Email    string `json:"email"`
-	Password string `json:"password"`
-	Role     string `json:"role"`
+	ID    int    `json:"id"`
+	Email string `json:"email"`
+	Role  string `json:"role"`
 }
 
 type School struct {
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// Session — серверная сессия пользователя, к которой привязан refresh-токен.
+type Session struct {
+	ID               int64      `json:"id"`
+	UserID           int        `json:"user_id"`
+	RefreshTokenHash string     `json:"-"`
+	ExpiresAt        time.Time  `json:"expires_at"`
+	Expired          bool       `json:"-"` // срок истёк по часам БД (заполняется FindByRefreshHash)
+	RevokedAt        *time.Time `json:"revoked_at,omitempty"`
+	ReplacedBy       *int64     `json:"replaced_by,omitempty"`
+	UserAgent        *string    `json:"user_agent,omitempty"`
+	IP               *string    `json:"ip,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+}
@@ -5,6 +5,7 @@ import "time"
 type Staff struct {
 	ID              int        `json:"id"`
 	FullName        string     `json:"full_name" validate:"required"`
+	BirthDate       *time.Time `json:"birth_date,omitempty"`
 	Phone           string     `json:"phone" validate:"required"`
 	Position        string     `json:"position" validate:"required"`
 	Subject         *string    `json:"subject,omitempty"`
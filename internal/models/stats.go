@@ -7,3 +7,140 @@ type StatsSummary struct {
 	Teachers   int `json:"teachers"`
 	StaffTotal int `json:"staff_total"`
 }
+
+// GenderCount — число учеников всего и по полу; пол не указан — total - male - female
+type GenderCount struct {
+	Total  int `json:"total"`
+	Male   int `json:"male"`
+	Female int `json:"female"`
+}
+
+// Add учитывает count учеников пола gender
+func (g *GenderCount) Add(gender *string, count int) {
+	g.Total += count
+	if gender == nil {
+		return
+	}
+	switch *gender {
+	case "male":
+		g.Male += count
+	case "female":
+		g.Female += count
+	}
+}
+
+// StudentStats — состав учеников: возрастная пирамида, пол по параллелям, наполняемость классов, разрез по школам
+type StudentStats struct {
+	AcademicYearID int    `json:"academic_year_id"`
+	Date           string `json:"date" example:"2025-09-01"` // дата, на которую считается возраст
+	GenderCount
+	Ages       []StudentAgeRow    `json:"ages"`
+	Grades     []StudentGradeRow  `json:"grades"`
+	ClassSizes []ClassSizeBand    `json:"class_sizes"`
+	Schools    []StudentSchoolRow `json:"schools"`
+}
+
+// StudentAgeRow — ученики одного возраста (полных лет на дату); age отсутствует — дата рождения не указана
+type StudentAgeRow struct {
+	Age *int `json:"age,omitempty"`
+	GenderCount
+}
+
+// StudentGradeRow — ученики параллели по полу
+type StudentGradeRow struct {
+	Grade int `json:"grade"`
+	GenderCount
+}
+
+// ClassSizeBand — классы с числом учеников в интервале [min, max] (max отсутствует — без верхней границы)
+type ClassSizeBand struct {
+	Band     string `json:"band" example:"21–25"`
+	Min      int    `json:"min"`
+	Max      *int   `json:"max,omitempty"`
+	Classes  int    `json:"classes"`
+	Students int    `json:"students"`
+}
+
+// StudentSchoolRow — ученики школы по полу, число классов и средняя наполняемость
+type StudentSchoolRow struct {
+	SchoolID     int     `json:"school_id"`
+	SchoolName   string  `json:"school_name"`
+	Classes      int     `json:"classes"`
+	AvgClassSize float64 `json:"avg_class_size"`
+	GenderCount
+}
+
+// StudentGroup — ученики одного класса с одинаковыми полом и возрастом (строка выборки для статистики)
+type StudentGroup struct {
+	SchoolID   int
+	SchoolName string
+	ClassID    int
+	Grade      int
+	Gender     *string
+	Age        *int
+	Count      int
+}
+
+// StaffStats — кадровый состав: учителя по квалификационной категории, образованию и педагогическому стажу,
+// предпенсионного возраста и молодые специалисты; доли — в процентах от учителей
+type StaffStats struct {
+	Staff                 int              `json:"staff"`
+	Teachers              int              `json:"teachers"`
+	Categories            []TeacherGroup   `json:"categories"`
+	Education             []TeacherGroup   `json:"education"`
+	Experience            []TeacherGroup   `json:"experience"`
+	NearRetirement        int              `json:"near_retirement"`
+	NearRetirementShare   float64          `json:"near_retirement_share"`
+	YoungSpecialists      int              `json:"young_specialists"`
+	YoungSpecialistsShare float64          `json:"young_specialists_share"`
+	NoBirthDate           int              `json:"no_birth_date"` // учителя без даты рождения — возраст неизвестен
+	Schools               []StaffSchoolRow `json:"schools"`
+}
+
+// TeacherGroup — учителя с одним значением признака и их доля
+type TeacherGroup struct {
+	Label    string  `json:"label" example:"Высшая"`
+	Teachers int     `json:"teachers"`
+	Share    float64 `json:"share"`
+}
+
+// StaffSchoolRow — кадровый состав школы
+type StaffSchoolRow struct {
+	SchoolID         int     `json:"school_id"`
+	SchoolName       string  `json:"school_name"`
+	Staff            int     `json:"staff"`
+	Teachers         int     `json:"teachers"`
+	HighestCategory  int     `json:"highest_category"`
+	FirstCategory    int     `json:"first_category"`
+	CategoryShare    float64 `json:"category_share"` // доля учителей с высшей или первой категорией
+	HigherEducation  int     `json:"higher_education"`
+	NearRetirement   int     `json:"near_retirement"`
+	YoungSpecialists int     `json:"young_specialists"`
+}
+
+// StaffGroup — сотрудники школы с одинаковыми признаками (строка выборки для статистики)
+type StaffGroup struct {
+	SchoolID      int
+	SchoolName    string
+	Teacher       bool
+	Education     string
+	Category      string
+	PedExperience *int
+	Age           *int
+	Count         int
+}
+
+// StatsPoint — значение показателя на дату снимка
+type StatsPoint struct {
+	Date  string `json:"date" example:"2025-09-01"`
+	Value int    `json:"value"`
+}
+
+// StatsHistory — ряд значений показателя сводной статистики по снимкам
+type StatsHistory struct {
+	Metric   string       `json:"metric" example:"students"`
+	SchoolID *int         `json:"school_id,omitempty"` // nil — весь район
+	From     string       `json:"from" example:"2025-09-01"`
+	To       string       `json:"to" example:"2026-05-31"`
+	Points   []StatsPoint `json:"points"`
+}
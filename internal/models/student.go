@@ -2,6 +2,13 @@ package models
 
 import "time"
 
+// Статусы ученика
+const (
+	StudentActive    = "active"    // учится
+	StudentGraduated = "graduated" // окончил школу
+	StudentLeft      = "left"      // выбыл
+)
+
 type Student struct {
 	ID        int        `json:"id"`
 	FullName  string     `json:"full_name" validate:"required"`
@@ -13,5 +20,6 @@ type Student struct {
 	ClassID   int        `json:"class_id"`
 	ClassName string     `json:"class"`
 	SchoolID  int        `json:"school_id"`
+	Status    string     `json:"status"` // active | graduated | left
 	CreatedAt time.Time  `json:"created_at"`
 }
@@ -0,0 +1,74 @@
+package models
+
+import "time"
+
+// Subject — предмет из справочника района
+type Subject struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name" example:"Математика"`
+	ShortName *string   `json:"short_name,omitempty" example:"Матем."`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TeachingAssignment — учитель ведёт предмет в классе; StaffID == nil — вакансия
+type TeachingAssignment struct {
+	ID             int       `json:"id"`
+	SchoolID       int       `json:"school_id"`
+	ClassID        int       `json:"class_id"`
+	ClassName      string    `json:"class_name"`
+	AcademicYearID int       `json:"academic_year_id"` // учебный год класса
+	SubjectID      int       `json:"subject_id"`
+	SubjectName    string    `json:"subject_name"`
+	StaffID        *int      `json:"staff_id,omitempty"`
+	StaffName      *string   `json:"staff_name,omitempty"`
+	HoursPerWeek   float64   `json:"hours_per_week" example:"4"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// Статусы нагрузки учителя относительно ставки
+const (
+	LoadOver   = "over"   // больше ставки
+	LoadUnder  = "under"  // меньше ставки
+	LoadNormal = "normal" // ровно ставка
+)
+
+// TeacherLoad — недельная нагрузка учителя в текущем учебном году
+type TeacherLoad struct {
+	StaffID    int     `json:"staff_id"`
+	FullName   string  `json:"full_name"`
+	Position   string  `json:"position"`
+	SchoolID   int     `json:"school_id"`
+	SchoolName string  `json:"school_name"`
+	Hours      float64 `json:"hours" example:"22"`
+	Rate       float64 `json:"rate" example:"1.22"` // доля ставки
+	Status     string  `json:"status" enums:"over,under,normal"`
+}
+
+// SubjectVacancy — незакрытые часы предмета в школе (назначения без учителя)
+type SubjectVacancy struct {
+	SchoolID    int     `json:"school_id"`
+	SchoolName  string  `json:"school_name"`
+	SubjectID   int     `json:"subject_id"`
+	SubjectName string  `json:"subject_name"`
+	Classes     int     `json:"classes"` // классов без учителя
+	Hours       float64 `json:"hours" example:"12"`
+	Teachers    int     `json:"teachers"` // нужно учителей: часы / ставка с округлением вверх
+}
+
+// SubjectNeed — потребность района в учителях предмета (сумма по школам)
+type SubjectNeed struct {
+	SubjectID   int     `json:"subject_id"`
+	SubjectName string  `json:"subject_name"`
+	Hours       float64 `json:"hours"`
+	Teachers    int     `json:"teachers"`
+}
+
+// TeachingLoad — отчёт о нагрузке и нехватке кадров
+type TeachingLoad struct {
+	StandardHours float64          `json:"standard_hours" example:"18"` // ставка, часов в неделю
+	Teachers      []TeacherLoad    `json:"teachers"`
+	Overloaded    int              `json:"overloaded"`
+	Underloaded   int              `json:"underloaded"`
+	Vacancies     []SubjectVacancy `json:"vacancies"`
+	Needed        []SubjectNeed    `json:"needed"`
+}
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// TimetableEntry — урок в расписании класса
+type TimetableEntry struct {
+	ID             int       `json:"id"`
+	SchoolID       int       `json:"school_id"`
+	ClassID        int       `json:"class_id"`
+	ClassName      string    `json:"class_name"`
+	AcademicYearID int       `json:"academic_year_id"`    // учебный год класса
+	Weekday        int       `json:"weekday" example:"1"` // 1 — понедельник … 6 — суббота
+	Lesson         int       `json:"lesson" example:"3"`  // номер урока, 1–12
+	SubjectID      int       `json:"subject_id"`
+	SubjectName    string    `json:"subject_name"`
+	StaffID        *int      `json:"staff_id,omitempty"`
+	StaffName      *string   `json:"staff_name,omitempty"`
+	Room           *string   `json:"room,omitempty" example:"204"`
+	CreatedAt      time.Time `json:"created_at"`
+}
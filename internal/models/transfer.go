@@ -0,0 +1,55 @@
+package models
+
+import "time"
+
+// Статусы заявки на перевод
+const (
+	TransferPending   = "pending"   // ждёт решения принимающей школы
+	TransferAccepted  = "accepted"  // ученик переведён
+	TransferRejected  = "rejected"  // принимающая школа отказала
+	TransferCancelled = "cancelled" // отозвана отправляющей школой
+)
+
+// StudentTransfer — заявка на перевод ученика в другую школу района
+type StudentTransfer struct {
+	ID           int        `json:"id"`
+	StudentID    int        `json:"student_id"`
+	StudentName  string     `json:"student_name"`
+	FromSchoolID int        `json:"from_school_id"`
+	FromSchool   string     `json:"from_school"`
+	FromClassID  *int       `json:"from_class_id,omitempty"`
+	ToSchoolID   int        `json:"to_school_id"`
+	ToSchool     string     `json:"to_school"`
+	ToClassID    *int       `json:"to_class_id,omitempty"` // класс, в который зачислен ученик
+	Status       string     `json:"status" example:"pending"`
+	Reason       *string    `json:"reason,omitempty"`
+	DecisionNote *string    `json:"decision_note,omitempty"`
+	CreatedBy    *int       `json:"created_by,omitempty"`
+	DecidedBy    *int       `json:"decided_by,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	DecidedAt    *time.Time `json:"decided_at,omitempty"`
+}
+
+// Виды записей движения учеников
+const (
+	MovementArrival     = "arrival"      // прибыл в школу
+	MovementDeparture   = "departure"    // выбыл из школы
+	MovementClassChange = "class_change" // сменил класс внутри школы
+)
+
+// StudentMovement — запись книги движения учеников
+type StudentMovement struct {
+	ID             int64     `json:"id"`
+	StudentID      int       `json:"student_id"`
+	SchoolID       int       `json:"school_id"`
+	Kind           string    `json:"kind" example:"arrival"`
+	FromSchoolID   *int      `json:"from_school_id,omitempty"`
+	ToSchoolID     *int      `json:"to_school_id,omitempty"`
+	FromClassID    *int      `json:"from_class_id,omitempty"`
+	ToClassID      *int      `json:"to_class_id,omitempty"`
+	TransferID     *int      `json:"transfer_id,omitempty"`
+	AcademicYearID *int      `json:"academic_year_id,omitempty"`
+	UserID         *int      `json:"user_id,omitempty"`
+	Note           *string   `json:"note,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+}
@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// TrashItem — запись в корзине
+type TrashItem struct {
+	EntityType string    `json:"entity_type"` // school | class | staff | student
+	ID         int       `json:"id"`
+	Name       string    `json:"name"`
+	SchoolID   int       `json:"school_id"`
+	ClassID    *int      `json:"class_id,omitempty"` // только для ученика
+	DeletedAt  time.Time `json:"deleted_at"`
+	DeletedBy  *int      `json:"deleted_by,omitempty"`
+}
@@ -2,10 +2,18 @@ package models
 
 import "time"
 
+// LockedPassword — пароль заблокированной учётной записи: не совпадает ни с одним паролем,
+// войти можно только после сброса пароля РОО
+const LockedPassword = "!"
+
 type User struct {
-	ID        int       `json:"id"`
-	Email     string    `json:"email"`
-	Password  string    `json:"-"`
-	Role      string    `json:"role"` // roo | school
-	CreatedAt time.Time `json:"created_at"`
+	ID                 int       `json:"id"`
+	Email              string    `json:"email"`
+	Password           string    `json:"-"`
+	FullName           *string   `json:"full_name,omitempty"`
+	Role               string    `json:"role"` // roo | inspector | director | deputy | secretary | teacher
+	SchoolID           *int      `json:"school_id,omitempty"`
+	ClassID            *int      `json:"class_id,omitempty"`
+	MustChangePassword bool      `json:"must_change_password"`
+	CreatedAt          time.Time `json:"created_at"`
 }
@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"eduBase/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	ErrAcademicYearNotFound = errors.New("academic year not found")
+	ErrAcademicYearExists   = errors.New("academic year with this name already exists")
+	ErrNoCurrentYear        = errors.New("current academic year is not set")
+)
+
+// currentYearSQL — подзапрос id текущего учебного года
+const currentYearSQL = `(SELECT id FROM academic_years WHERE is_current)`
+
+type AcademicYearRepository struct {
+	db DBTX
+}
+
+func NewAcademicYearRepository(db DBTX) *AcademicYearRepository {
+	return &AcademicYearRepository{db: db}
+}
+
+func (r *AcademicYearRepository) DB() DBTX { return r.db }
+
+const academicYearColumns = `id, name, start_date, end_date, is_current, created_at`
+
+func scanAcademicYear(row pgx.Row) (*models.AcademicYear, error) {
+	var y models.AcademicYear
+	if err := row.Scan(&y.ID, &y.Name, &y.StartDate, &y.EndDate, &y.IsCurrent, &y.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &y, nil
+}
+
+func (r *AcademicYearRepository) Create(ctx context.Context, y *models.AcademicYear) error {
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO academic_years (name, start_date, end_date)
+		VALUES ($1,$2,$3)
+		RETURNING id, is_current, created_at`,
+		y.Name, y.StartDate, y.EndDate,
+	).Scan(&y.ID, &y.IsCurrent, &y.CreatedAt)
+	if isUniqueViolation(err) {
+		return ErrAcademicYearExists
+	}
+	return err
+}
+
+func (r *AcademicYearRepository) GetAll(ctx context.Context) ([]models.AcademicYear, error) {
+	rows, err := r.db.Query(ctx, `SELECT `+academicYearColumns+` FROM academic_years ORDER BY start_date DESC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []models.AcademicYear
+	for rows.Next() {
+		y, err := scanAcademicYear(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, *y)
+	}
+	return list, rows.Err()
+}
+
+func (r *AcademicYearRepository) GetByID(ctx context.Context, id int) (*models.AcademicYear, error) {
+	y, err := scanAcademicYear(r.db.QueryRow(ctx,
+		`SELECT `+academicYearColumns+` FROM academic_years WHERE id=$1`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrAcademicYearNotFound
+	}
+	return y, err
+}
+
+func (r *AcademicYearRepository) GetCurrent(ctx context.Context) (*models.AcademicYear, error) {
+	y, err := scanAcademicYear(r.db.QueryRow(ctx,
+		`SELECT `+academicYearColumns+` FROM academic_years WHERE is_current`))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNoCurrentYear
+	}
+	return y, err
+}
+
+// GetByDate — учебный год, в который попадает дата
+func (r *AcademicYearRepository) GetByDate(ctx context.Context, date time.Time) (*models.AcademicYear, error) {
+	y, err := scanAcademicYear(r.db.QueryRow(ctx,
+		`SELECT `+academicYearColumns+` FROM academic_years WHERE $1::date BETWEEN start_date AND end_date
+		 ORDER BY start_date DESC LIMIT 1`, date))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrAcademicYearNotFound
+	}
+	return y, err
+}
+
+// SetCurrent делает год текущим, снимая отметку с предыдущего
+func (r *AcademicYearRepository) SetCurrent(ctx context.Context, id int) error {
+	if _, err := r.db.Exec(ctx, `UPDATE academic_years SET is_current=FALSE WHERE is_current AND id<>$1`, id); err != nil {
+		return err
+	}
+	res, err := r.db.Exec(ctx, `UPDATE academic_years SET is_current=TRUE WHERE id=$1`, id)
+	if err != nil {
+		return err
+	}
+	if res.RowsAffected() == 0 {
+		return ErrAcademicYearNotFound
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"eduBase/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	ErrAssignmentNotFound = errors.New("teaching assignment not found")
+	ErrAssignmentExists   = errors.New("teacher already teaches this subject in this class")
+)
+
+// AssignmentFilter — SchoolID != nil ограничивает назначениями школы; AcademicYearID == nil — текущий год
+type AssignmentFilter struct {
+	SchoolID       *int
+	ClassID        *int
+	StaffID        *int
+	SubjectID      *int
+	AcademicYearID *int
+}
+
+type AssignmentRepository struct {
+	db DBTX
+}
+
+func NewAssignmentRepository(db DBTX) *AssignmentRepository {
+	return &AssignmentRepository{db: db}
+}
+
+func (r *AssignmentRepository) DB() DBTX { return r.db }
+
+// уволенный (удалённый) сотрудник в назначениях не показывается — место считается вакантным
+const assignmentSelect = `
+	SELECT a.id, a.school_id, a.class_id, c.name, c.academic_year_id, a.subject_id, sb.name,
+	       st.id, st.full_name, a.hours_per_week::float8, a.created_at
+	FROM teaching_assignments a
+	JOIN classes c ON c.id = a.class_id AND c.deleted_at IS NULL
+	JOIN subjects sb ON sb.id = a.subject_id
+	LEFT JOIN staff st ON st.id = a.staff_id AND st.deleted_at IS NULL`
+
+func scanAssignment(row pgx.Row) (*models.TeachingAssignment, error) {
+	var a models.TeachingAssignment
+	if err := row.Scan(
+		&a.ID, &a.SchoolID, &a.ClassID, &a.ClassName, &a.AcademicYearID, &a.SubjectID, &a.SubjectName,
+		&a.StaffID, &a.StaffName, &a.HoursPerWeek, &a.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func (r *AssignmentRepository) Create(ctx context.Context, a *models.TeachingAssignment) error {
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO teaching_assignments (school_id, class_id, subject_id, staff_id, hours_per_week)
+		VALUES ($1,$2,$3,$4,$5)
+		RETURNING id, created_at`,
+		a.SchoolID, a.ClassID, a.SubjectID, a.StaffID, a.HoursPerWeek,
+	).Scan(&a.ID, &a.CreatedAt)
+	if isUniqueViolation(err) {
+		return ErrAssignmentExists
+	}
+	return err
+}
+
+func (r *AssignmentRepository) GetByID(ctx context.Context, id int) (*models.TeachingAssignment, error) {
+	a, err := scanAssignment(r.db.QueryRow(ctx, assignmentSelect+` WHERE a.id=$1`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrAssignmentNotFound
+	}
+	return a, err
+}
+
+func (r *AssignmentRepository) GetAll(ctx context.Context, f AssignmentFilter) ([]models.TeachingAssignment, error) {
+	where := []string{fmt.Sprintf("c.academic_year_id=COALESCE($1::int, %s)", currentYearSQL)}
+	args := []any{f.AcademicYearID}
+	i := 2
+
+	if f.SchoolID != nil {
+		where = append(where, fmt.Sprintf("a.school_id=$%d", i))
+		args = append(args, *f.SchoolID)
+		i++
+	}
+	if f.ClassID != nil {
+		where = append(where, fmt.Sprintf("a.class_id=$%d", i))
+		args = append(args, *f.ClassID)
+		i++
+	}
+	if f.StaffID != nil {
+		where = append(where, fmt.Sprintf("a.staff_id=$%d", i))
+		args = append(args, *f.StaffID)
+		i++
+	}
+	if f.SubjectID != nil {
+		where = append(where, fmt.Sprintf("a.subject_id=$%d", i))
+		args = append(args, *f.SubjectID)
+		i++
+	}
+
+	query := assignmentSelect + " WHERE " + strings.Join(where, " AND ") + " ORDER BY c.grade, c.name, sb.name, a.id"
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []models.TeachingAssignment{}
+	for rows.Next() {
+		a, err := scanAssignment(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, *a)
+	}
+	return list, rows.Err()
+}
+
+// Update меняет учителя и часы; класс и предмет назначения не меняются
+func (r *AssignmentRepository) Update(ctx context.Context, id int, staffID *int, hours float64) error {
+	_, err := r.db.Exec(ctx, `
+		UPDATE teaching_assignments SET staff_id=$2, hours_per_week=$3 WHERE id=$1`, id, staffID, hours)
+	if isUniqueViolation(err) {
+		return ErrAssignmentExists
+	}
+	return err
+}
+
+func (r *AssignmentRepository) Delete(ctx context.Context, id int) error {
+	_, err := r.db.Exec(ctx, `DELETE FROM teaching_assignments WHERE id=$1`, id)
+	return err
+}
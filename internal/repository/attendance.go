@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"eduBase/internal/models"
+)
+
+type AttendanceRepository struct {
+	db DBTX
+}
+
+func NewAttendanceRepository(db DBTX) *AttendanceRepository {
+	return &AttendanceRepository{db: db}
+}
+
+func (r *AttendanceRepository) DB() DBTX { return r.db }
+
+// GetByClass — обучающиеся класса с отметками за день; неотмеченные — со status = nil
+func (r *AttendanceRepository) GetByClass(ctx context.Context, classID int, date time.Time) ([]models.AttendanceRecord, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT s.id, s.full_name, a.status, a.note, a.marked_by, a.marked_at
+		FROM students s
+		LEFT JOIN attendance a ON a.student_id = s.id AND a.date = $2
+		WHERE s.class_id=$1 AND s.deleted_at IS NULL AND s.status='active'
+		ORDER BY s.full_name, s.id`, classID, date)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []models.AttendanceRecord{}
+	for rows.Next() {
+		var a models.AttendanceRecord
+		if err := rows.Scan(&a.StudentID, &a.FullName, &a.Status, &a.Note, &a.MarkedBy, &a.MarkedAt); err != nil {
+			return nil, err
+		}
+		list = append(list, a)
+	}
+	return list, rows.Err()
+}
+
+// Upsert ставит или исправляет отметку ученика за день
+func (r *AttendanceRepository) Upsert(ctx context.Context, m models.AttendanceMark, classID, schoolID int, date time.Time, markedBy *int) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO attendance (student_id, class_id, school_id, date, status, note, marked_by)
+		VALUES ($1,$2,$3,$4,$5,$6,$7)
+		ON CONFLICT (student_id, date) DO UPDATE
+		SET class_id=EXCLUDED.class_id, school_id=EXCLUDED.school_id, status=EXCLUDED.status,
+		    note=EXCLUDED.note, marked_by=EXCLUDED.marked_by, marked_at=NOW()`,
+		m.StudentID, classID, schoolID, date, m.Status, m.Note, markedBy)
+	return err
+}
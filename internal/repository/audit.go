@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"eduBase/internal/models"
+)
+
+type AuditFilter struct {
+	SchoolID   *int
+	EntityType string
+	EntityID   *int
+	UserID     *int
+	From       *time.Time
+	To         *time.Time
+	Limit      int
+	Offset     int
+}
+
+type AuditRepository struct {
+	db DBTX
+}
+
+func NewAuditRepository(db DBTX) *AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+func (r *AuditRepository) Insert(ctx context.Context, e *models.AuditEntry) error {
+	return r.db.QueryRow(ctx, `
+		INSERT INTO audit_log (user_id, role, school_id, entity_type, entity_id, action, before, after, ip)
+		VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9)
+		RETURNING id, created_at`,
+		e.UserID, e.Role, e.SchoolID, e.EntityType, e.EntityID, e.Action, e.Before, e.After, e.IP,
+	).Scan(&e.ID, &e.CreatedAt)
+}
+
+func (r *AuditRepository) GetAll(ctx context.Context, f AuditFilter) ([]models.AuditEntry, error) {
+	base := `
+	SELECT id, user_id, role, school_id, entity_type, entity_id, action, before, after, ip, created_at
+	FROM audit_log`
+	var where []string
+	var args []any
+	i := 1
+
+	if f.SchoolID != nil {
+		where = append(where, fmt.Sprintf("school_id=$%d", i))
+		args = append(args, *f.SchoolID)
+		i++
+	}
+	if f.EntityType != "" {
+		where = append(where, fmt.Sprintf("entity_type=$%d", i))
+		args = append(args, f.EntityType)
+		i++
+	}
+	if f.EntityID != nil {
+		where = append(where, fmt.Sprintf("entity_id=$%d", i))
+		args = append(args, *f.EntityID)
+		i++
+	}
+	if f.UserID != nil {
+		where = append(where, fmt.Sprintf("user_id=$%d", i))
+		args = append(args, *f.UserID)
+		i++
+	}
+	if f.From != nil {
+		where = append(where, fmt.Sprintf("created_at >= $%d", i))
+		args = append(args, *f.From)
+		i++
+	}
+	if f.To != nil {
+		where = append(where, fmt.Sprintf("created_at < $%d", i))
+		args = append(args, *f.To)
+		i++
+	}
+
+	query := base
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += fmt.Sprintf(" ORDER BY created_at DESC, id DESC LIMIT $%d OFFSET $%d", i, i+1)
+	args = append(args, f.Limit, f.Offset)
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []models.AuditEntry
+	for rows.Next() {
+		var e models.AuditEntry
+		if err := rows.Scan(
+			&e.ID, &e.UserID, &e.Role, &e.SchoolID, &e.EntityType, &e.EntityID,
+			&e.Action, &e.Before, &e.After, &e.IP, &e.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		list = append(list, e)
+	}
+	return list, rows.Err()
+}
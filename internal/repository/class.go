@@ -3,7 +3,7 @@ package repository
 import (
 	"context"
 	"errors"
-	"github.com/jackc/pgx/v5/pgconn"
+	"time"
 
 	"eduBase/internal/models"
 	"github.com/jackc/pgx/v5"
@@ -12,44 +12,39 @@ import (
 var ErrClassNotFound = errors.New("class not found")
 
 type ClassRepository struct {
-	db *pgx.Conn
+	db DBTX
 }
 
-func NewClassRepository(db *pgx.Conn) *ClassRepository {
+func NewClassRepository(db DBTX) *ClassRepository {
 	return &ClassRepository{db: db}
 }
 
 func (r *ClassRepository) Create(ctx context.Context, c *models.Class) error {
 	return r.db.QueryRow(ctx,
-		`INSERT INTO classes (name, grade, school_id)
-		 VALUES ($1,$2,$3) RETURNING id,created_at`,
-		c.Name, c.Grade, c.SchoolID,
+		`INSERT INTO classes (name, grade, school_id, academic_year_id, class_teacher_id)
+		 VALUES ($1,$2,$3,$4,$5) RETURNING id,created_at`,
+		c.Name, c.Grade, c.SchoolID, c.AcademicYearID, c.ClassTeacherID,
 	).Scan(&c.ID, &c.CreatedAt)
 }
 
-func (r *ClassRepository) GetAll(ctx context.Context) ([]models.Class, error) {
-	rows, err := r.db.Query(ctx,
-		`SELECT id,name,grade,school_id,student_count,created_at
-		 FROM classes ORDER BY id`)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var res []models.Class
-	for rows.Next() {
-		var c models.Class
-		if err := rows.Scan(&c.ID, &c.Name, &c.Grade, &c.SchoolID, &c.StudentCount, &c.CreatedAt); err != nil {
-			return nil, err
-		}
-		res = append(res, c)
-	}
-	return res, nil
+// GetAll — классы всех школ за учебный год; yearID == nil — текущий год
+func (r *ClassRepository) GetAll(ctx context.Context, yearID *int) ([]models.Class, error) {
+	return r.list(ctx, nil, yearID)
 }
 
-func (r *ClassRepository) GetBySchool(ctx context.Context, schoolID int) ([]models.Class, error) {
+// GetBySchool — классы школы за учебный год; yearID == nil — текущий год
+func (r *ClassRepository) GetBySchool(ctx context.Context, schoolID int, yearID *int) ([]models.Class, error) {
+	return r.list(ctx, &schoolID, yearID)
+}
+
+func (r *ClassRepository) list(ctx context.Context, schoolID, yearID *int) ([]models.Class, error) {
 	rows, err := r.db.Query(ctx,
-		`SELECT id,name,grade,school_id,student_count,created_at
-		 FROM classes WHERE school_id=$1 ORDER BY id`, schoolID)
+		`SELECT id,name,grade,school_id,academic_year_id,class_teacher_id,student_count,created_at
+		 FROM classes
+		 WHERE deleted_at IS NULL
+		   AND ($1::int IS NULL OR school_id=$1)
+		   AND academic_year_id=COALESCE($2::int, `+currentYearSQL+`)
+		 ORDER BY grade, name, id`, schoolID, yearID)
 	if err != nil {
 		return nil, err
 	}
@@ -57,48 +52,54 @@ func (r *ClassRepository) GetBySchool(ctx context.Context, schoolID int) ([]mode
 	var res []models.Class
 	for rows.Next() {
 		var c models.Class
-		if err := rows.Scan(&c.ID, &c.Name, &c.Grade, &c.SchoolID, &c.StudentCount, &c.CreatedAt); err != nil {
+		if err := rows.Scan(&c.ID, &c.Name, &c.Grade, &c.SchoolID, &c.AcademicYearID, &c.ClassTeacherID, &c.StudentCount, &c.CreatedAt); err != nil {
 			return nil, err
 		}
 		res = append(res, c)
 	}
-	return res, nil
+	return res, rows.Err()
 }
 
-func (r *ClassRepository) Update(ctx context.Context, id int, c *models.Class, role string) (int64, error) {
-	var res pgconn.CommandTag
-	var err error
-
-	if role == "roo" {
-		res, err = r.db.Exec(ctx,
-			`UPDATE classes SET name=$1, grade=$2 WHERE id=$3`,
-			c.Name, c.Grade, id,
-		)
-	} else {
-		res, err = r.db.Exec(ctx,
-			`UPDATE classes SET name=$1, grade=$2 WHERE id=$3 AND school_id=$4`,
-			c.Name, c.Grade, id, c.SchoolID,
-		)
-	}
-
+// Update обновляет класс; schoolID != nil ограничивает обновление классами этой школы
+func (r *ClassRepository) Update(ctx context.Context, id int, c *models.Class, schoolID *int) (int64, error) {
+	res, err := r.db.Exec(ctx,
+		`UPDATE classes SET name=$1, grade=$2, class_teacher_id=$5
+		 WHERE id=$3 AND ($4::int IS NULL OR school_id=$4) AND deleted_at IS NULL`,
+		c.Name, c.Grade, id, schoolID, c.ClassTeacherID,
+	)
 	if err != nil {
 		return 0, err
 	}
 	return res.RowsAffected(), nil
 }
 
-func (r *ClassRepository) Delete(ctx context.Context, id int, schoolID int) error {
-	_, err := r.db.Exec(ctx, `DELETE FROM classes WHERE id=$1 AND school_id=$2`, id, schoolID)
-	return err
+// SoftDelete переносит класс в корзину вместе с его учениками (общая отметка deleted_at).
+// Возвращает перенесённых учеников: id, школа, класс и статус.
+func (r *ClassRepository) SoftDelete(ctx context.Context, id int, schoolID int, deletedBy *int) ([]models.Student, error) {
+	var deletedAt time.Time
+	err := r.db.QueryRow(ctx, `
+		UPDATE classes SET deleted_at=NOW(), deleted_by=$3
+		WHERE id=$1 AND school_id=$2 AND deleted_at IS NULL
+		RETURNING deleted_at`, id, schoolID, deletedBy).Scan(&deletedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrClassNotFound
+		}
+		return nil, err
+	}
+	return collectPlaces(r.db.Query(ctx, `
+		UPDATE students SET deleted_at=$2, deleted_by=$3
+		WHERE class_id=$1 AND deleted_at IS NULL
+		RETURNING id, school_id, class_id, status`, id, deletedAt, deletedBy))
 }
 
 func (r *ClassRepository) GetByID(ctx context.Context, id int) (*models.Class, error) {
 	row := r.db.QueryRow(ctx, `
-		SELECT id, name, grade, school_id, student_count, created_at
-		FROM classes WHERE id=$1
+		SELECT id, name, grade, school_id, academic_year_id, class_teacher_id, student_count, created_at
+		FROM classes WHERE id=$1 AND deleted_at IS NULL
 	`, id)
 	var c models.Class
-	if err := row.Scan(&c.ID, &c.Name, &c.Grade, &c.SchoolID, &c.StudentCount, &c.CreatedAt); err != nil {
+	if err := row.Scan(&c.ID, &c.Name, &c.Grade, &c.SchoolID, &c.AcademicYearID, &c.ClassTeacherID, &c.StudentCount, &c.CreatedAt); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, ErrClassNotFound
 		}
@@ -107,4 +108,16 @@ func (r *ClassRepository) GetByID(ctx context.Context, id int) (*models.Class, e
 	return &c, nil
 }
 
-func (r *ClassRepository) DB() *pgx.Conn { return r.db }
+// RefreshStudentCount пересчитывает classes.student_count по фактическим ученикам
+func (r *ClassRepository) RefreshStudentCount(ctx context.Context, classID int) error {
+	_, err := r.db.Exec(ctx, `
+		UPDATE classes
+		SET student_count=(
+			SELECT COUNT(*) FROM students
+			WHERE class_id=$1 AND deleted_at IS NULL AND status='active'
+		)
+		WHERE id=$1`, classID)
+	return err
+}
+
+func (r *ClassRepository) DB() DBTX { return r.db }
@@ -0,0 +1,33 @@
+package repository
+
+import "context"
+
+type EnrollmentRepository struct {
+	db DBTX
+}
+
+func NewEnrollmentRepository(db DBTX) *EnrollmentRepository {
+	return &EnrollmentRepository{db: db}
+}
+
+// Upsert фиксирует класс ученика в учебном году (один класс на год)
+func (r *EnrollmentRepository) Upsert(ctx context.Context, studentID, yearID, classID, schoolID int) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO student_enrollments (student_id, academic_year_id, class_id, school_id)
+		VALUES ($1,$2,$3,$4)
+		ON CONFLICT (student_id, academic_year_id)
+		DO UPDATE SET class_id=EXCLUDED.class_id, school_id=EXCLUDED.school_id`,
+		studentID, yearID, classID, schoolID)
+	return err
+}
+
+// CountInYear — сколько из указанных учеников уже зачислены в учебный год
+func (r *EnrollmentRepository) CountInYear(ctx context.Context, yearID int, studentIDs []int) (int, error) {
+	var n int
+	err := r.db.QueryRow(ctx, `
+		SELECT COUNT(*) FROM student_enrollments
+		WHERE academic_year_id=$1 AND student_id = ANY($2)`, yearID, studentIDs).Scan(&n)
+	return n, err
+}
+
+func (r *EnrollmentRepository) DB() DBTX { return r.db }
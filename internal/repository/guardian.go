@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"eduBase/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+var ErrGuardianNotFound = errors.New("guardian not found")
+
+type GuardianRepository struct {
+	db DBTX
+}
+
+func NewGuardianRepository(db DBTX) *GuardianRepository {
+	return &GuardianRepository{db: db}
+}
+
+func (r *GuardianRepository) DB() DBTX { return r.db }
+
+const guardianSelect = `
+	SELECT g.id, g.full_name, g.relation, g.phones, g.email, g.workplace, sg.is_primary, g.created_at
+	FROM guardians g
+	JOIN student_guardians sg ON sg.guardian_id = g.id`
+
+func scanGuardian(row pgx.Row) (*models.Guardian, error) {
+	var g models.Guardian
+	if err := row.Scan(&g.ID, &g.FullName, &g.Relation, &g.Phones, &g.Email, &g.Workplace, &g.IsPrimary, &g.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &g, nil
+}
+
+func (r *GuardianRepository) Create(ctx context.Context, g *models.Guardian) error {
+	return r.db.QueryRow(ctx, `
+		INSERT INTO guardians (full_name, relation, phones, email, workplace)
+		VALUES ($1,$2,$3,$4,$5)
+		RETURNING id, created_at`,
+		g.FullName, g.Relation, g.Phones, g.Email, g.Workplace,
+	).Scan(&g.ID, &g.CreatedAt)
+}
+
+func (r *GuardianRepository) Update(ctx context.Context, id int, g *models.Guardian) error {
+	_, err := r.db.Exec(ctx, `
+		UPDATE guardians SET full_name=$2, relation=$3, phones=$4, email=$5, workplace=$6
+		WHERE id=$1`, id, g.FullName, g.Relation, g.Phones, g.Email, g.Workplace)
+	return err
+}
+
+// Link связывает представителя с учеником; isPrimary снимает отметку основного контакта с остальных
+func (r *GuardianRepository) Link(ctx context.Context, studentID, guardianID int, isPrimary bool) error {
+	if isPrimary {
+		if _, err := r.db.Exec(ctx, `
+			UPDATE student_guardians SET is_primary=FALSE
+			WHERE student_id=$1 AND guardian_id<>$2 AND is_primary`, studentID, guardianID); err != nil {
+			return err
+		}
+	}
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO student_guardians (student_id, guardian_id, is_primary)
+		VALUES ($1,$2,$3)
+		ON CONFLICT (student_id, guardian_id) DO UPDATE SET is_primary=EXCLUDED.is_primary`,
+		studentID, guardianID, isPrimary)
+	return err
+}
+
+// Unlink убирает связь; false — представитель не был связан с учеником
+func (r *GuardianRepository) Unlink(ctx context.Context, studentID, guardianID int) (bool, error) {
+	res, err := r.db.Exec(ctx, `DELETE FROM student_guardians WHERE student_id=$1 AND guardian_id=$2`, studentID, guardianID)
+	if err != nil {
+		return false, err
+	}
+	return res.RowsAffected() > 0, nil
+}
+
+// DeleteIfOrphan удаляет представителя, не связанного ни с одним учеником
+func (r *GuardianRepository) DeleteIfOrphan(ctx context.Context, id int) (bool, error) {
+	res, err := r.db.Exec(ctx, `
+		DELETE FROM guardians g
+		WHERE g.id=$1 AND NOT EXISTS (SELECT 1 FROM student_guardians WHERE guardian_id=g.id)`, id)
+	if err != nil {
+		return false, err
+	}
+	return res.RowsAffected() > 0, nil
+}
+
+// GetForStudent — представитель, связанный с учеником
+func (r *GuardianRepository) GetForStudent(ctx context.Context, studentID, guardianID int) (*models.Guardian, error) {
+	g, err := scanGuardian(r.db.QueryRow(ctx, guardianSelect+` WHERE sg.student_id=$1 AND g.id=$2`, studentID, guardianID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrGuardianNotFound
+	}
+	return g, err
+}
+
+// Exists — представитель есть; schoolID != nil — и связан хотя бы с одним учеником этой школы
+func (r *GuardianRepository) Exists(ctx context.Context, id int, schoolID *int) (bool, error) {
+	var ok bool
+	err := r.db.QueryRow(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM guardians g
+			WHERE g.id=$1 AND ($2::int IS NULL OR EXISTS (
+				SELECT 1 FROM student_guardians sg
+				JOIN students s ON s.id = sg.student_id
+				WHERE sg.guardian_id=g.id AND s.school_id=$2 AND s.deleted_at IS NULL)))`, id, schoolID).Scan(&ok)
+	return ok, err
+}
+
+// GetByStudent — представители ученика, основной контакт первым
+func (r *GuardianRepository) GetByStudent(ctx context.Context, studentID int) ([]models.Guardian, error) {
+	rows, err := r.db.Query(ctx, guardianSelect+` WHERE sg.student_id=$1 ORDER BY sg.is_primary DESC, g.full_name, g.id`, studentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []models.Guardian{}
+	for rows.Next() {
+		g, err := scanGuardian(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, *g)
+	}
+	return list, rows.Err()
+}
+
+// GetByStudents — представители нескольких учеников одним запросом (для выгрузок)
+func (r *GuardianRepository) GetByStudents(ctx context.Context, studentIDs []int) (map[int][]models.Guardian, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT sg.student_id, g.id, g.full_name, g.relation, g.phones, g.email, g.workplace, sg.is_primary, g.created_at
+		FROM guardians g
+		JOIN student_guardians sg ON sg.guardian_id = g.id
+		WHERE sg.student_id = ANY($1)
+		ORDER BY sg.student_id, sg.is_primary DESC, g.full_name, g.id`, studentIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := make(map[int][]models.Guardian)
+	for rows.Next() {
+		var studentID int
+		var g models.Guardian
+		if err := rows.Scan(&studentID, &g.ID, &g.FullName, &g.Relation, &g.Phones, &g.Email, &g.Workplace, &g.IsPrimary, &g.CreatedAt); err != nil {
+			return nil, err
+		}
+		res[studentID] = append(res[studentID], g)
+	}
+	return res, rows.Err()
+}
@@ -0,0 +1,178 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"eduBase/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+type MarkRepository struct {
+	db DBTX
+}
+
+func NewMarkRepository(db DBTX) *MarkRepository {
+	return &MarkRepository{db: db}
+}
+
+func (r *MarkRepository) DB() DBTX { return r.db }
+
+// JournalStudents — строки журнала класса: обучающиеся ученики и выбывшие, у которых есть отметки по предмету в этом классе
+func (r *MarkRepository) JournalStudents(ctx context.Context, classID, subjectID int) ([]models.JournalRow, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT s.id, s.full_name
+		FROM students s
+		WHERE s.deleted_at IS NULL
+		  AND ((s.class_id=$1 AND s.status='active')
+		       OR EXISTS (SELECT 1 FROM marks m WHERE m.student_id=s.id AND m.class_id=$1 AND m.subject_id=$2)
+		       OR EXISTS (SELECT 1 FROM term_marks t WHERE t.student_id=s.id AND t.class_id=$1 AND t.subject_id=$2))
+		ORDER BY s.full_name, s.id`, classID, subjectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []models.JournalRow{}
+	for rows.Next() {
+		row := models.JournalRow{Marks: []models.Mark{}, TermMarks: []models.TermMark{}}
+		if err := rows.Scan(&row.StudentID, &row.FullName); err != nil {
+			return nil, err
+		}
+		list = append(list, row)
+	}
+	return list, rows.Err()
+}
+
+const markSelect = `
+	SELECT m.id, m.student_id, m.subject_id, to_char(m.date, 'YYYY-MM-DD'), m.mark, m.note, m.marked_by, m.marked_at
+	FROM marks m`
+
+func (r *MarkRepository) queryMarks(ctx context.Context, sql string, args ...any) ([]models.Mark, error) {
+	rows, err := r.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []models.Mark{}
+	for rows.Next() {
+		var m models.Mark
+		if err := rows.Scan(&m.ID, &m.StudentID, &m.SubjectID, &m.Date, &m.Mark, &m.Note, &m.MarkedBy, &m.MarkedAt); err != nil {
+			return nil, err
+		}
+		list = append(list, m)
+	}
+	return list, rows.Err()
+}
+
+// GetByClass — отметки класса по предмету за период [from, to]
+func (r *MarkRepository) GetByClass(ctx context.Context, classID, subjectID int, from, to time.Time) ([]models.Mark, error) {
+	return r.queryMarks(ctx, markSelect+`
+		WHERE m.class_id=$1 AND m.subject_id=$2 AND m.date BETWEEN $3 AND $4
+		ORDER BY m.date, m.id`, classID, subjectID, from, to)
+}
+
+// GetByStudent — отметки ученика за учебный год по всем предметам
+func (r *MarkRepository) GetByStudent(ctx context.Context, studentID, yearID int) ([]models.Mark, error) {
+	return r.queryMarks(ctx, markSelect+`
+		JOIN classes c ON c.id = m.class_id
+		WHERE m.student_id=$1 AND c.academic_year_id=$2
+		ORDER BY m.subject_id, m.date, m.id`, studentID, yearID)
+}
+
+// GetForStudents — отметки учеников по предмету за дату (в любом классе)
+func (r *MarkRepository) GetForStudents(ctx context.Context, studentIDs []int, subjectID int, date time.Time) ([]models.Mark, error) {
+	return r.queryMarks(ctx, markSelect+`
+		WHERE m.student_id = ANY($1) AND m.subject_id=$2 AND m.date=$3
+		ORDER BY m.student_id`, studentIDs, subjectID, date)
+}
+
+// Upsert ставит или исправляет отметку ученика по предмету за дату
+func (r *MarkRepository) Upsert(ctx context.Context, studentID, classID, schoolID, subjectID int, date time.Time, mark int, note *string, markedBy *int) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO marks (student_id, class_id, school_id, subject_id, date, mark, note, marked_by)
+		VALUES ($1,$2,$3,$4,$5,$6,$7,$8)
+		ON CONFLICT (student_id, subject_id, date) DO UPDATE
+		SET class_id=EXCLUDED.class_id, school_id=EXCLUDED.school_id, mark=EXCLUDED.mark,
+		    note=EXCLUDED.note, marked_by=EXCLUDED.marked_by, marked_at=NOW()`,
+		studentID, classID, schoolID, subjectID, date, mark, note, markedBy)
+	return err
+}
+
+// Delete снимает отметку ученика по предмету за дату
+func (r *MarkRepository) Delete(ctx context.Context, studentID, subjectID int, date time.Time) error {
+	_, err := r.db.Exec(ctx, `DELETE FROM marks WHERE student_id=$1 AND subject_id=$2 AND date=$3`, studentID, subjectID, date)
+	return err
+}
+
+const termMarkSelect = `
+	SELECT t.id, t.student_id, t.class_id, t.academic_year_id, t.subject_id, t.period, t.mark, t.marked_by, t.marked_at
+	FROM term_marks t`
+
+func scanTermMarks(rows pgx.Rows) ([]models.TermMark, error) {
+	defer rows.Close()
+
+	list := []models.TermMark{}
+	for rows.Next() {
+		var t models.TermMark
+		if err := rows.Scan(&t.ID, &t.StudentID, &t.ClassID, &t.AcademicYearID, &t.SubjectID, &t.Period, &t.Mark, &t.MarkedBy, &t.MarkedAt); err != nil {
+			return nil, err
+		}
+		list = append(list, t)
+	}
+	return list, rows.Err()
+}
+
+// GetTermByClass — итоговые отметки класса по предмету
+func (r *MarkRepository) GetTermByClass(ctx context.Context, classID, subjectID int) ([]models.TermMark, error) {
+	rows, err := r.db.Query(ctx, termMarkSelect+`
+		WHERE t.class_id=$1 AND t.subject_id=$2
+		ORDER BY t.student_id, t.id`, classID, subjectID)
+	if err != nil {
+		return nil, err
+	}
+	return scanTermMarks(rows)
+}
+
+// GetTermByStudent — итоговые отметки ученика за учебный год
+func (r *MarkRepository) GetTermByStudent(ctx context.Context, studentID, yearID int) ([]models.TermMark, error) {
+	rows, err := r.db.Query(ctx, termMarkSelect+`
+		WHERE t.student_id=$1 AND t.academic_year_id=$2
+		ORDER BY t.subject_id, t.id`, studentID, yearID)
+	if err != nil {
+		return nil, err
+	}
+	return scanTermMarks(rows)
+}
+
+// GetTermForStudents — итоговые отметки учеников по предмету за период учебного года
+func (r *MarkRepository) GetTermForStudents(ctx context.Context, studentIDs []int, subjectID, yearID int, period string) ([]models.TermMark, error) {
+	rows, err := r.db.Query(ctx, termMarkSelect+`
+		WHERE t.student_id = ANY($1) AND t.subject_id=$2 AND t.academic_year_id=$3 AND t.period=$4
+		ORDER BY t.student_id`, studentIDs, subjectID, yearID, period)
+	if err != nil {
+		return nil, err
+	}
+	return scanTermMarks(rows)
+}
+
+// UpsertTerm ставит или исправляет итоговую отметку ученика по предмету за период учебного года класса
+func (r *MarkRepository) UpsertTerm(ctx context.Context, studentID int, c *models.Class, subjectID int, period string, mark int, markedBy *int) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO term_marks (student_id, class_id, school_id, academic_year_id, subject_id, period, mark, marked_by)
+		VALUES ($1,$2,$3,$4,$5,$6,$7,$8)
+		ON CONFLICT (student_id, subject_id, academic_year_id, period) DO UPDATE
+		SET class_id=EXCLUDED.class_id, school_id=EXCLUDED.school_id, mark=EXCLUDED.mark,
+		    marked_by=EXCLUDED.marked_by, marked_at=NOW()`,
+		studentID, c.ID, c.SchoolID, c.AcademicYearID, subjectID, period, mark, markedBy)
+	return err
+}
+
+// DeleteTerm снимает итоговую отметку ученика
+func (r *MarkRepository) DeleteTerm(ctx context.Context, studentID, subjectID, yearID int, period string) error {
+	_, err := r.db.Exec(ctx, `
+		DELETE FROM term_marks WHERE student_id=$1 AND subject_id=$2 AND academic_year_id=$3 AND period=$4`,
+		studentID, subjectID, yearID, period)
+	return err
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+
+	"eduBase/internal/models"
+)
+
+// MovementRepository — книга движения учеников
+type MovementRepository struct {
+	db DBTX
+}
+
+func NewMovementRepository(db DBTX) *MovementRepository {
+	return &MovementRepository{db: db}
+}
+
+func (r *MovementRepository) DB() DBTX { return r.db }
+
+// Create добавляет запись; учебный год — текущий
+func (r *MovementRepository) Create(ctx context.Context, m *models.StudentMovement) error {
+	return r.db.QueryRow(ctx, `
+		INSERT INTO student_movements (
+			student_id, school_id, kind, from_school_id, to_school_id,
+			from_class_id, to_class_id, transfer_id, academic_year_id, user_id, note
+		) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,`+currentYearSQL+`,$9,$10)
+		RETURNING id, academic_year_id, created_at`,
+		m.StudentID, m.SchoolID, m.Kind, m.FromSchoolID, m.ToSchoolID,
+		m.FromClassID, m.ToClassID, m.TransferID, m.UserID, m.Note,
+	).Scan(&m.ID, &m.AcademicYearID, &m.CreatedAt)
+}
+
+// ListByStudent — история движения ученика. schoolID != nil — только если ученик когда-либо
+// числился в этой школе (текущая школа и школа, из которой он выбыл, видят всю историю).
+func (r *MovementRepository) ListByStudent(ctx context.Context, studentID int, schoolID *int) ([]models.StudentMovement, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT id, student_id, school_id, kind, from_school_id, to_school_id,
+		       from_class_id, to_class_id, transfer_id, academic_year_id, user_id, note, created_at
+		FROM student_movements
+		WHERE student_id=$1
+		  AND ($2::int IS NULL OR EXISTS (
+		      SELECT 1 FROM student_movements WHERE student_id=$1 AND school_id=$2))
+		ORDER BY created_at, id`, studentID, schoolID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []models.StudentMovement{}
+	for rows.Next() {
+		var m models.StudentMovement
+		if err := rows.Scan(
+			&m.ID, &m.StudentID, &m.SchoolID, &m.Kind, &m.FromSchoolID, &m.ToSchoolID,
+			&m.FromClassID, &m.ToClassID, &m.TransferID, &m.AcademicYearID, &m.UserID, &m.Note, &m.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		list = append(list, m)
+	}
+	return list, rows.Err()
+}
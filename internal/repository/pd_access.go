@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"eduBase/internal/models"
+)
+
+type PDAccessFilter struct {
+	StudentID *int
+	UserID    *int
+	From      *time.Time
+	To        *time.Time
+	Limit     int
+	Offset    int
+}
+
+type PDAccessRepository struct {
+	db DBTX
+}
+
+func NewPDAccessRepository(db DBTX) *PDAccessRepository {
+	return &PDAccessRepository{db: db}
+}
+
+func (r *PDAccessRepository) Insert(ctx context.Context, e *models.PDAccessEntry) error {
+	return r.db.QueryRow(ctx, `
+		INSERT INTO pd_access_log (user_id, role, action, student_ids, filter, ip)
+		VALUES ($1,$2,$3,$4,$5,$6)
+		RETURNING id, created_at`,
+		e.UserID, e.Role, e.Action, e.StudentIDs, e.Filter, e.IP,
+	).Scan(&e.ID, &e.CreatedAt)
+}
+
+func (r *PDAccessRepository) GetAll(ctx context.Context, f PDAccessFilter) ([]models.PDAccessEntry, error) {
+	base := `
+	SELECT l.id, l.user_id, u.email, l.role, l.action, l.student_ids, l.filter, l.ip, l.created_at
+	FROM pd_access_log l
+	LEFT JOIN users u ON u.id = l.user_id`
+	var where []string
+	var args []any
+	i := 1
+
+	if f.StudentID != nil {
+		where = append(where, fmt.Sprintf("l.student_ids @> ARRAY[$%d::int]", i))
+		args = append(args, *f.StudentID)
+		i++
+	}
+	if f.UserID != nil {
+		where = append(where, fmt.Sprintf("l.user_id=$%d", i))
+		args = append(args, *f.UserID)
+		i++
+	}
+	if f.From != nil {
+		where = append(where, fmt.Sprintf("l.created_at >= $%d", i))
+		args = append(args, *f.From)
+		i++
+	}
+	if f.To != nil {
+		where = append(where, fmt.Sprintf("l.created_at < $%d", i))
+		args = append(args, *f.To)
+		i++
+	}
+
+	query := base
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += fmt.Sprintf(" ORDER BY l.created_at DESC, l.id DESC LIMIT $%d OFFSET $%d", i, i+1)
+	args = append(args, f.Limit, f.Offset)
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []models.PDAccessEntry
+	for rows.Next() {
+		var e models.PDAccessEntry
+		if err := rows.Scan(
+			&e.ID, &e.UserID, &e.UserEmail, &e.Role, &e.Action,
+			&e.StudentIDs, &e.Filter, &e.IP, &e.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		list = append(list, e)
+	}
+	return list, rows.Err()
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"errors"
+	"time"
 
 	"eduBase/internal/models"
 	"github.com/jackc/pgx/v5"
@@ -11,10 +12,10 @@ import (
 var ErrSchoolNotFound = errors.New("school not found")
 
 type SchoolRepository struct {
-	db *pgx.Conn
+	db DBTX
 }
 
-func NewSchoolRepository(db *pgx.Conn) *SchoolRepository {
+func NewSchoolRepository(db DBTX) *SchoolRepository {
 	return &SchoolRepository{db: db}
 }
 
@@ -32,9 +33,10 @@ func (r *SchoolRepository) GetAll(ctx context.Context) ([]models.School, error)
 	rows, err := r.db.Query(ctx, `
 		SELECT 
 			s.id, s.name, s.director, s.class_count, s.student_count, s.user_id, s.created_at,
-			u.id, u.email, u.password, u.role
+			u.id, u.email, u.role
 		FROM schools s
 		JOIN users u ON u.id = s.user_id
+		WHERE s.deleted_at IS NULL
 		ORDER BY s.id
 	`)
 	if err != nil {
@@ -57,7 +59,6 @@ func (r *SchoolRepository) GetAll(ctx context.Context) ([]models.School, error)
 			&s.CreatedAt,
 			&u.ID,
 			&u.Email,
-			&u.Password,
 			&u.Role,
 		); err != nil {
 			return nil, err
@@ -73,10 +74,10 @@ func (r *SchoolRepository) GetByID(ctx context.Context, id int) (*models.School,
 	row := r.db.QueryRow(ctx, `
 		SELECT 
 			s.id, s.name, s.director, s.class_count, s.student_count, s.user_id, s.created_at,
-			u.id, u.email, u.password, u.role
+			u.id, u.email, u.role
 		FROM schools s
 		JOIN users u ON u.id = s.user_id
-		WHERE s.id = $1
+		WHERE s.id = $1 AND s.deleted_at IS NULL
 	`, id)
 
 	var s models.School
@@ -92,7 +93,6 @@ func (r *SchoolRepository) GetByID(ctx context.Context, id int) (*models.School,
 		&s.CreatedAt,
 		&u.ID,
 		&u.Email,
-		&u.Password,
 		&u.Role,
 	); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -109,20 +109,43 @@ func (r *SchoolRepository) Update(ctx context.Context, id int, s *models.School)
 	_, err := r.db.Exec(ctx, `
 		UPDATE schools
 		SET name=$1, director=$2
-		WHERE id=$3
+		WHERE id=$3 AND deleted_at IS NULL
 	`, s.Name, s.Director, id)
 	return err
 }
 
-func (r *SchoolRepository) Delete(ctx context.Context, id int) error {
-	_, err := r.db.Exec(ctx, `DELETE FROM schools WHERE id=$1`, id)
-	return err
+// SoftDelete переносит школу в корзину вместе с её классами, сотрудниками и учениками.
+// Все записи получают одну и ту же отметку deleted_at — по ней восстановление
+// возвращает именно то, что было удалено вместе со школой. Возвращает перенесённых учеников.
+func (r *SchoolRepository) SoftDelete(ctx context.Context, id int, deletedBy *int) ([]models.Student, error) {
+	var deletedAt time.Time
+	err := r.db.QueryRow(ctx, `
+		UPDATE schools SET deleted_at=NOW(), deleted_by=$2
+		WHERE id=$1 AND deleted_at IS NULL
+		RETURNING deleted_at`, id, deletedBy).Scan(&deletedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrSchoolNotFound
+		}
+		return nil, err
+	}
+	for _, table := range []string{"staff", "classes"} {
+		if _, err := r.db.Exec(ctx, `
+			UPDATE `+table+` SET deleted_at=$2, deleted_by=$3
+			WHERE school_id=$1 AND deleted_at IS NULL`, id, deletedAt, deletedBy); err != nil {
+			return nil, err
+		}
+	}
+	return collectPlaces(r.db.Query(ctx, `
+		UPDATE students SET deleted_at=$2, deleted_by=$3
+		WHERE school_id=$1 AND deleted_at IS NULL
+		RETURNING id, school_id, class_id, status`, id, deletedAt, deletedBy))
 }
 
 func (r *SchoolRepository) GetByUserID(ctx context.Context, userID int) (*models.School, error) {
 	row := r.db.QueryRow(ctx, `
 		SELECT id, name, director, class_count, student_count, created_at
-		FROM schools WHERE user_id=$1
+		FROM schools WHERE user_id=$1 AND deleted_at IS NULL
 	`, userID)
 
 	var s models.School
@@ -135,6 +158,30 @@ func (r *SchoolRepository) GetByUserID(ctx context.Context, userID int) (*models
 	return &s, nil
 }
 
-func (r *SchoolRepository) DB() *pgx.Conn {
+// RefreshStudentCount пересчитывает schools.student_count по фактическим ученикам
+func (r *SchoolRepository) RefreshStudentCount(ctx context.Context, schoolID int) error {
+	_, err := r.db.Exec(ctx, `
+		UPDATE schools
+		SET student_count=(
+			SELECT COUNT(*) FROM students
+			WHERE school_id=$1 AND deleted_at IS NULL AND status='active'
+		)
+		WHERE id=$1`, schoolID)
+	return err
+}
+
+// RefreshClassCounts пересчитывает schools.class_count всех школ по классам текущего учебного года
+func (r *SchoolRepository) RefreshClassCounts(ctx context.Context) error {
+	_, err := r.db.Exec(ctx, `
+		UPDATE schools s
+		SET class_count=(
+			SELECT COUNT(*) FROM classes c
+			WHERE c.school_id=s.id AND c.deleted_at IS NULL
+			  AND c.academic_year_id=`+currentYearSQL+`
+		)`)
+	return err
+}
+
+func (r *SchoolRepository) DB() DBTX {
 	return r.db
 }
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"eduBase/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+var ErrSessionNotFound = errors.New("session not found")
+
+type SessionRepository struct {
+	db DBTX
+}
+
+func NewSessionRepository(db DBTX) *SessionRepository {
+	return &SessionRepository{db: db}
+}
+
+// Create создаёт сессию, которая истекает через ttl. Срок считается по часам БД —
+// по ним же проверяются IsActive и FindByRefreshHash.
+func (r *SessionRepository) Create(ctx context.Context, s *models.Session, ttl time.Duration) error {
+	return r.db.QueryRow(ctx, `
+		INSERT INTO sessions (user_id, refresh_token_hash, expires_at, user_agent, ip)
+		VALUES ($1, $2, NOW() + make_interval(secs => $3), $4, $5)
+		RETURNING id, expires_at, created_at`,
+		s.UserID, s.RefreshTokenHash, ttl.Seconds(), s.UserAgent, s.IP,
+	).Scan(&s.ID, &s.ExpiresAt, &s.CreatedAt)
+}
+
+// FindByRefreshHash ищет сессию по хэшу refresh-токена (FOR UPDATE — для ротации в транзакции)
+func (r *SessionRepository) FindByRefreshHash(ctx context.Context, hash string) (*models.Session, error) {
+	row := r.db.QueryRow(ctx, `
+		SELECT id, user_id, refresh_token_hash, expires_at, expires_at <= NOW(),
+		       revoked_at, replaced_by, user_agent, ip, created_at
+		FROM sessions WHERE refresh_token_hash=$1
+		FOR UPDATE`, hash)
+	var s models.Session
+	if err := row.Scan(
+		&s.ID, &s.UserID, &s.RefreshTokenHash, &s.ExpiresAt, &s.Expired, &s.RevokedAt,
+		&s.ReplacedBy, &s.UserAgent, &s.IP, &s.CreatedAt,
+	); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrSessionNotFound
+		}
+		return nil, err
+	}
+	return &s, nil
+}
+
+// IsActive — сессия существует, не отозвана и не истекла.
+// Вызывается middleware.Authenticator на каждый запрос.
+func (r *SessionRepository) IsActive(ctx context.Context, id int64) (bool, error) {
+	var ok bool
+	err := r.db.QueryRow(ctx, `
+		SELECT EXISTS(
+			SELECT 1 FROM sessions
+			WHERE id=$1 AND revoked_at IS NULL AND expires_at > NOW()
+		)`, id).Scan(&ok)
+	return ok, err
+}
+
+// Revoke отзывает одну сессию; replacedBy заполняется при ротации refresh-токена.
+func (r *SessionRepository) Revoke(ctx context.Context, id int64, replacedBy *int64) error {
+	_, err := r.db.Exec(ctx, `
+		UPDATE sessions SET revoked_at=NOW(), replaced_by=$1
+		WHERE id=$2 AND revoked_at IS NULL`, replacedBy, id)
+	return err
+}
+
+// RevokeAllForUser отзывает все активные сессии пользователя.
+func (r *SessionRepository) RevokeAllForUser(ctx context.Context, userID int) error {
+	_, err := r.db.Exec(ctx, `
+		UPDATE sessions SET revoked_at=NOW()
+		WHERE user_id=$1 AND revoked_at IS NULL`, userID)
+	return err
+}
+
+// RevokeAllForSchool отзывает активные сессии всех сотрудников школы.
+func (r *SessionRepository) RevokeAllForSchool(ctx context.Context, schoolID int) error {
+	_, err := r.db.Exec(ctx, `
+		UPDATE sessions SET revoked_at=NOW()
+		WHERE revoked_at IS NULL
+		  AND user_id IN (SELECT id FROM users WHERE school_id=$1)`, schoolID)
+	return err
+}
+
+func (r *SessionRepository) DB() DBTX {
+	return r.db
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"github.com/jackc/pgx/v5/pgconn"
 	"strings"
 
 	"eduBase/internal/models"
@@ -23,10 +22,10 @@ type StaffFilter struct {
 }
 
 type StaffRepository struct {
-	db *pgx.Conn
+	db DBTX
 }
 
-func NewStaffRepository(db *pgx.Conn) *StaffRepository {
+func NewStaffRepository(db DBTX) *StaffRepository {
 	return &StaffRepository{db: db}
 }
 
@@ -36,21 +35,31 @@ func (r *StaffRepository) Create(ctx context.Context, s *models.Staff) error {
 	query := `
 	INSERT INTO staff (
 		full_name, phone, position, subject, education, category,
-		ped_experience, total_experience, work_start, note, school_id
-	) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11)
+		ped_experience, total_experience, work_start, note, school_id, birth_date
+	) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12)
 	RETURNING id, created_at`
 	return r.db.QueryRow(ctx, query,
 		s.FullName, s.Phone, s.Position, s.Subject, s.Education, s.Category,
-		s.PedExperience, s.TotalExperience, s.WorkStart, s.Note, s.SchoolID,
+		s.PedExperience, s.TotalExperience, s.WorkStart, s.Note, s.SchoolID, s.BirthDate,
 	).Scan(&s.ID, &s.CreatedAt)
 }
 
 func (r *StaffRepository) GetAll(ctx context.Context, schoolID *int, f StaffFilter) ([]models.Staff, error) {
+	return r.find(ctx, schoolID, false, f)
+}
+
+// GetAllDistrict — сотрудники всех школ района (для выгрузок РОО и инспектора).
+// В отличие от GetAll, отсутствие школы здесь означает «весь район», а не пустой список.
+func (r *StaffRepository) GetAllDistrict(ctx context.Context, f StaffFilter) ([]models.Staff, error) {
+	return r.find(ctx, nil, true, f)
+}
+
+func (r *StaffRepository) find(ctx context.Context, schoolID *int, district bool, f StaffFilter) ([]models.Staff, error) {
 	base := `
 	SELECT id, full_name, phone, position, subject, education, category,
-	       ped_experience, total_experience, work_start, note, school_id, created_at
+	       ped_experience, total_experience, work_start, note, school_id, created_at, birth_date
 	FROM staff`
-	var where []string
+	where := []string{"deleted_at IS NULL"}
 	var args []any
 	i := 1
 
@@ -58,7 +67,7 @@ func (r *StaffRepository) GetAll(ctx context.Context, schoolID *int, f StaffFilt
 		where = append(where, fmt.Sprintf("school_id=$%d", i))
 		args = append(args, *schoolID)
 		i++
-	} else {
+	} else if !district {
 		// 🔒 safety: если schoolID == nil, вернётся пустой результат
 		where = append(where, "1=0")
 	}
@@ -97,7 +106,7 @@ func (r *StaffRepository) GetAll(ctx context.Context, schoolID *int, f StaffFilt
 		if err := rows.Scan(
 			&s.ID, &s.FullName, &s.Phone, &s.Position, &s.Subject, &s.Education,
 			&s.Category, &s.PedExperience, &s.TotalExperience,
-			&s.WorkStart, &s.Note, &s.SchoolID, &s.CreatedAt,
+			&s.WorkStart, &s.Note, &s.SchoolID, &s.CreatedAt, &s.BirthDate,
 		); err != nil {
 			return nil, err
 		}
@@ -106,27 +115,33 @@ func (r *StaffRepository) GetAll(ctx context.Context, schoolID *int, f StaffFilt
 	return list, nil
 }
 
-func (r *StaffRepository) Delete(ctx context.Context, id, schoolID int) error {
-	_, err := r.db.Exec(ctx, `DELETE FROM staff WHERE id=$1 AND school_id=$2`, id, schoolID)
-	return err
+// SoftDelete переносит сотрудника в корзину; schoolID != nil ограничивает удаление сотрудниками этой школы
+func (r *StaffRepository) SoftDelete(ctx context.Context, id int, schoolID *int, deletedBy *int) (int64, error) {
+	res, err := r.db.Exec(ctx, `
+		UPDATE staff SET deleted_at=NOW(), deleted_by=$3
+		WHERE id=$1 AND ($2::int IS NULL OR school_id=$2) AND deleted_at IS NULL`, id, schoolID, deletedBy)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected(), nil
 }
 
-func (r *StaffRepository) DB() *pgx.Conn {
+func (r *StaffRepository) DB() DBTX {
 	return r.db
 }
 
 func (r *StaffRepository) GetByID(ctx context.Context, id int) (*models.Staff, error) {
 	row := r.db.QueryRow(ctx, `
-		SELECT id, full_name, phone, position, education, category,
+		SELECT id, full_name, phone, position, subject, education, category,
 		       ped_experience, total_experience, work_start, note,
-		       school_id, created_at
-		FROM staff WHERE id=$1
+		       school_id, created_at, birth_date
+		FROM staff WHERE id=$1 AND deleted_at IS NULL
 	`, id)
 	var s models.Staff
 	if err := row.Scan(
-		&s.ID, &s.FullName, &s.Phone, &s.Position, &s.Education, &s.Category,
+		&s.ID, &s.FullName, &s.Phone, &s.Position, &s.Subject, &s.Education, &s.Category,
 		&s.PedExperience, &s.TotalExperience, &s.WorkStart, &s.Note,
-		&s.SchoolID, &s.CreatedAt,
+		&s.SchoolID, &s.CreatedAt, &s.BirthDate,
 	); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, ErrStaffNotFound
@@ -136,30 +151,17 @@ func (r *StaffRepository) GetByID(ctx context.Context, id int) (*models.Staff, e
 	return &s, nil
 }
 
-func (r *StaffRepository) Update(ctx context.Context, id int, s *models.Staff, role string) (int64, error) {
-	var res pgconn.CommandTag
-	var err error
-	if role == "roo" {
-		res, err = r.db.Exec(ctx, `
-			UPDATE staff
-			SET full_name=$1, phone=$2, position=$3, subject=$4,
-			    education=$5, category=$6, ped_experience=$7,
-			    total_experience=$8, work_start=$9, note=$10
-			WHERE id=$11`,
-			s.FullName, s.Phone, s.Position, s.Subject, s.Education, s.Category,
-			s.PedExperience, s.TotalExperience, s.WorkStart, s.Note, id,
-		)
-	} else {
-		res, err = r.db.Exec(ctx, `
-			UPDATE staff
-			SET full_name=$1, phone=$2, position=$3, subject=$4,
-			    education=$5, category=$6, ped_experience=$7,
-			    total_experience=$8, work_start=$9, note=$10
-			WHERE id=$11 AND school_id=$12`,
-			s.FullName, s.Phone, s.Position, s.Subject, s.Education, s.Category,
-			s.PedExperience, s.TotalExperience, s.WorkStart, s.Note, id, s.SchoolID,
-		)
-	}
+// Update обновляет сотрудника; schoolID != nil ограничивает обновление сотрудниками этой школы
+func (r *StaffRepository) Update(ctx context.Context, id int, s *models.Staff, schoolID *int) (int64, error) {
+	res, err := r.db.Exec(ctx, `
+		UPDATE staff
+		SET full_name=$1, phone=$2, position=$3, subject=$4,
+		    education=$5, category=$6, ped_experience=$7,
+		    total_experience=$8, work_start=$9, note=$10, birth_date=$13
+		WHERE id=$11 AND ($12::int IS NULL OR school_id=$12) AND deleted_at IS NULL`,
+		s.FullName, s.Phone, s.Position, s.Subject, s.Education, s.Category,
+		s.PedExperience, s.TotalExperience, s.WorkStart, s.Note, id, schoolID, s.BirthDate,
+	)
 	if err != nil {
 		return 0, err
 	}
@@ -170,7 +172,7 @@ func (r *StaffRepository) Update(ctx context.Context, id int, s *models.Staff, r
 func (r *StaffRepository) GetStats(ctx context.Context) (map[string]int, error) {
 	stats := make(map[string]int)
 	rows, err := r.db.Query(ctx, `
-		SELECT position, COUNT(*) FROM staff GROUP BY position
+		SELECT position, COUNT(*) FROM staff WHERE deleted_at IS NULL GROUP BY position
 	`)
 	if err != nil {
 		return nil, err
@@ -2,21 +2,39 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
 	"eduBase/internal/models"
-	"github.com/jackc/pgx/v5"
 )
 
 type StatsRepository struct {
-	db *pgx.Conn
+	db DBTX
 }
 
-func NewStatsRepository(db *pgx.Conn) *StatsRepository {
+func NewStatsRepository(db DBTX) *StatsRepository {
 	return &StatsRepository{db: db}
 }
 
-func (r *StatsRepository) DB() *pgx.Conn { return r.db }
+func (r *StatsRepository) DB() DBTX { return r.db }
+
+// teacherInYearSQL — условие «сотрудник st — учитель»: должность «учитель» или назначения
+// в учебном году year (SQL-выражение с id года)
+func teacherInYearSQL(year string) string {
+	return `(st.position ILIKE '%учител%' OR EXISTS (
+	SELECT 1 FROM teaching_assignments ta
+	JOIN classes c ON c.id = ta.class_id AND c.deleted_at IS NULL
+	WHERE ta.staff_id = st.id AND c.academic_year_id = ` + year + `))`
+}
 
-// GetSummary: если schoolID != nil — агрегаты по школе, иначе по всей системе
+// teacherSQL — учитель в текущем учебном году
+var teacherSQL = teacherInYearSQL(currentYearSQL)
+
+// GetSummary: если schoolID != nil — агрегаты по школе, иначе по всей системе.
+// Учителя считаются по teacherSQL, как в кадровой статистике.
 func (r *StatsRepository) GetSummary(ctx context.Context, schoolID *int) (*models.StatsSummary, error) {
 	var q string
 	var args []any
@@ -24,21 +42,21 @@ func (r *StatsRepository) GetSummary(ctx context.Context, schoolID *int) (*model
 	if schoolID == nil {
 		q = `
 		WITH
-		sch AS (SELECT COUNT(*)::int AS n FROM schools),
-		c AS (SELECT COUNT(*)::int AS n FROM classes),
-		stu AS (SELECT COUNT(*)::int AS n FROM students),
-		t AS (SELECT COUNT(*)::int AS n FROM staff WHERE position ILIKE '%учител%'),
-		st AS (SELECT COUNT(*)::int AS n FROM staff)
+		sch AS (SELECT COUNT(*)::int AS n FROM schools  WHERE deleted_at IS NULL),
+		c AS (SELECT COUNT(*)::int AS n FROM classes  WHERE deleted_at IS NULL AND academic_year_id = ` + currentYearSQL + `),
+		stu AS (SELECT COUNT(*)::int AS n FROM students WHERE deleted_at IS NULL AND status = 'active'),
+		t AS (SELECT COUNT(*)::int AS n FROM staff st WHERE st.deleted_at IS NULL AND ` + teacherSQL + `),
+		st AS (SELECT COUNT(*)::int AS n FROM staff    WHERE deleted_at IS NULL)
 		SELECT sch.n, c.n, stu.n, t.n, st.n FROM sch,c,stu,t,st;
 		`
 	} else {
 		q = `
 		WITH
-		sch AS (SELECT COUNT(*)::int AS n FROM schools WHERE id = $1),
-		c AS (SELECT COUNT(*)::int AS n FROM classes  WHERE school_id = $1),
-		stu AS (SELECT COUNT(*)::int AS n FROM students WHERE school_id = $1),
-		t AS (SELECT COUNT(*)::int AS n FROM staff    WHERE school_id = $1 AND position ILIKE '%учител%'),
-		st AS (SELECT COUNT(*)::int AS n FROM staff    WHERE school_id = $1)
+		sch AS (SELECT COUNT(*)::int AS n FROM schools  WHERE id = $1 AND deleted_at IS NULL),
+		c AS (SELECT COUNT(*)::int AS n FROM classes  WHERE school_id = $1 AND deleted_at IS NULL AND academic_year_id = ` + currentYearSQL + `),
+		stu AS (SELECT COUNT(*)::int AS n FROM students WHERE school_id = $1 AND deleted_at IS NULL AND status = 'active'),
+		t AS (SELECT COUNT(*)::int AS n FROM staff st WHERE st.school_id = $1 AND st.deleted_at IS NULL AND ` + teacherSQL + `),
+		st AS (SELECT COUNT(*)::int AS n FROM staff    WHERE school_id = $1 AND deleted_at IS NULL)
 		SELECT sch.n, c.n, stu.n, t.n, st.n FROM sch,c,stu,t,st;
 		`
 		args = append(args, *schoolID)
@@ -52,10 +70,362 @@ func (r *StatsRepository) GetSummary(ctx context.Context, schoolID *int) (*model
 	return &res, nil
 }
 
+// GetAttendance — посещаемость по школам и дням за период [from, to]; schoolID != nil — одна школа
+func (r *StatsRepository) GetAttendance(ctx context.Context, schoolID *int, from, to time.Time) ([]models.AttendanceDay, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT to_char(a.date, 'YYYY-MM-DD'), a.school_id, sc.name, sc.student_count,
+		       COUNT(*)::int,
+		       COUNT(*) FILTER (WHERE a.status = 'present')::int,
+		       COUNT(*) FILTER (WHERE a.status = 'late')::int,
+		       COUNT(*) FILTER (WHERE a.status = 'sick')::int,
+		       COUNT(*) FILTER (WHERE a.status = 'excused')::int,
+		       COUNT(*) FILTER (WHERE a.status = 'unexcused')::int
+		FROM attendance a
+		JOIN schools sc ON sc.id = a.school_id AND sc.deleted_at IS NULL
+		WHERE a.date BETWEEN $2 AND $3
+		  AND ($1::int IS NULL OR a.school_id = $1)
+		GROUP BY a.date, a.school_id, sc.name, sc.student_count
+		ORDER BY a.date, sc.name`, schoolID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []models.AttendanceDay{}
+	for rows.Next() {
+		var d models.AttendanceDay
+		if err := rows.Scan(
+			&d.Date, &d.SchoolID, &d.SchoolName, &d.Students, &d.Marked,
+			&d.Present, &d.Late, &d.Sick, &d.Excused, &d.Unexcused,
+		); err != nil {
+			return nil, err
+		}
+		if d.Marked > 0 {
+			d.Rate = math.Round(float64(d.Present+d.Late)*1000/float64(d.Marked)) / 10
+		}
+		list = append(list, d)
+	}
+	return list, rows.Err()
+}
+
+// GetTeacherHours — недельные часы учителей по назначениям текущего года.
+// Учителем считается сотрудник с должностью «учитель» или с назначениями; schoolID != nil — одна школа.
+func (r *StatsRepository) GetTeacherHours(ctx context.Context, schoolID *int) ([]models.TeacherLoad, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT st.id, st.full_name, st.position, st.school_id, sc.name,
+		       COALESCE(SUM(a.hours_per_week), 0)::float8
+		FROM staff st
+		JOIN schools sc ON sc.id = st.school_id AND sc.deleted_at IS NULL
+		LEFT JOIN (
+			SELECT ta.staff_id, ta.hours_per_week
+			FROM teaching_assignments ta
+			JOIN classes c ON c.id = ta.class_id AND c.deleted_at IS NULL
+			WHERE c.academic_year_id = `+currentYearSQL+`
+		) a ON a.staff_id = st.id
+		WHERE st.deleted_at IS NULL
+		  AND ($1::int IS NULL OR st.school_id = $1)
+		GROUP BY st.id, st.full_name, st.position, st.school_id, sc.name
+		HAVING st.position ILIKE '%учител%' OR COUNT(a.staff_id) > 0
+		ORDER BY sc.name, st.full_name, st.id`, schoolID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []models.TeacherLoad{}
+	for rows.Next() {
+		var t models.TeacherLoad
+		if err := rows.Scan(&t.StaffID, &t.FullName, &t.Position, &t.SchoolID, &t.SchoolName, &t.Hours); err != nil {
+			return nil, err
+		}
+		list = append(list, t)
+	}
+	return list, rows.Err()
+}
+
+// GetVacancies — часы текущего года без учителя по школам и предметам; schoolID != nil — одна школа
+func (r *StatsRepository) GetVacancies(ctx context.Context, schoolID *int) ([]models.SubjectVacancy, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT ta.school_id, sc.name, ta.subject_id, sb.name,
+		       COUNT(DISTINCT ta.class_id)::int, SUM(ta.hours_per_week)::float8
+		FROM teaching_assignments ta
+		JOIN classes c ON c.id = ta.class_id AND c.deleted_at IS NULL
+		JOIN schools sc ON sc.id = ta.school_id AND sc.deleted_at IS NULL
+		JOIN subjects sb ON sb.id = ta.subject_id
+		LEFT JOIN staff st ON st.id = ta.staff_id AND st.deleted_at IS NULL
+		WHERE st.id IS NULL
+		  AND c.academic_year_id = `+currentYearSQL+`
+		  AND ($1::int IS NULL OR ta.school_id = $1)
+		GROUP BY ta.school_id, sc.name, ta.subject_id, sb.name
+		ORDER BY sc.name, sb.name`, schoolID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []models.SubjectVacancy{}
+	for rows.Next() {
+		var v models.SubjectVacancy
+		if err := rows.Scan(&v.SchoolID, &v.SchoolName, &v.SubjectID, &v.SubjectName, &v.Classes, &v.Hours); err != nil {
+			return nil, err
+		}
+		list = append(list, v)
+	}
+	return list, rows.Err()
+}
+
+// GetPerformance — успеваемость по классам по итоговым отметкам периода учебного года;
+// ученик оценивается по худшей итоговой отметке. schoolID != nil — одна школа.
+func (r *StatsRepository) GetPerformance(ctx context.Context, schoolID *int, yearID int, period string) ([]models.PerformanceRow, error) {
+	rows, err := r.db.Query(ctx, `
+		WITH st AS (
+			SELECT t.student_id, t.class_id, MIN(t.mark) AS worst
+			FROM term_marks t
+			JOIN students s ON s.id = t.student_id AND s.deleted_at IS NULL
+			WHERE t.academic_year_id = $2 AND t.period = $3
+			  AND ($1::int IS NULL OR t.school_id = $1)
+			GROUP BY t.student_id, t.class_id
+		)
+		SELECT c.school_id, sc.name, c.id, c.name,
+		       COUNT(*)::int,
+		       COUNT(*) FILTER (WHERE st.worst = 5)::int,
+		       COUNT(*) FILTER (WHERE st.worst >= 4)::int,
+		       COUNT(*) FILTER (WHERE st.worst >= 3)::int
+		FROM st
+		JOIN classes c ON c.id = st.class_id AND c.deleted_at IS NULL
+		JOIN schools sc ON sc.id = c.school_id AND sc.deleted_at IS NULL
+		GROUP BY c.school_id, sc.name, c.id, c.name, c.grade
+		ORDER BY sc.name, c.school_id, c.grade, c.name`, schoolID, yearID, period)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []models.PerformanceRow{}
+	for rows.Next() {
+		var p models.PerformanceRow
+		var sID, cID int
+		if err := rows.Scan(&sID, &p.SchoolName, &cID, &p.ClassName, &p.Students, &p.Excellent, &p.Good, &p.Passed); err != nil {
+			return nil, err
+		}
+		p.SchoolID, p.ClassID = &sID, &cID
+		list = append(list, p)
+	}
+	return list, rows.Err()
+}
+
+// GetStudentGroups — ученики учебного года по школам, классам, полу и возрасту (полных лет на дату).
+// Для текущего года — только обучающиеся, для прошлых — все зачисленные в тот год.
+// schoolID, grade != nil — одна школа, одна параллель.
+func (r *StatsRepository) GetStudentGroups(ctx context.Context, schoolID, grade *int, yearID int, date time.Time) ([]models.StudentGroup, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT e.school_id, sc.name, e.class_id, c.grade, s.gender,
+		       date_part('year', age($4::date, s.birth_date))::int,
+		       COUNT(*)::int
+		FROM student_enrollments e
+		JOIN students s ON s.id = e.student_id AND s.deleted_at IS NULL
+		JOIN classes c ON c.id = e.class_id AND c.deleted_at IS NULL
+		JOIN schools sc ON sc.id = e.school_id AND sc.deleted_at IS NULL
+		WHERE e.academic_year_id = $3
+		  AND (e.academic_year_id IS DISTINCT FROM `+currentYearSQL+` OR s.status = 'active')
+		  AND ($1::int IS NULL OR e.school_id = $1)
+		  AND ($2::int IS NULL OR c.grade = $2)
+		GROUP BY 1, 2, 3, 4, 5, 6
+		ORDER BY sc.name, e.school_id`, schoolID, grade, yearID, date)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []models.StudentGroup{}
+	for rows.Next() {
+		var g models.StudentGroup
+		if err := rows.Scan(&g.SchoolID, &g.SchoolName, &g.ClassID, &g.Grade, &g.Gender, &g.Age, &g.Count); err != nil {
+			return nil, err
+		}
+		list = append(list, g)
+	}
+	return list, rows.Err()
+}
+
+// GetStaffGroups — сотрудники по школам, образованию, категории, педагогическому стажу и возрасту (полных лет на сегодня).
+// Учителем считается сотрудник с должностью «учитель» или с назначениями текущего года; schoolID != nil — одна школа.
+func (r *StatsRepository) GetStaffGroups(ctx context.Context, schoolID *int) ([]models.StaffGroup, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT st.school_id, sc.name,
+		       `+teacherSQL+`,
+		       COALESCE(TRIM(st.education), ''), COALESCE(TRIM(st.category), ''), st.ped_experience,
+		       date_part('year', age(CURRENT_DATE, st.birth_date))::int,
+		       COUNT(*)::int
+		FROM staff st
+		JOIN schools sc ON sc.id = st.school_id AND sc.deleted_at IS NULL
+		WHERE st.deleted_at IS NULL
+		  AND ($1::int IS NULL OR st.school_id = $1)
+		GROUP BY 1, 2, 3, 4, 5, 6, 7
+		ORDER BY sc.name, st.school_id`, schoolID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []models.StaffGroup{}
+	for rows.Next() {
+		var g models.StaffGroup
+		if err := rows.Scan(&g.SchoolID, &g.SchoolName, &g.Teacher, &g.Education, &g.Category, &g.PedExperience, &g.Age, &g.Count); err != nil {
+			return nil, err
+		}
+		list = append(list, g)
+	}
+	return list, rows.Err()
+}
+
+// GetOO1Classes — число классов учебного года по параллелям на дату; schoolID != nil — одна школа
+func (r *StatsRepository) GetOO1Classes(ctx context.Context, schoolID *int, yearID int, date time.Time) (map[int]int, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT c.grade, COUNT(*)::int
+		FROM classes c
+		JOIN schools sc ON sc.id = c.school_id AND sc.deleted_at IS NULL
+		WHERE c.academic_year_id = $2 AND c.created_at < $3::date + 1
+		  AND (c.deleted_at IS NULL OR c.deleted_at >= $3::date + 1)
+		  AND ($1::int IS NULL OR c.school_id = $1)
+		GROUP BY c.grade`, schoolID, yearID, date)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := map[int]int{}
+	for rows.Next() {
+		var grade, n int
+		if err := rows.Scan(&grade, &n); err != nil {
+			return nil, err
+		}
+		res[grade] = n
+	}
+	return res, rows.Err()
+}
+
+// GetOO1Students — обучающиеся на дату по параллели, полу и возрасту на ageDate (1 января учебного года).
+// Школа и класс — по последней записи книги движения ученика на эту дату: ученик учитывается,
+// если это прибытие или смена класса (выбытие, перевод в другую школу, выпуск и удаление — выбытие).
+// schoolID != nil — одна школа.
+func (r *StatsRepository) GetOO1Students(ctx context.Context, schoolID *int, date, ageDate time.Time) ([]models.OO1StudentGroup, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT c.grade, s.gender,
+		       date_part('year', age($3::date, s.birth_date))::int,
+		       COUNT(*)::int
+		FROM (
+			SELECT DISTINCT ON (m.student_id) m.student_id, m.school_id, m.kind, m.to_class_id
+			FROM student_movements m
+			WHERE m.created_at < $2::date + 1
+			ORDER BY m.student_id, m.created_at DESC, m.id DESC
+		) last
+		JOIN students s ON s.id = last.student_id AND (s.deleted_at IS NULL OR s.deleted_at >= $2::date + 1)
+		JOIN classes c ON c.id = last.to_class_id
+		JOIN schools sc ON sc.id = last.school_id AND sc.deleted_at IS NULL
+		WHERE last.kind IN ('arrival', 'class_change') AND ($1::int IS NULL OR last.school_id = $1)
+		GROUP BY 1, 2, 3`, schoolID, date, ageDate)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []models.OO1StudentGroup{}
+	for rows.Next() {
+		var g models.OO1StudentGroup
+		if err := rows.Scan(&g.Grade, &g.Gender, &g.Age, &g.Count); err != nil {
+			return nil, err
+		}
+		list = append(list, g)
+	}
+	return list, rows.Err()
+}
+
+// GetOO1Staff — работники на дату (приняты не позже даты) по образованию, категории, педагогическому стажу
+// и признаку учителя (как в кадровой статистике, с назначениями учебного года yearID); schoolID != nil — одна школа
+func (r *StatsRepository) GetOO1Staff(ctx context.Context, schoolID *int, yearID int, date time.Time) ([]models.OO1StaffGroup, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT COALESCE(TRIM(st.education), ''), COALESCE(TRIM(st.category), ''), st.ped_experience,
+		       `+teacherInYearSQL("$3")+`, COUNT(*)::int
+		FROM staff st
+		JOIN schools sc ON sc.id = st.school_id AND sc.deleted_at IS NULL
+		WHERE ($1::int IS NULL OR st.school_id = $1)
+		  AND (st.deleted_at IS NULL OR st.deleted_at >= $2::date + 1)
+		  AND (st.work_start IS NULL OR st.work_start <= $2::date)
+		GROUP BY 1, 2, 3, 4`, schoolID, date, yearID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []models.OO1StaffGroup{}
+	for rows.Next() {
+		var g models.OO1StaffGroup
+		if err := rows.Scan(&g.Education, &g.Category, &g.PedExperience, &g.Teacher, &g.Count); err != nil {
+			return nil, err
+		}
+		list = append(list, g)
+	}
+	return list, rows.Err()
+}
+
+// SnapshotMetrics — показатели сводной статистики, которые хранятся в снимках (названия колонок stats_snapshots)
+var SnapshotMetrics = []string{"schools", "classes", "students", "teachers", "staff_total"}
+
+var ErrUnknownMetric = errors.New("metric must be one of: " + strings.Join(SnapshotMetrics, ", "))
+
+// SaveSnapshot сохраняет снимок сводной статистики sum на дату: schoolID == nil — района, иначе школы;
+// повторный снимок за ту же дату заменяет прежний
+func (r *StatsRepository) SaveSnapshot(ctx context.Context, date time.Time, schoolID *int, sum *models.StatsSummary) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO stats_snapshots (date, school_id, schools, classes, students, teachers, staff_total)
+		VALUES ($1::date, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (date, (COALESCE(school_id, 0))) DO UPDATE
+		SET schools=EXCLUDED.schools, classes=EXCLUDED.classes, students=EXCLUDED.students,
+		    teachers=EXCLUDED.teachers, staff_total=EXCLUDED.staff_total, created_at=NOW()`,
+		date, schoolID, sum.Schools, sum.Classes, sum.Students, sum.Teachers, sum.StaffTotal,
+	)
+	return err
+}
+
+// LastSnapshotDate — дата последнего снимка района; nil — снимков ещё нет
+func (r *StatsRepository) LastSnapshotDate(ctx context.Context) (*time.Time, error) {
+	var d *time.Time
+	err := r.db.QueryRow(ctx, `SELECT MAX(date) FROM stats_snapshots WHERE school_id IS NULL`).Scan(&d)
+	return d, err
+}
+
+// GetHistory — значения показателя metric (из SnapshotMetrics) по снимкам за [from, to];
+// schoolID != nil — снимки школы, иначе района
+func (r *StatsRepository) GetHistory(ctx context.Context, metric string, schoolID *int, from, to time.Time) ([]models.StatsPoint, error) {
+	if !slices.Contains(SnapshotMetrics, metric) {
+		return nil, ErrUnknownMetric
+	}
+	rows, err := r.db.Query(ctx, `
+		SELECT to_char(date, 'YYYY-MM-DD'), `+metric+`
+		FROM stats_snapshots
+		WHERE school_id IS NOT DISTINCT FROM $1::int AND date BETWEEN $2::date AND $3::date
+		ORDER BY date`, schoolID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []models.StatsPoint{}
+	for rows.Next() {
+		var p models.StatsPoint
+		if err := rows.Scan(&p.Date, &p.Value); err != nil {
+			return nil, err
+		}
+		list = append(list, p)
+	}
+	return list, rows.Err()
+}
+
 // Дополнительно: быстрая проверка существования школы (для валидации school_id у ROO)
 func (r *StatsRepository) SchoolExists(ctx context.Context, id int) (bool, error) {
 	var ok bool
-	if err := r.db.QueryRow(ctx, `SELECT EXISTS(SELECT 1 FROM schools WHERE id=$1)`, id).Scan(&ok); err != nil {
+	if err := r.db.QueryRow(ctx, `SELECT EXISTS(SELECT 1 FROM schools WHERE id=$1 AND deleted_at IS NULL)`, id).Scan(&ok); err != nil {
 		return false, err
 	}
 	return ok, nil
@@ -8,20 +8,21 @@ import (
 
 	"eduBase/internal/models"
 	"github.com/jackc/pgx/v5"
-	"github.com/jackc/pgx/v5/pgconn"
 )
 
 type StudentFilter struct {
-	FullName string
-	Gender   string
-	ClassID  *int
+	FullName string `json:"full_name,omitempty"`
+	Gender   string `json:"gender,omitempty"`
+	ClassID  *int   `json:"class_id,omitempty"`
+	// учебный год; nil — текущий
+	AcademicYearID *int `json:"academic_year_id,omitempty"`
 }
 
 type StudentRepository struct {
-	db *pgx.Conn
+	db DBTX
 }
 
-func NewStudentRepository(db *pgx.Conn) *StudentRepository {
+func NewStudentRepository(db DBTX) *StudentRepository {
 	return &StudentRepository{db: db}
 }
 
@@ -40,18 +41,21 @@ func (r *StudentRepository) Create(ctx context.Context, s *models.Student) error
 }
 
 // ===== GET ALL (with class name) =====
+// Выборка идёт по зачислениям учебного года: для архивного года ученик
+// показывается в том классе и той школе, где учился тогда.
 func (r *StudentRepository) GetAll(ctx context.Context, schoolID *int, f StudentFilter) ([]models.Student, error) {
 	base := `
 	SELECT s.id, s.full_name, s.birth_date, s.gender, s.phone, s.address, s.note,
-	       s.class_id, c.name AS class_name, s.school_id, s.created_at
-	FROM students s
-	JOIN classes c ON c.id = s.class_id`
-	var where []string
-	var args []any
-	i := 1
+	       e.class_id, c.name AS class_name, e.school_id, s.status, s.created_at
+	FROM student_enrollments e
+	JOIN students s ON s.id = e.student_id
+	JOIN classes c ON c.id = e.class_id`
+	where := []string{"s.deleted_at IS NULL", "e.academic_year_id=COALESCE($1::int, " + currentYearSQL + ")"}
+	args := []any{f.AcademicYearID}
+	i := 2
 
 	if schoolID != nil {
-		where = append(where, fmt.Sprintf("s.school_id=$%d", i))
+		where = append(where, fmt.Sprintf("e.school_id=$%d", i))
 		args = append(args, *schoolID)
 		i++
 	}
@@ -66,7 +70,7 @@ func (r *StudentRepository) GetAll(ctx context.Context, schoolID *int, f Student
 		i++
 	}
 	if f.ClassID != nil {
-		where = append(where, fmt.Sprintf("s.class_id=$%d", i))
+		where = append(where, fmt.Sprintf("e.class_id=$%d", i))
 		args = append(args, *f.ClassID)
 		i++
 	}
@@ -89,7 +93,7 @@ func (r *StudentRepository) GetAll(ctx context.Context, schoolID *int, f Student
 		if err := rows.Scan(
 			&s.ID, &s.FullName, &s.BirthDate, &s.Gender,
 			&s.Phone, &s.Address, &s.Note,
-			&s.ClassID, &s.ClassName, &s.SchoolID, &s.CreatedAt,
+			&s.ClassID, &s.ClassName, &s.SchoolID, &s.Status, &s.CreatedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -102,16 +106,16 @@ func (r *StudentRepository) GetAll(ctx context.Context, schoolID *int, f Student
 func (r *StudentRepository) GetByID(ctx context.Context, id int) (*models.Student, error) {
 	row := r.db.QueryRow(ctx, `
 		SELECT s.id, s.full_name, s.birth_date, s.gender, s.phone, s.address, s.note,
-		       s.class_id, c.name AS class_name, s.school_id, s.created_at
+		       s.class_id, c.name AS class_name, s.school_id, s.status, s.created_at
 		FROM students s
 		JOIN classes c ON c.id = s.class_id
-		WHERE s.id=$1`, id)
+		WHERE s.id=$1 AND s.deleted_at IS NULL`, id)
 
 	var s models.Student
 	if err := row.Scan(
 		&s.ID, &s.FullName, &s.BirthDate, &s.Gender,
 		&s.Phone, &s.Address, &s.Note,
-		&s.ClassID, &s.ClassName, &s.SchoolID, &s.CreatedAt,
+		&s.ClassID, &s.ClassName, &s.SchoolID, &s.Status, &s.CreatedAt,
 	); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, ErrStudentNotFound
@@ -122,45 +126,124 @@ func (r *StudentRepository) GetByID(ctx context.Context, id int) (*models.Studen
 }
 
 // ===== UPDATE =====
-func (r *StudentRepository) Update(ctx context.Context, id int, s *models.Student, role string) (int64, error) {
-	var res pgconn.CommandTag
-	var err error
-	if role == "roo" {
-		res, err = r.db.Exec(ctx, `
-			UPDATE students
-			SET full_name=$1, birth_date=$2, gender=$3, phone=$4, address=$5, note=$6, class_id=$7
-			WHERE id=$8`,
-			s.FullName, s.BirthDate, s.Gender, s.Phone, s.Address, s.Note, s.ClassID, id)
-	} else {
-		res, err = r.db.Exec(ctx, `
-			UPDATE students
-			SET full_name=$1, birth_date=$2, gender=$3, phone=$4, address=$5, note=$6, class_id=$7
-			WHERE id=$8 AND school_id=$9`,
-			s.FullName, s.BirthDate, s.Gender, s.Phone, s.Address, s.Note, s.ClassID, id, s.SchoolID)
-	}
+// schoolID != nil ограничивает обновление учениками этой школы
+func (r *StudentRepository) Update(ctx context.Context, id int, s *models.Student, schoolID *int) (int64, error) {
+	res, err := r.db.Exec(ctx, `
+		UPDATE students
+		SET full_name=$1, birth_date=$2, gender=$3, phone=$4, address=$5, note=$6, class_id=$7
+		WHERE id=$8 AND ($9::int IS NULL OR school_id=$9) AND deleted_at IS NULL`,
+		s.FullName, s.BirthDate, s.Gender, s.Phone, s.Address, s.Note, s.ClassID, id, schoolID)
 	if err != nil {
 		return 0, err
 	}
 	return res.RowsAffected(), nil
 }
 
-// ===== DELETE =====
-func (r *StudentRepository) Delete(ctx context.Context, id int, schoolID int) error {
-	_, err := r.db.Exec(ctx, `DELETE FROM students WHERE id=$1 AND school_id=$2`, id, schoolID)
+// ===== SOFT DELETE =====
+// ученик переносится в корзину и исключается из всех выборок
+func (r *StudentRepository) SoftDelete(ctx context.Context, id int, schoolID int, deletedBy *int) error {
+	_, err := r.db.Exec(ctx, `
+		UPDATE students SET deleted_at=NOW(), deleted_by=$3
+		WHERE id=$1 AND school_id=$2 AND deleted_at IS NULL`, id, schoolID, deletedBy)
 	return err
 }
 
+// collectPlaces читает строки (id, school_id, class_id, status) — учеников, затронутых
+// каскадным удалением или восстановлением
+func collectPlaces(rows pgx.Rows, err error) ([]models.Student, error) {
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []models.Student
+	for rows.Next() {
+		var s models.Student
+		if err := rows.Scan(&s.ID, &s.SchoolID, &s.ClassID, &s.Status); err != nil {
+			return nil, err
+		}
+		list = append(list, s)
+	}
+	return list, rows.Err()
+}
+
+// ===== MOVE / STATUS =====
+// SetClass переводит ученика в другой класс без проверок (перевод на следующий год)
+func (r *StudentRepository) SetClass(ctx context.Context, id, classID int) error {
+	_, err := r.db.Exec(ctx, `UPDATE students SET class_id=$2 WHERE id=$1`, id, classID)
+	return err
+}
+
+// SetSchool переводит ученика в класс другой школы (принятая заявка на перевод)
+func (r *StudentRepository) SetSchool(ctx context.Context, id, schoolID, classID int) error {
+	_, err := r.db.Exec(ctx, `UPDATE students SET school_id=$2, class_id=$3 WHERE id=$1`, id, schoolID, classID)
+	return err
+}
+
+// SetStatus меняет статус ученика (окончил, выбыл, снова учится)
+func (r *StudentRepository) SetStatus(ctx context.Context, id int, status string) error {
+	_, err := r.db.Exec(ctx, `
+		UPDATE students SET status=$2, status_changed_at=NOW()
+		WHERE id=$1`, id, status)
+	return err
+}
+
+// ListByClass — обучающиеся (active) ученики класса
+func (r *StudentRepository) ListByClass(ctx context.Context, classID int) ([]models.Student, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT s.id, s.full_name, s.class_id, c.name, s.school_id, s.status, s.created_at
+		FROM students s
+		JOIN classes c ON c.id = s.class_id
+		WHERE s.class_id=$1 AND s.deleted_at IS NULL AND s.status='active'
+		ORDER BY s.full_name, s.id`, classID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []models.Student
+	for rows.Next() {
+		var s models.Student
+		if err := rows.Scan(&s.ID, &s.FullName, &s.ClassID, &s.ClassName, &s.SchoolID, &s.Status, &s.CreatedAt); err != nil {
+			return nil, err
+		}
+		list = append(list, s)
+	}
+	return list, rows.Err()
+}
+
+// ListNames — ФИО и даты рождения обучающихся школы (для поиска дублей при загрузке)
+func (r *StudentRepository) ListNames(ctx context.Context, schoolID int) ([]models.Student, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT full_name, birth_date FROM students
+		WHERE school_id=$1 AND deleted_at IS NULL AND status='active'`, schoolID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []models.Student
+	for rows.Next() {
+		var s models.Student
+		if err := rows.Scan(&s.FullName, &s.BirthDate); err != nil {
+			return nil, err
+		}
+		list = append(list, s)
+	}
+	return list, rows.Err()
+}
+
 // ===== COUNT BY CLASS =====
 func (r *StudentRepository) CountByClass(ctx context.Context, classID int) (int, error) {
 	var count int
-	err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM students WHERE class_id=$1`, classID).Scan(&count)
+	err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM students WHERE class_id=$1 AND deleted_at IS NULL AND status='active'`, classID).Scan(&count)
 	return count, err
 }
 
 // ===== STATS =====
 func (r *StudentRepository) GetStats(ctx context.Context) (map[string]int, error) {
 	stats := make(map[string]int)
-	rows, err := r.db.Query(ctx, `SELECT gender, COUNT(*) FROM students GROUP BY gender`)
+	rows, err := r.db.Query(ctx, `SELECT gender, COUNT(*) FROM students WHERE deleted_at IS NULL AND status='active' GROUP BY gender`)
 	if err != nil {
 		return nil, err
 	}
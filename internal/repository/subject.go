@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"eduBase/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	ErrSubjectNotFound = errors.New("subject not found")
+	ErrSubjectExists   = errors.New("subject with this name already exists")
+	ErrSubjectInUse    = errors.New("subject is in use and cannot be deleted")
+)
+
+type SubjectRepository struct {
+	db DBTX
+}
+
+func NewSubjectRepository(db DBTX) *SubjectRepository {
+	return &SubjectRepository{db: db}
+}
+
+func (r *SubjectRepository) DB() DBTX { return r.db }
+
+func (r *SubjectRepository) Create(ctx context.Context, s *models.Subject) error {
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO subjects (name, short_name) VALUES ($1,$2)
+		RETURNING id, created_at`, s.Name, s.ShortName,
+	).Scan(&s.ID, &s.CreatedAt)
+	if isUniqueViolation(err) {
+		return ErrSubjectExists
+	}
+	return err
+}
+
+func (r *SubjectRepository) GetAll(ctx context.Context) ([]models.Subject, error) {
+	rows, err := r.db.Query(ctx, `SELECT id, name, short_name, created_at FROM subjects ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []models.Subject{}
+	for rows.Next() {
+		var s models.Subject
+		if err := rows.Scan(&s.ID, &s.Name, &s.ShortName, &s.CreatedAt); err != nil {
+			return nil, err
+		}
+		list = append(list, s)
+	}
+	return list, rows.Err()
+}
+
+func (r *SubjectRepository) GetByID(ctx context.Context, id int) (*models.Subject, error) {
+	var s models.Subject
+	err := r.db.QueryRow(ctx, `SELECT id, name, short_name, created_at FROM subjects WHERE id=$1`, id).
+		Scan(&s.ID, &s.Name, &s.ShortName, &s.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrSubjectNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (r *SubjectRepository) Update(ctx context.Context, id int, s *models.Subject) error {
+	res, err := r.db.Exec(ctx, `UPDATE subjects SET name=$2, short_name=$3 WHERE id=$1`, id, s.Name, s.ShortName)
+	if isUniqueViolation(err) {
+		return ErrSubjectExists
+	}
+	if err != nil {
+		return err
+	}
+	if res.RowsAffected() == 0 {
+		return ErrSubjectNotFound
+	}
+	return nil
+}
+
+// Delete удаляет предмет; предмет, на который есть назначения, удалить нельзя
+func (r *SubjectRepository) Delete(ctx context.Context, id int) error {
+	res, err := r.db.Exec(ctx, `DELETE FROM subjects WHERE id=$1`, id)
+	if isForeignKeyViolation(err) {
+		return ErrSubjectInUse
+	}
+	if err != nil {
+		return err
+	}
+	if res.RowsAffected() == 0 {
+		return ErrSubjectNotFound
+	}
+	return nil
+}
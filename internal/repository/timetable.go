@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"eduBase/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	ErrTimetableEntryNotFound = errors.New("timetable entry not found")
+	ErrTimetableSlotTaken     = errors.New("class already has a lesson at this time")
+)
+
+type TimetableRepository struct {
+	db DBTX
+}
+
+func NewTimetableRepository(db DBTX) *TimetableRepository {
+	return &TimetableRepository{db: db}
+}
+
+func (r *TimetableRepository) DB() DBTX { return r.db }
+
+// уроки удалённых классов в расписание не попадают; уволенный учитель — как не назначенный
+const timetableSelect = `
+	SELECT t.id, t.school_id, t.class_id, c.name, c.academic_year_id, t.weekday, t.lesson,
+	       t.subject_id, sb.name, st.id, st.full_name, t.room, t.created_at
+	FROM timetable_entries t
+	JOIN classes c ON c.id = t.class_id AND c.deleted_at IS NULL
+	JOIN subjects sb ON sb.id = t.subject_id
+	LEFT JOIN staff st ON st.id = t.staff_id AND st.deleted_at IS NULL`
+
+func scanTimetableEntry(row pgx.Row) (*models.TimetableEntry, error) {
+	var e models.TimetableEntry
+	if err := row.Scan(
+		&e.ID, &e.SchoolID, &e.ClassID, &e.ClassName, &e.AcademicYearID, &e.Weekday, &e.Lesson,
+		&e.SubjectID, &e.SubjectName, &e.StaffID, &e.StaffName, &e.Room, &e.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+func (r *TimetableRepository) query(ctx context.Context, sql string, args ...any) ([]models.TimetableEntry, error) {
+	rows, err := r.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []models.TimetableEntry{}
+	for rows.Next() {
+		e, err := scanTimetableEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, *e)
+	}
+	return list, rows.Err()
+}
+
+func (r *TimetableRepository) GetByID(ctx context.Context, id int) (*models.TimetableEntry, error) {
+	e, err := scanTimetableEntry(r.db.QueryRow(ctx, timetableSelect+` WHERE t.id=$1`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrTimetableEntryNotFound
+	}
+	return e, err
+}
+
+// GetByClass — расписание класса на неделю
+func (r *TimetableRepository) GetByClass(ctx context.Context, classID int) ([]models.TimetableEntry, error) {
+	return r.query(ctx, timetableSelect+`
+		WHERE t.class_id=$1
+		ORDER BY t.weekday, t.lesson`, classID)
+}
+
+// GetByStaff — уроки учителя за учебный год; yearID == nil — текущий
+func (r *TimetableRepository) GetByStaff(ctx context.Context, staffID int, yearID *int) ([]models.TimetableEntry, error) {
+	return r.query(ctx, timetableSelect+`
+		WHERE t.staff_id=$1 AND c.academic_year_id=COALESCE($2::int, `+currentYearSQL+`)
+		ORDER BY t.weekday, t.lesson, c.name`, staffID, yearID)
+}
+
+// Conflicts — уроки того же учебного года в то же время, занимающие класс, учителя или кабинет записи e.
+// excludeID — сама изменяемая запись.
+func (r *TimetableRepository) Conflicts(ctx context.Context, e *models.TimetableEntry, yearID, excludeID int) ([]models.TimetableEntry, error) {
+	return r.query(ctx, timetableSelect+`
+		WHERE c.academic_year_id=$1 AND t.weekday=$2 AND t.lesson=$3 AND t.id<>$4
+		  AND (t.class_id=$5
+		       OR (st.id IS NOT NULL AND st.id=$6)
+		       OR (t.school_id=$7 AND LOWER(t.room)=LOWER($8)))
+		ORDER BY t.id`,
+		yearID, e.Weekday, e.Lesson, excludeID, e.ClassID, e.StaffID, e.SchoolID, e.Room)
+}
+
+// LockSchool блокирует школу до конца транзакции, чтобы параллельные правки
+// расписания одной школы не обошли проверку занятости
+func (r *TimetableRepository) LockSchool(ctx context.Context, schoolID int) error {
+	var id int
+	return r.db.QueryRow(ctx, `SELECT id FROM schools WHERE id=$1 FOR UPDATE`, schoolID).Scan(&id)
+}
+
+func (r *TimetableRepository) Create(ctx context.Context, e *models.TimetableEntry) error {
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO timetable_entries (school_id, class_id, weekday, lesson, subject_id, staff_id, room)
+		VALUES ($1,$2,$3,$4,$5,$6,$7)
+		RETURNING id, created_at`,
+		e.SchoolID, e.ClassID, e.Weekday, e.Lesson, e.SubjectID, e.StaffID, e.Room,
+	).Scan(&e.ID, &e.CreatedAt)
+	if isUniqueViolation(err) {
+		return ErrTimetableSlotTaken
+	}
+	return err
+}
+
+// Update меняет время, предмет, учителя и кабинет урока; класс не меняется
+func (r *TimetableRepository) Update(ctx context.Context, id int, e *models.TimetableEntry) error {
+	_, err := r.db.Exec(ctx, `
+		UPDATE timetable_entries
+		SET weekday=$2, lesson=$3, subject_id=$4, staff_id=$5, room=$6
+		WHERE id=$1`,
+		id, e.Weekday, e.Lesson, e.SubjectID, e.StaffID, e.Room)
+	if isUniqueViolation(err) {
+		return ErrTimetableSlotTaken
+	}
+	return err
+}
+
+func (r *TimetableRepository) Delete(ctx context.Context, id int) error {
+	_, err := r.db.Exec(ctx, `DELETE FROM timetable_entries WHERE id=$1`, id)
+	return err
+}
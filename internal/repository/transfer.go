@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"eduBase/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	ErrTransferNotFound = errors.New("transfer not found")
+	ErrTransferPending  = errors.New("student already has a pending transfer")
+)
+
+// Направление заявок относительно школы
+const (
+	TransferIncoming = "incoming"
+	TransferOutgoing = "outgoing"
+)
+
+// TransferFilter — SchoolID != nil ограничивает заявками, где школа отправляет или принимает
+type TransferFilter struct {
+	SchoolID  *int
+	Direction string // incoming | outgoing; пусто — оба направления
+	Status    string
+	StudentID *int
+}
+
+type TransferRepository struct {
+	db DBTX
+}
+
+func NewTransferRepository(db DBTX) *TransferRepository {
+	return &TransferRepository{db: db}
+}
+
+func (r *TransferRepository) DB() DBTX { return r.db }
+
+const transferSelect = `
+	SELECT t.id, t.student_id, st.full_name, t.from_school_id, fs.name, t.from_class_id,
+	       t.to_school_id, ts.name, t.to_class_id, t.status, t.reason, t.decision_note,
+	       t.created_by, t.decided_by, t.created_at, t.decided_at
+	FROM student_transfers t
+	JOIN students st ON st.id = t.student_id
+	JOIN schools fs ON fs.id = t.from_school_id
+	JOIN schools ts ON ts.id = t.to_school_id`
+
+func scanTransfer(row pgx.Row) (*models.StudentTransfer, error) {
+	var t models.StudentTransfer
+	if err := row.Scan(
+		&t.ID, &t.StudentID, &t.StudentName, &t.FromSchoolID, &t.FromSchool, &t.FromClassID,
+		&t.ToSchoolID, &t.ToSchool, &t.ToClassID, &t.Status, &t.Reason, &t.DecisionNote,
+		&t.CreatedBy, &t.DecidedBy, &t.CreatedAt, &t.DecidedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// Create заводит заявку со статусом pending
+func (r *TransferRepository) Create(ctx context.Context, t *models.StudentTransfer) error {
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO student_transfers (student_id, from_school_id, from_class_id, to_school_id, reason, created_by)
+		VALUES ($1,$2,$3,$4,$5,$6)
+		RETURNING id, status, created_at`,
+		t.StudentID, t.FromSchoolID, t.FromClassID, t.ToSchoolID, t.Reason, t.CreatedBy,
+	).Scan(&t.ID, &t.Status, &t.CreatedAt)
+	if isUniqueViolation(err) {
+		return ErrTransferPending
+	}
+	return err
+}
+
+func (r *TransferRepository) GetByID(ctx context.Context, id int) (*models.StudentTransfer, error) {
+	t, err := scanTransfer(r.db.QueryRow(ctx, transferSelect+` WHERE t.id=$1`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrTransferNotFound
+	}
+	return t, err
+}
+
+func (r *TransferRepository) GetAll(ctx context.Context, f TransferFilter) ([]models.StudentTransfer, error) {
+	where := []string{"st.deleted_at IS NULL"}
+	var args []any
+	i := 1
+
+	if f.SchoolID != nil {
+		switch f.Direction {
+		case TransferIncoming:
+			where = append(where, fmt.Sprintf("t.to_school_id=$%d", i))
+		case TransferOutgoing:
+			where = append(where, fmt.Sprintf("t.from_school_id=$%d", i))
+		default:
+			where = append(where, fmt.Sprintf("(t.from_school_id=$%d OR t.to_school_id=$%d)", i, i))
+		}
+		args = append(args, *f.SchoolID)
+		i++
+	}
+	if f.Status != "" {
+		where = append(where, fmt.Sprintf("t.status=$%d", i))
+		args = append(args, f.Status)
+		i++
+	}
+	if f.StudentID != nil {
+		where = append(where, fmt.Sprintf("t.student_id=$%d", i))
+		args = append(args, *f.StudentID)
+		i++
+	}
+
+	query := transferSelect + " WHERE " + strings.Join(where, " AND ") + " ORDER BY t.created_at DESC, t.id DESC"
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []models.StudentTransfer{}
+	for rows.Next() {
+		t, err := scanTransfer(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, *t)
+	}
+	return list, rows.Err()
+}
+
+// Decide закрывает заявку; false — заявка уже не в статусе pending
+func (r *TransferRepository) Decide(ctx context.Context, id int, status string, toClassID *int, note *string, decidedBy *int) (bool, error) {
+	res, err := r.db.Exec(ctx, `
+		UPDATE student_transfers
+		SET status=$2, to_class_id=$3, decision_note=$4, decided_by=$5, decided_at=NOW()
+		WHERE id=$1 AND status='pending'`, id, status, toClassID, note, decidedBy)
+	if err != nil {
+		return false, err
+	}
+	return res.RowsAffected() > 0, nil
+}
@@ -0,0 +1,167 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"eduBase/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+var ErrTrashItemNotFound = errors.New("item not found in trash")
+
+// Типы записей корзины
+const (
+	TrashSchool  = "school"
+	TrashClass   = "class"
+	TrashStaff   = "staff"
+	TrashStudent = "student"
+)
+
+// trashSources — как каждая сущность выглядит в корзине
+var trashSources = map[string]string{
+	TrashSchool:  `SELECT 'school', id, name, id, NULL::int, deleted_at, deleted_by FROM schools WHERE deleted_at IS NOT NULL`,
+	TrashClass:   `SELECT 'class', id, name, school_id, NULL::int, deleted_at, deleted_by FROM classes WHERE deleted_at IS NOT NULL`,
+	TrashStaff:   `SELECT 'staff', id, full_name, school_id, NULL::int, deleted_at, deleted_by FROM staff WHERE deleted_at IS NOT NULL`,
+	TrashStudent: `SELECT 'student', id, full_name, school_id, class_id, deleted_at, deleted_by FROM students WHERE deleted_at IS NOT NULL`,
+}
+
+// trashTables — таблица сущности; дочерние записи чистятся раньше родителей
+var trashTables = []struct{ entity, table string }{
+	{TrashStudent, "students"},
+	{TrashStaff, "staff"},
+	{TrashClass, "classes"},
+	{TrashSchool, "schools"},
+}
+
+type TrashRepository struct {
+	db DBTX
+}
+
+func NewTrashRepository(db DBTX) *TrashRepository {
+	return &TrashRepository{db: db}
+}
+
+func (r *TrashRepository) DB() DBTX { return r.db }
+
+// List возвращает содержимое корзины по указанным типам; schoolID != nil — только записи этой школы
+func (r *TrashRepository) List(ctx context.Context, types []string, schoolID *int) ([]models.TrashItem, error) {
+	var parts []string
+	for _, t := range types {
+		src, ok := trashSources[t]
+		if !ok {
+			continue
+		}
+		parts = append(parts, src)
+	}
+	if len(parts) == 0 {
+		return nil, nil
+	}
+
+	query := fmt.Sprintf(`
+		SELECT * FROM (%s) t(entity_type, id, name, school_id, class_id, deleted_at, deleted_by)
+		WHERE ($1::int IS NULL OR school_id=$1)
+		ORDER BY deleted_at DESC, entity_type, id`, strings.Join(parts, " UNION ALL "))
+
+	rows, err := r.db.Query(ctx, query, schoolID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []models.TrashItem
+	for rows.Next() {
+		var it models.TrashItem
+		if err := rows.Scan(
+			&it.EntityType, &it.ID, &it.Name, &it.SchoolID, &it.ClassID, &it.DeletedAt, &it.DeletedBy,
+		); err != nil {
+			return nil, err
+		}
+		list = append(list, it)
+	}
+	return list, rows.Err()
+}
+
+// Get возвращает удалённую запись из корзины
+func (r *TrashRepository) Get(ctx context.Context, entityType string, id int) (*models.TrashItem, error) {
+	src, ok := trashSources[entityType]
+	if !ok {
+		return nil, ErrTrashItemNotFound
+	}
+	var it models.TrashItem
+	err := r.db.QueryRow(ctx, src+` AND id=$1`, id).Scan(
+		&it.EntityType, &it.ID, &it.Name, &it.SchoolID, &it.ClassID, &it.DeletedAt, &it.DeletedBy,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrTrashItemNotFound
+		}
+		return nil, err
+	}
+	return &it, nil
+}
+
+// Restore возвращает запись из корзины. Школа и класс восстанавливаются вместе
+// с дочерними записями, удалёнными в тот же момент (общая отметка deleted_at);
+// удалённые раньше по отдельности остаются в корзине. Возвращает восстановленных учеников.
+func (r *TrashRepository) Restore(ctx context.Context, it *models.TrashItem) ([]models.Student, error) {
+	var students []models.Student
+	var err error
+	switch it.EntityType {
+	case TrashSchool:
+		for _, table := range []string{"classes", "staff"} {
+			if err := r.restoreWhere(ctx, table, "school_id", it.ID, it.DeletedAt); err != nil {
+				return nil, err
+			}
+		}
+		if students, err = r.restoreStudents(ctx, "school_id", it.ID, it.DeletedAt); err != nil {
+			return nil, err
+		}
+		return students, r.restoreWhere(ctx, "schools", "id", it.ID, it.DeletedAt)
+	case TrashClass:
+		if students, err = r.restoreStudents(ctx, "class_id", it.ID, it.DeletedAt); err != nil {
+			return nil, err
+		}
+		return students, r.restoreWhere(ctx, "classes", "id", it.ID, it.DeletedAt)
+	case TrashStaff:
+		return nil, r.restoreWhere(ctx, "staff", "id", it.ID, it.DeletedAt)
+	case TrashStudent:
+		return r.restoreStudents(ctx, "id", it.ID, it.DeletedAt)
+	}
+	return nil, ErrTrashItemNotFound
+}
+
+func (r *TrashRepository) restoreWhere(ctx context.Context, table, column string, id int, deletedAt time.Time) error {
+	_, err := r.db.Exec(ctx, fmt.Sprintf(`
+		UPDATE %s SET deleted_at=NULL, deleted_by=NULL
+		WHERE %s=$1 AND deleted_at=$2`, table, column), id, deletedAt)
+	return err
+}
+
+// restoreStudents восстанавливает учеников и возвращает их (id, школа, класс, статус)
+func (r *TrashRepository) restoreStudents(ctx context.Context, column string, id int, deletedAt time.Time) ([]models.Student, error) {
+	return collectPlaces(r.db.Query(ctx, fmt.Sprintf(`
+		UPDATE students SET deleted_at=NULL, deleted_by=NULL
+		WHERE %s=$1 AND deleted_at=$2
+		RETURNING id, school_id, class_id, status`, column), id, deletedAt))
+}
+
+// Purge окончательно удаляет записи, пролежавшие в корзине дольше retention
+// (срок считается по часам БД, как и deleted_at). Возвращает количество удалённых записей по типам.
+func (r *TrashRepository) Purge(ctx context.Context, retention time.Duration) (map[string]int64, error) {
+	res := make(map[string]int64)
+	for _, t := range trashTables {
+		tag, err := r.db.Exec(ctx, fmt.Sprintf(`
+			DELETE FROM %s
+			WHERE deleted_at IS NOT NULL AND deleted_at < NOW() - make_interval(secs => $1)`, t.table),
+			retention.Seconds())
+		if err != nil {
+			return nil, err
+		}
+		res[t.entity] = tag.RowsAffected()
+	}
+	return res, nil
+}
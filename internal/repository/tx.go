@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// TxManager — единица работы: выполняет несколько вызовов репозиториев атомарно.
+type TxManager struct {
+	pool *pgxpool.Pool
+}
+
+func NewTxManager(pool *pgxpool.Pool) *TxManager {
+	return &TxManager{pool: pool}
+}
+
+// WithTx открывает транзакцию и передаёт её в fn как DBTX.
+// Репозитории внутри fn создаются поверх q: repository.NewStudentRepository(q).
+// Если fn вернула ошибку или запаниковала — транзакция откатывается, иначе коммитится.
+func (m *TxManager) WithTx(ctx context.Context, fn func(q DBTX) error) (err error) {
+	tx, err := m.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback(ctx)
+			panic(p)
+		}
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	if err = fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
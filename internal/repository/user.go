@@ -8,20 +8,27 @@ import (
 	"github.com/jackc/pgx/v5"
 )
 
-var ErrUserNotFound = errors.New("user not found")
+var (
+	ErrUserNotFound = errors.New("user not found")
+	ErrEmailTaken   = errors.New("email already taken")
+)
+
+const userColumns = `id, email, password, full_name, role, school_id, class_id, must_change_password, created_at`
 
 type UserRepository struct {
-	db *pgx.Conn
+	db DBTX
 }
 
-func NewUserRepository(db *pgx.Conn) *UserRepository {
+func NewUserRepository(db DBTX) *UserRepository {
 	return &UserRepository{db: db}
 }
 
-func (r *UserRepository) FindByEmail(ctx context.Context, email string) (*models.User, error) {
-	row := r.db.QueryRow(ctx, `SELECT id, email, password, role, created_at FROM users WHERE email=$1`, email)
+func scanUser(row pgx.Row) (*models.User, error) {
 	var u models.User
-	if err := row.Scan(&u.ID, &u.Email, &u.Password, &u.Role, &u.CreatedAt); err != nil {
+	if err := row.Scan(
+		&u.ID, &u.Email, &u.Password, &u.FullName, &u.Role,
+		&u.SchoolID, &u.ClassID, &u.MustChangePassword, &u.CreatedAt,
+	); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, ErrUserNotFound
 		}
@@ -30,14 +37,143 @@ func (r *UserRepository) FindByEmail(ctx context.Context, email string) (*models
 	return &u, nil
 }
 
+func (r *UserRepository) FindByEmail(ctx context.Context, email string) (*models.User, error) {
+	return scanUser(r.db.QueryRow(ctx, `SELECT `+userColumns+` FROM users WHERE email=$1`, email))
+}
+
+func (r *UserRepository) FindByID(ctx context.Context, id int) (*models.User, error) {
+	return scanUser(r.db.QueryRow(ctx, `SELECT `+userColumns+` FROM users WHERE id=$1`, id))
+}
+
 func (r *UserRepository) Create(ctx context.Context, u *models.User) error {
-	_, err := r.db.Exec(ctx, `
-		INSERT INTO users (email, password, role)
-		VALUES ($1, $2, $3)
-	`, u.Email, u.Password, u.Role)
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO users (email, password, full_name, role, school_id, class_id, must_change_password)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at
+	`, u.Email, u.Password, u.FullName, u.Role, u.SchoolID, u.ClassID, u.MustChangePassword).Scan(&u.ID, &u.CreatedAt)
+	if isUniqueViolation(err) {
+		return ErrEmailTaken
+	}
+	return err
+}
+
+// ListByRoles — пользователи с одной из ролей, по алфавиту
+func (r *UserRepository) ListByRoles(ctx context.Context, roles ...string) ([]models.User, error) {
+	rows, err := r.db.Query(ctx, `SELECT `+userColumns+` FROM users WHERE role = ANY($1) ORDER BY email`, roles)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []models.User
+	for rows.Next() {
+		u, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, *u)
+	}
+	return list, rows.Err()
+}
+
+// ListBySchool — учётные записи сотрудников школы
+func (r *UserRepository) ListBySchool(ctx context.Context, schoolID int) ([]models.User, error) {
+	rows, err := r.db.Query(ctx, `SELECT `+userColumns+` FROM users WHERE school_id=$1 ORDER BY role, email`, schoolID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []models.User
+	for rows.Next() {
+		u, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, *u)
+	}
+	return list, rows.Err()
+}
+
+// CountByRole — число пользователей с ролью, которые могут войти (заблокированные не считаются)
+func (r *UserRepository) CountByRole(ctx context.Context, role string) (int, error) {
+	var n int
+	err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM users WHERE role=$1 AND password <> $2`, role, models.LockedPassword).Scan(&n)
+	return n, err
+}
+
+// LockByRole блокирует (FOR UPDATE) пользователей с ролью до конца транзакции и возвращает число тех,
+// кто может войти, — проверка «останется ли кто-то с этой ролью» не гонится с параллельным удалением или сменой роли
+func (r *UserRepository) LockByRole(ctx context.Context, role string) (int, error) {
+	var n int
+	err := r.db.QueryRow(ctx, `
+		SELECT COUNT(*) FILTER (WHERE password <> $2)
+		FROM (SELECT id, password FROM users WHERE role=$1 FOR UPDATE) locked`, role, models.LockedPassword).Scan(&n)
+	return n, err
+}
+
+// UpdateProfile меняет email, ФИО, роль и класс пользователя (школа не меняется)
+func (r *UserRepository) UpdateProfile(ctx context.Context, id int, u *models.User) (int64, error) {
+	res, err := r.db.Exec(ctx, `
+		UPDATE users SET email=$1, full_name=$2, role=$3, class_id=$4
+		WHERE id=$5`, u.Email, u.FullName, u.Role, u.ClassID, id)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return 0, ErrEmailTaken
+		}
+		return 0, err
+	}
+	return res.RowsAffected(), nil
+}
+
+// UpdatePassword сохраняет хэш пароля, не трогая флаг обязательной смены
+func (r *UserRepository) UpdatePassword(ctx context.Context, id int, hash string) error {
+	_, err := r.db.Exec(ctx, `UPDATE users SET password=$1 WHERE id=$2`, hash, id)
+	return err
+}
+
+// SetPassword сохраняет хэш пароля и флаг обязательной смены при следующем входе
+func (r *UserRepository) SetPassword(ctx context.Context, id int, hash string, mustChange bool) error {
+	_, err := r.db.Exec(ctx, `UPDATE users SET password=$1, must_change_password=$2 WHERE id=$3`, hash, mustChange, id)
+	return err
+}
+
+// SetSchool привязывает учётную запись к школе
+func (r *UserRepository) SetSchool(ctx context.Context, id, schoolID int) error {
+	_, err := r.db.Exec(ctx, `UPDATE users SET school_id=$1 WHERE id=$2`, schoolID, id)
+	return err
+}
+
+func (r *UserRepository) Delete(ctx context.Context, id int) error {
+	_, err := r.db.Exec(ctx, `DELETE FROM users WHERE id=$1`, id)
+	return err
+}
+
+// ListPlaintextPasswords возвращает пользователей, чей пароль ещё не захэширован bcrypt (кроме заблокированных)
+func (r *UserRepository) ListPlaintextPasswords(ctx context.Context) ([]models.User, error) {
+	rows, err := r.db.Query(ctx, `SELECT `+userColumns+` FROM users WHERE password NOT LIKE '$2_$%' AND password <> $1`, models.LockedPassword)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []models.User
+	for rows.Next() {
+		u, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, *u)
+	}
+	return list, rows.Err()
+}
+
+// ReassignClass переназначает учителей с одного класса на другой (перевод класса на следующий год)
+func (r *UserRepository) ReassignClass(ctx context.Context, fromClassID, toClassID int) error {
+	_, err := r.db.Exec(ctx, `UPDATE users SET class_id=$2 WHERE class_id=$1`, fromClassID, toClassID)
 	return err
 }
 
-func (r *UserRepository) DB() *pgx.Conn {
+func (r *UserRepository) DB() DBTX {
 	return r.db
 }
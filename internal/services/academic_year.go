@@ -0,0 +1,46 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"eduBase/internal/models"
+	"eduBase/internal/repository"
+)
+
+var ErrInvalidYearDates = errors.New("end_date must be after start_date")
+
+// AcademicYearService — учебные годы; текущий год определяет выборки классов и учеников по умолчанию
+type AcademicYearService struct {
+	repo *repository.AcademicYearRepository
+	tx   *repository.TxManager
+}
+
+func NewAcademicYearService(repo *repository.AcademicYearRepository, tx *repository.TxManager) *AcademicYearService {
+	return &AcademicYearService{repo: repo, tx: tx}
+}
+
+func (s *AcademicYearService) GetAll(ctx context.Context) ([]models.AcademicYear, error) {
+	return s.repo.GetAll(ctx)
+}
+
+func (s *AcademicYearService) GetCurrent(ctx context.Context) (*models.AcademicYear, error) {
+	return s.repo.GetCurrent(ctx)
+}
+
+func (s *AcademicYearService) Create(ctx context.Context, y *models.AcademicYear) error {
+	if !y.EndDate.After(y.StartDate) {
+		return ErrInvalidYearDates
+	}
+	return s.repo.Create(ctx, y)
+}
+
+// SetCurrent делает год текущим и пересчитывает class_count школ под новый год
+func (s *AcademicYearService) SetCurrent(ctx context.Context, id int) error {
+	return s.tx.WithTx(ctx, func(q repository.DBTX) error {
+		if err := repository.NewAcademicYearRepository(q).SetCurrent(ctx, id); err != nil {
+			return err
+		}
+		return repository.NewSchoolRepository(q).RefreshClassCounts(ctx)
+	})
+}
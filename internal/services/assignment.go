@@ -0,0 +1,146 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"eduBase/internal/audit"
+	"eduBase/internal/models"
+	"eduBase/internal/repository"
+)
+
+var (
+	ErrStaffNotInSchool = errors.New("staff member does not belong to this school")
+	ErrInvalidHours     = errors.New("hours_per_week must be between 0.5 and 40")
+)
+
+// AssignmentService — нагрузка: кто какой предмет ведёт в каком классе
+type AssignmentService struct {
+	repo *repository.AssignmentRepository
+	tx   *repository.TxManager
+}
+
+func NewAssignmentService(repo *repository.AssignmentRepository, tx *repository.TxManager) *AssignmentService {
+	return &AssignmentService{repo: repo, tx: tx}
+}
+
+// checkStaffInSchool — сотрудник существует и работает в школе
+func checkStaffInSchool(ctx context.Context, q repository.DBTX, staffID, schoolID int) error {
+	st, err := repository.NewStaffRepository(q).GetByID(ctx, staffID)
+	if err != nil {
+		if errors.Is(err, repository.ErrStaffNotFound) {
+			return ErrStaffNotInSchool
+		}
+		return err
+	}
+	if st.SchoolID != schoolID {
+		return ErrStaffNotInSchool
+	}
+	return nil
+}
+
+func validHours(h float64) bool {
+	return h >= 0.5 && h <= 40
+}
+
+func (s *AssignmentService) GetAll(ctx context.Context, f repository.AssignmentFilter) ([]models.TeachingAssignment, error) {
+	return s.repo.GetAll(ctx, f)
+}
+
+// Create назначает учителя (или вакансию, если StaffID == nil) на предмет в классе.
+// schoolID != nil — только в классы этой школы.
+func (s *AssignmentService) Create(ctx context.Context, a *models.TeachingAssignment, schoolID *int) error {
+	if !validHours(a.HoursPerWeek) {
+		return ErrInvalidHours
+	}
+	return s.tx.WithTx(ctx, func(q repository.DBTX) error {
+		c, err := repository.NewClassRepository(q).GetByID(ctx, a.ClassID)
+		if err != nil {
+			if errors.Is(err, repository.ErrClassNotFound) {
+				return ErrClassNotInSchool
+			}
+			return err
+		}
+		if schoolID != nil && c.SchoolID != *schoolID {
+			return ErrClassNotInSchool
+		}
+		if _, err := repository.NewSubjectRepository(q).GetByID(ctx, a.SubjectID); err != nil {
+			return err
+		}
+		if a.StaffID != nil {
+			if err := checkStaffInSchool(ctx, q, *a.StaffID, c.SchoolID); err != nil {
+				return err
+			}
+		}
+
+		a.SchoolID = c.SchoolID
+		repo := repository.NewAssignmentRepository(q)
+		if err := repo.Create(ctx, a); err != nil {
+			return err
+		}
+		fresh, err := repo.GetByID(ctx, a.ID)
+		if err != nil {
+			return err
+		}
+		*a = *fresh
+		return audit.Record(ctx, q, audit.Change{
+			SchoolID: &a.SchoolID, EntityType: audit.EntityAssignment, EntityID: a.ID,
+			Action: audit.ActionCreate, After: a,
+		})
+	})
+}
+
+// Update меняет учителя (nil — вакансия) и часы; schoolID != nil — только назначения этой школы
+func (s *AssignmentService) Update(ctx context.Context, id int, staffID *int, hours float64, schoolID *int) (*models.TeachingAssignment, error) {
+	if !validHours(hours) {
+		return nil, ErrInvalidHours
+	}
+	var res *models.TeachingAssignment
+	err := s.tx.WithTx(ctx, func(q repository.DBTX) error {
+		repo := repository.NewAssignmentRepository(q)
+		old, err := repo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if schoolID != nil && old.SchoolID != *schoolID {
+			return repository.ErrAssignmentNotFound
+		}
+		if staffID != nil {
+			if err := checkStaffInSchool(ctx, q, *staffID, old.SchoolID); err != nil {
+				return err
+			}
+		}
+		if err := repo.Update(ctx, id, staffID, hours); err != nil {
+			return err
+		}
+		if res, err = repo.GetByID(ctx, id); err != nil {
+			return err
+		}
+		return audit.Record(ctx, q, audit.Change{
+			SchoolID: &old.SchoolID, EntityType: audit.EntityAssignment, EntityID: id,
+			Action: audit.ActionUpdate, Before: old, After: res,
+		})
+	})
+	return res, err
+}
+
+// Delete снимает назначение; schoolID != nil — только назначения этой школы
+func (s *AssignmentService) Delete(ctx context.Context, id int, schoolID *int) error {
+	return s.tx.WithTx(ctx, func(q repository.DBTX) error {
+		repo := repository.NewAssignmentRepository(q)
+		old, err := repo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if schoolID != nil && old.SchoolID != *schoolID {
+			return repository.ErrAssignmentNotFound
+		}
+		if err := repo.Delete(ctx, id); err != nil {
+			return err
+		}
+		return audit.Record(ctx, q, audit.Change{
+			SchoolID: &old.SchoolID, EntityType: audit.EntityAssignment, EntityID: id,
+			Action: audit.ActionDelete, Before: old,
+		})
+	})
+}
@@ -0,0 +1,134 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"eduBase/internal/audit"
+	"eduBase/internal/models"
+	"eduBase/internal/repository"
+)
+
+var (
+	ErrInvalidAttendanceStatus = errors.New("status must be one of: present, sick, excused, unexcused, late")
+	ErrAttendanceStudent       = errors.New("student is not an active student of this class")
+	ErrAttendanceFutureDate    = errors.New("attendance cannot be marked for a future date")
+	ErrAttendanceOutsideYear   = errors.New("date is outside the current academic year")
+	ErrAttendanceDuplicate     = errors.New("student is listed more than once")
+)
+
+var attendanceStatuses = map[string]bool{
+	models.AttendancePresent: true, models.AttendanceSick: true, models.AttendanceExcused: true,
+	models.AttendanceUnexcused: true, models.AttendanceLate: true,
+}
+
+// AttendanceService — ежедневная посещаемость по классам
+type AttendanceService struct {
+	repo      *repository.AttendanceRepository
+	classRepo *repository.ClassRepository
+	tx        *repository.TxManager
+}
+
+func NewAttendanceService(repo *repository.AttendanceRepository, cr *repository.ClassRepository, tx *repository.TxManager) *AttendanceService {
+	return &AttendanceService{repo: repo, classRepo: cr, tx: tx}
+}
+
+func (s *AttendanceService) GetClass(ctx context.Context, classID int) (*models.Class, error) {
+	return s.classRepo.GetByID(ctx, classID)
+}
+
+// GetByClass — отметки класса за день
+func (s *AttendanceService) GetByClass(ctx context.Context, classID int, date time.Time) (*models.ClassAttendance, error) {
+	list, err := s.repo.GetByClass(ctx, classID, date)
+	if err != nil {
+		return nil, err
+	}
+	return &models.ClassAttendance{ClassID: classID, Date: date.Format(time.DateOnly), Records: list}, nil
+}
+
+// Mark сохраняет отметки класса за день одной транзакцией: либо все, либо ни одной.
+// Отмечать можно только обучающихся учеников класса текущего учебного года и только в пределах этого года,
+// каждого ученика — один раз; ранее поставленные отметки перезаписываются.
+func (s *AttendanceService) Mark(ctx context.Context, c *models.Class, date time.Time, marks []models.AttendanceMark) (*models.ClassAttendance, error) {
+	if date.Format(time.DateOnly) > time.Now().Format(time.DateOnly) {
+		return nil, ErrAttendanceFutureDate
+	}
+	seen := make(map[int]bool, len(marks))
+	for _, m := range marks {
+		if !attendanceStatuses[m.Status] {
+			return nil, ErrInvalidAttendanceStatus
+		}
+		if seen[m.StudentID] {
+			return nil, ErrAttendanceDuplicate
+		}
+		seen[m.StudentID] = true
+	}
+
+	var res *models.ClassAttendance
+	err := s.tx.WithTx(ctx, func(q repository.DBTX) error {
+		year, err := currentClassYear(ctx, q, c)
+		if err != nil {
+			return err
+		}
+		if !inYear(year, date) {
+			return ErrAttendanceOutsideYear
+		}
+		repo := repository.NewAttendanceRepository(q)
+		current, err := repo.GetByClass(ctx, c.ID, date)
+		if err != nil {
+			return err
+		}
+		inClass := make(map[int]models.AttendanceRecord, len(current))
+		for _, a := range current {
+			inClass[a.StudentID] = a
+		}
+
+		markedBy := actorID(ctx)
+		for _, m := range marks {
+			old, ok := inClass[m.StudentID]
+			if !ok {
+				return ErrAttendanceStudent
+			}
+			if err := repo.Upsert(ctx, m, c.ID, c.SchoolID, date, markedBy); err != nil {
+				return err
+			}
+			if err := recordAttendance(ctx, q, c, date, old, m); err != nil {
+				return err
+			}
+		}
+
+		list, err := repo.GetByClass(ctx, c.ID, date)
+		if err != nil {
+			return err
+		}
+		res = &models.ClassAttendance{ClassID: c.ID, Date: date.Format(time.DateOnly), Records: list}
+		return nil
+	})
+	return res, err
+}
+
+// attendanceChange — отметка ученика за день в журнале изменений
+type attendanceChange struct {
+	ClassID int     `json:"class_id"`
+	Date    string  `json:"date"`
+	Status  string  `json:"status"`
+	Note    *string `json:"note,omitempty"`
+}
+
+// recordAttendance пишет в журнал изменение отметки ученика (entity_id — ID ученика):
+// первая отметка за день — create, исправление — update с прежним и новым значением
+func recordAttendance(ctx context.Context, q repository.DBTX, c *models.Class, date time.Time, old models.AttendanceRecord, m models.AttendanceMark) error {
+	day := date.Format(time.DateOnly)
+	ch := audit.Change{
+		SchoolID: &c.SchoolID, EntityType: audit.EntityAttendance, EntityID: m.StudentID,
+		Action: audit.ActionCreate,
+		After:  attendanceChange{ClassID: c.ID, Date: day, Status: m.Status, Note: m.Note},
+		Keep:   []string{"class_id", "date"},
+	}
+	if old.Status != nil {
+		ch.Action = audit.ActionUpdate
+		ch.Before = attendanceChange{ClassID: c.ID, Date: day, Status: *old.Status, Note: old.Note}
+	}
+	return audit.Record(ctx, q, ch)
+}
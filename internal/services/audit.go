@@ -0,0 +1,35 @@
+package services
+
+import (
+	"context"
+
+	"eduBase/internal/models"
+	"eduBase/internal/repository"
+)
+
+const (
+	defaultAuditLimit = 100
+	maxAuditLimit     = 1000
+)
+
+// AuditService — чтение журнала изменений (запись идёт через пакет audit внутри транзакций)
+type AuditService struct {
+	repo *repository.AuditRepository
+}
+
+func NewAuditService(repo *repository.AuditRepository) *AuditService {
+	return &AuditService{repo: repo}
+}
+
+func (s *AuditService) GetAll(ctx context.Context, f repository.AuditFilter) ([]models.AuditEntry, error) {
+	if f.Limit <= 0 {
+		f.Limit = defaultAuditLimit
+	}
+	if f.Limit > maxAuditLimit {
+		f.Limit = maxAuditLimit
+	}
+	if f.Offset < 0 {
+		f.Offset = 0
+	}
+	return s.repo.GetAll(ctx, f)
+}
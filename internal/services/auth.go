@@ -2,110 +2,441 @@ package services
 
 import (
 	"context"
+	"crypto/subtle"
 	"eduBase/internal/utils"
 	"errors"
 	"github.com/go-chi/jwtauth/v5"
 	"time"
 
+	"eduBase/internal/access"
+	"eduBase/internal/audit"
 	"eduBase/internal/models"
 	"eduBase/internal/repository"
 
 	"golang.org/x/crypto/bcrypt"
 )
 
+var (
+	ErrInvalidCredentials  = errors.New("invalid email or password")
+	ErrAccountDisabled     = errors.New("account is disabled")
+	ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+	ErrWrongPassword       = errors.New("current password is incorrect")
+	ErrWeakPassword        = errors.New("password must be at least 8 characters")
+	ErrSamePassword        = errors.New("new password must differ from the current one")
+	ErrBootstrapNotSet     = errors.New("ADMIN_EMAIL and ADMIN_PASSWORD are required to create the first ROO user")
+)
+
+const minPasswordLength = 8
+
+// TokenPair — короткоживущий access-токен и refresh-токен для его обновления.
+type TokenPair struct {
+	AccessToken        string
+	RefreshToken       string
+	ExpiresIn          int64 // время жизни access-токена в секундах
+	MustChangePassword bool
+}
+
+// SessionMeta — сведения о клиенте, сохраняемые в сессии.
+type SessionMeta struct {
+	UserAgent string
+	IP        string
+}
+
 type AuthService struct {
-	repo     *repository.UserRepository
-	jwt      *jwtauth.JWTAuth
-	tokenExp time.Duration
+	repo       *repository.UserRepository
+	tx         *repository.TxManager
+	jwt        *jwtauth.JWTAuth
+	accessTTL  time.Duration
+	refreshTTL time.Duration
 }
 
-func NewAuthService(repo *repository.UserRepository, jwt *jwtauth.JWTAuth) *AuthService {
-	return &AuthService{repo: repo, jwt: jwt, tokenExp: 24 * time.Hour}
+func NewAuthService(repo *repository.UserRepository, tx *repository.TxManager, jwt *jwtauth.JWTAuth, accessTTL, refreshTTL time.Duration) *AuthService {
+	return &AuthService{repo: repo, tx: tx, jwt: jwt, accessTTL: accessTTL, refreshTTL: refreshTTL}
 }
 
-func (s *AuthService) Login(ctx context.Context, email, password string) (string, error) {
+func (s *AuthService) Login(ctx context.Context, email, password string, meta SessionMeta) (*TokenPair, error) {
 	u, err := s.repo.FindByEmail(ctx, email)
 	if err != nil {
 		// не раскрываем, существует ли пользователь
 		time.Sleep(150 * time.Millisecond)
-		return "", errors.New("invalid email or password")
+		return nil, ErrInvalidCredentials
 	}
 
 	// сравнение пароля
-	passBytes := []byte(password)
-	dbPassBytes := []byte(u.Password)
+	if !s.checkPassword(ctx, u, password) {
+		time.Sleep(150 * time.Millisecond)
+		return nil, ErrInvalidCredentials
+	}
+
+	var pair *TokenPair
+	err = s.tx.WithTx(ctx, func(q repository.DBTX) error {
+		session, refresh, err := s.newSession(ctx, q, u.ID, meta)
+		if err != nil {
+			return err
+		}
+		pair, err = s.issueTokens(ctx, q, u, session.ID, refresh)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return pair, nil
+}
+
+// Refresh обменивает refresh-токен на новую пару токенов (ротация).
+// Старая сессия отзывается; повторное предъявление уже использованного токена
+// считается компрометацией и отзывает все сессии пользователя.
+func (s *AuthService) Refresh(ctx context.Context, refreshToken string, meta SessionMeta) (*TokenPair, error) {
+	var pair *TokenPair
+	reused := false
+
+	err := s.tx.WithTx(ctx, func(q repository.DBTX) error {
+		sessions := repository.NewSessionRepository(q)
+		old, err := sessions.FindByRefreshHash(ctx, utils.HashToken(refreshToken))
+		if err != nil {
+			if errors.Is(err, repository.ErrSessionNotFound) {
+				return ErrInvalidRefreshToken
+			}
+			return err
+		}
+
+		if old.RevokedAt != nil {
+			if old.ReplacedBy != nil {
+				reused = true
+				return sessions.RevokeAllForUser(ctx, old.UserID)
+			}
+			return ErrInvalidRefreshToken
+		}
+		if old.Expired {
+			return ErrInvalidRefreshToken
+		}
+
+		u, err := repository.NewUserRepository(q).FindByID(ctx, old.UserID)
+		if err != nil {
+			if errors.Is(err, repository.ErrUserNotFound) {
+				return ErrInvalidRefreshToken
+			}
+			return err
+		}
+
+		session, refresh, err := s.newSession(ctx, q, u.ID, meta)
+		if err != nil {
+			return err
+		}
+		if err := sessions.Revoke(ctx, old.ID, &session.ID); err != nil {
+			return err
+		}
+		pair, err = s.issueTokens(ctx, q, u, session.ID, refresh)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if reused {
+		return nil, ErrInvalidRefreshToken
+	}
+	return pair, nil
+}
+
+// Logout отзывает текущую сессию.
+func (s *AuthService) Logout(ctx context.Context, sessionID int64) error {
+	return repository.NewSessionRepository(s.repo.DB()).Revoke(ctx, sessionID, nil)
+}
+
+// RevokeAllSessions отзывает все сессии пользователя — выданные токены перестают работать сразу.
+func (s *AuthService) RevokeAllSessions(ctx context.Context, userID int) error {
+	return repository.NewSessionRepository(s.repo.DB()).RevokeAllForUser(ctx, userID)
+}
+
+// ChangePassword меняет пароль текущего пользователя, снимает флаг обязательной смены,
+// завершает все прочие сессии и выдаёт новую пару токенов.
+func (s *AuthService) ChangePassword(ctx context.Context, userID int, oldPassword, newPassword string, meta SessionMeta) (*TokenPair, error) {
+	if len(newPassword) < minPasswordLength {
+		return nil, ErrWeakPassword
+	}
+	if oldPassword == newPassword {
+		return nil, ErrSamePassword
+	}
+
+	u, err := s.repo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !s.checkPassword(ctx, u, oldPassword) {
+		time.Sleep(150 * time.Millisecond)
+		return nil, ErrWrongPassword
+	}
 
-	switch u.Role {
-	case "roo":
-		// админские — хэшированные
-		if err := bcrypt.CompareHashAndPassword(dbPassBytes, passBytes); err != nil {
-			time.Sleep(150 * time.Millisecond)
-			return "", errors.New("invalid email or password")
+	hash, err := utils.HashPassword(newPassword)
+	if err != nil {
+		return nil, errors.New("failed to hash password")
+	}
+
+	var pair *TokenPair
+	err = s.tx.WithTx(ctx, func(q repository.DBTX) error {
+		if err := repository.NewUserRepository(q).SetPassword(ctx, u.ID, hash, false); err != nil {
+			return err
+		}
+		if err := repository.NewSessionRepository(q).RevokeAllForUser(ctx, u.ID); err != nil {
+			return err
 		}
-	default:
-		// школы — пока в чистом виде (на проде можно захэшировать)
-		if u.Password != password {
-			time.Sleep(150 * time.Millisecond)
-			return "", errors.New("invalid email or password")
+		u.MustChangePassword = false
+		if err := audit.Record(ctx, q, audit.Change{
+			SchoolID: u.SchoolID, EntityType: audit.EntityUser, EntityID: u.ID,
+			Action: audit.ActionChangePassword,
+		}); err != nil {
+			return err
 		}
+
+		session, refresh, err := s.newSession(ctx, q, u.ID, meta)
+		if err != nil {
+			return err
+		}
+		pair, err = s.issueTokens(ctx, q, u, session.ID, refresh)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return pair, nil
+}
+
+// BootstrapAdmin создаёт первого пользователя ROO из ADMIN_EMAIL/ADMIN_PASSWORD,
+// если в системе нет ни одного, который может войти (например, остался только заблокированный admin). Пароль придётся сменить при первом входе.
+func (s *AuthService) BootstrapAdmin(ctx context.Context, email, password string) (bool, error) {
+	n, err := s.repo.CountByRole(ctx, access.RoleROO)
+	if err != nil {
+		return false, err
+	}
+	if n > 0 {
+		return false, nil
+	}
+	if email == "" || password == "" {
+		return false, ErrBootstrapNotSet
 	}
+	if len(password) < minPasswordLength {
+		return false, ErrWeakPassword
+	}
+
+	hash, err := utils.HashPassword(password)
+	if err != nil {
+		return false, err
+	}
+	admin := &models.User{
+		Email:              email,
+		Password:           hash,
+		Role:               access.RoleROO,
+		MustChangePassword: true,
+	}
+	if err := s.repo.Create(ctx, admin); err != nil {
+		return false, err
+	}
+	return true, nil
+}
 
+// newSession создаёт сессию и возвращает её вместе с открытым refresh-токеном.
+func (s *AuthService) newSession(ctx context.Context, q repository.DBTX, userID int, meta SessionMeta) (*models.Session, string, error) {
+	refresh, err := utils.GenerateToken(32)
+	if err != nil {
+		return nil, "", errors.New("failed to generate token")
+	}
+	session := &models.Session{
+		UserID:           userID,
+		RefreshTokenHash: utils.HashToken(refresh),
+	}
+	if meta.UserAgent != "" {
+		session.UserAgent = &meta.UserAgent
+	}
+	if meta.IP != "" {
+		session.IP = &meta.IP
+	}
+	if err := repository.NewSessionRepository(q).Create(ctx, session, s.refreshTTL); err != nil {
+		return nil, "", err
+	}
+	return session, refresh, nil
+}
+
+// issueTokens подписывает access-токен, привязанный к сессии (claim sid).
+func (s *AuthService) issueTokens(ctx context.Context, q repository.DBTX, u *models.User, sessionID int64, refresh string) (*TokenPair, error) {
 	// базовые клеймы
 	claims := map[string]interface{}{
 		"user_id": u.ID,
 		"role":    u.Role,
-		"exp":     time.Now().Add(s.tokenExp).Unix(),
+		"sid":     sessionID,
+		"exp":     time.Now().Add(s.accessTTL).Unix(),
+	}
+
+	// пока пароль не сменён, токен пускает только на /auth/me/password и /auth/logout
+	if u.MustChangePassword {
+		claims["pwd_change"] = true
 	}
 
-	// если школа — добавляем school_name
-	if u.Role == "school" {
-		schoolRepo := repository.NewSchoolRepository(s.repo.DB())
-		if school, err := schoolRepo.GetByUserID(ctx, u.ID); err == nil {
-			claims["school_name"] = school.Name
-			claims["school_id"] = school.ID
+	// сотрудник школы — добавляем school_id/school_name, учитель — свой класс
+	if u.SchoolID != nil {
+		school, err := repository.NewSchoolRepository(q).GetByID(ctx, *u.SchoolID)
+		if err != nil {
+			if errors.Is(err, repository.ErrSchoolNotFound) {
+				// школа в корзине — вход её сотрудникам закрыт до восстановления
+				return nil, ErrAccountDisabled
+			}
+			return nil, err
 		}
+		claims["school_name"] = school.Name
+		claims["school_id"] = school.ID
+	}
+	if u.ClassID != nil {
+		claims["class_id"] = *u.ClassID
 	}
 
 	// формируем JWT
 	_, tokenStr, err := s.jwt.Encode(claims)
 	if err != nil {
-		return "", errors.New("failed to generate token")
+		return nil, errors.New("failed to generate token")
 	}
 
-	return tokenStr, nil
+	return &TokenPair{
+		AccessToken:        tokenStr,
+		RefreshToken:       refresh,
+		ExpiresIn:          int64(s.accessTTL.Seconds()),
+		MustChangePassword: u.MustChangePassword,
+	}, nil
 }
 
-func (s *AuthService) RegisterSchool(ctx context.Context, email, name, director string, schoolRepo *repository.SchoolRepository) (string, error) {
+// RegisterSchool создаёт пользователя и школу в одной транзакции и возвращает сгенерированный пароль.
+func (s *AuthService) RegisterSchool(ctx context.Context, email, name, director string) (string, error) {
 	// 1. генерируем пароль
 	password, err := utils.GeneratePassword(8)
 	if err != nil {
 		return "", errors.New("failed to generate password")
 	}
-
-	// 2. создаём пользователя с ролью school (plain password)
-	u := &models.User{
-		Email:    email,
-		Password: password, // сохраняем без хеша
-		Role:     "school",
+	hash, err := utils.HashPassword(password)
+	if err != nil {
+		return "", errors.New("failed to hash password")
 	}
-	if err := s.repo.Create(ctx, u); err != nil {
+
+	err = s.tx.WithTx(ctx, func(q repository.DBTX) error {
+		// 2. создаём директорскую учётную запись школы
+		users := repository.NewUserRepository(q)
+		u := &models.User{
+			Email:    email,
+			Password: hash,
+			Role:     access.RoleDirector,
+		}
+		if err := users.Create(ctx, u); err != nil {
+			return err
+		}
+
+		// 3. создаём школу, привязанную к пользователю, и привязываем пользователя к школе
+		school := &models.School{
+			Name:     name,
+			Director: director,
+		}
+		if err := repository.NewSchoolRepository(q).Create(ctx, school, u.ID); err != nil {
+			return err
+		}
+		if err := users.SetSchool(ctx, u.ID, school.ID); err != nil {
+			return err
+		}
+		u.SchoolID = &school.ID
+
+		if err := audit.Record(ctx, q, audit.Change{
+			SchoolID: &school.ID, EntityType: audit.EntitySchool, EntityID: school.ID,
+			Action: audit.ActionCreate, After: school,
+		}); err != nil {
+			return err
+		}
+		return audit.Record(ctx, q, audit.Change{
+			SchoolID: &school.ID, EntityType: audit.EntityUser, EntityID: u.ID,
+			Action: audit.ActionCreate, After: u,
+		})
+	})
+	if err != nil {
 		return "", err
 	}
 
-	// 3. ищем id
-	user, err := s.repo.FindByEmail(ctx, email)
+	return password, nil
+}
+
+// checkPassword сверяет пароль с bcrypt-хэшем.
+// Старые записи с паролем в открытом виде принимаются один раз и сразу хэшируются.
+func (s *AuthService) checkPassword(ctx context.Context, u *models.User, password string) bool {
+	if u.Password == models.LockedPassword {
+		return false
+	}
+	if utils.IsHashed(u.Password) {
+		return bcrypt.CompareHashAndPassword([]byte(u.Password), []byte(password)) == nil
+	}
+
+	if subtle.ConstantTimeCompare([]byte(u.Password), []byte(password)) != 1 {
+		return false
+	}
+	if hash, err := utils.HashPassword(password); err == nil {
+		_ = s.repo.UpdatePassword(ctx, u.ID, hash)
+	}
+	return true
+}
+
+// MigratePlaintextPasswords хэширует все пароли, оставшиеся в открытом виде.
+// Вызывается при старте приложения; возвращает количество обновлённых записей.
+func (s *AuthService) MigratePlaintextPasswords(ctx context.Context) (int, error) {
+	var migrated int
+	err := s.tx.WithTx(ctx, func(q repository.DBTX) error {
+		repo := repository.NewUserRepository(q)
+		users, err := repo.ListPlaintextPasswords(ctx)
+		if err != nil {
+			return err
+		}
+		for _, u := range users {
+			hash, err := utils.HashPassword(u.Password)
+			if err != nil {
+				return err
+			}
+			if err := repo.UpdatePassword(ctx, u.ID, hash); err != nil {
+				return err
+			}
+			migrated++
+		}
+		return nil
+	})
 	if err != nil {
-		return "", err
+		return 0, err
 	}
+	return migrated, nil
+}
 
-	// 4. создаём школу
-	school := &models.School{
-		Name:     name,
-		Director: director,
+// ResetSchoolPassword генерирует школе новый пароль и сохраняет только его хэш.
+// Пароль возвращается один раз — повторно получить его нельзя.
+func (s *AuthService) ResetSchoolPassword(ctx context.Context, schoolID int) (*models.School, string, error) {
+	password, err := utils.GeneratePassword(8)
+	if err != nil {
+		return nil, "", errors.New("failed to generate password")
 	}
-	if err := schoolRepo.Create(ctx, school, user.ID); err != nil {
-		return "", err
+	hash, err := utils.HashPassword(password)
+	if err != nil {
+		return nil, "", errors.New("failed to hash password")
 	}
 
-	return password, nil
+	var school *models.School
+	err = s.tx.WithTx(ctx, func(q repository.DBTX) error {
+		var err error
+		school, err = repository.NewSchoolRepository(q).GetByID(ctx, schoolID)
+		if err != nil {
+			return err
+		}
+		if err := repository.NewUserRepository(q).UpdatePassword(ctx, school.UserID, hash); err != nil {
+			return err
+		}
+		// старые токены после сброса пароля недействительны
+		if err := repository.NewSessionRepository(q).RevokeAllForUser(ctx, school.UserID); err != nil {
+			return err
+		}
+		return audit.Record(ctx, q, audit.Change{
+			SchoolID: &school.ID, EntityType: audit.EntityUser, EntityID: school.UserID,
+			Action: audit.ActionResetPassword,
+		})
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return school, password, nil
 }
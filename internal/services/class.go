@@ -2,47 +2,140 @@ package services
 
 import (
 	"context"
-	"github.com/jackc/pgx/v5"
+	"errors"
 
+	"eduBase/internal/audit"
 	"eduBase/internal/models"
 	"eduBase/internal/repository"
 )
 
 type ClassService struct {
 	repo *repository.ClassRepository
-	db   *pgx.Conn
+	db   repository.DBTX
+	tx   *repository.TxManager
 }
 
-func NewClassService(repo *repository.ClassRepository) *ClassService {
-	return &ClassService{repo: repo, db: repo.DB()}
+func NewClassService(repo *repository.ClassRepository, tx *repository.TxManager) *ClassService {
+	return &ClassService{repo: repo, db: repo.DB(), tx: tx}
 }
 
-func (s *ClassService) RepoDB() *pgx.Conn {
+func (s *ClassService) RepoDB() repository.DBTX {
 	return s.db
 }
 
+// Create создаёт класс в указанном учебном году, по умолчанию — в текущем
 func (s *ClassService) Create(ctx context.Context, c *models.Class) error {
-	return s.repo.Create(ctx, c)
+	return s.tx.WithTx(ctx, func(q repository.DBTX) error {
+		years := repository.NewAcademicYearRepository(q)
+		if c.AcademicYearID == 0 {
+			cur, err := years.GetCurrent(ctx)
+			if err != nil {
+				return err
+			}
+			c.AcademicYearID = cur.ID
+		} else if _, err := years.GetByID(ctx, c.AcademicYearID); err != nil {
+			return err
+		}
+		if c.ClassTeacherID != nil {
+			if err := checkStaffInSchool(ctx, q, *c.ClassTeacherID, c.SchoolID); err != nil {
+				return err
+			}
+		}
+
+		if err := repository.NewClassRepository(q).Create(ctx, c); err != nil {
+			return err
+		}
+		return audit.Record(ctx, q, audit.Change{
+			SchoolID: &c.SchoolID, EntityType: audit.EntityClass, EntityID: c.ID,
+			Action: audit.ActionCreate, After: c,
+		})
+	})
 }
 
-func (s *ClassService) GetAll(ctx context.Context) ([]models.Class, error) {
-	return s.repo.GetAll(ctx)
+// GetAll — классы района за учебный год; yearID == nil — текущий
+func (s *ClassService) GetAll(ctx context.Context, yearID *int) ([]models.Class, error) {
+	return s.repo.GetAll(ctx, yearID)
 }
 
-func (s *ClassService) GetBySchool(ctx context.Context, schoolID int) ([]models.Class, error) {
-	return s.repo.GetBySchool(ctx, schoolID)
+// GetBySchool — классы школы за учебный год; yearID == nil — текущий
+func (s *ClassService) GetBySchool(ctx context.Context, schoolID int, yearID *int) ([]models.Class, error) {
+	return s.repo.GetBySchool(ctx, schoolID, yearID)
 }
 
-func (s *ClassService) Update(ctx context.Context, id int, c *models.Class, role string) (bool, error) {
-	rows, err := s.repo.Update(ctx, id, c, role)
-	if err != nil {
-		return false, err
-	}
-	return rows > 0, nil
+// Update обновляет класс; schoolID != nil — только класс этой школы.
+// setTeacher == false — классный руководитель остаётся прежним.
+func (s *ClassService) Update(ctx context.Context, id int, c *models.Class, setTeacher bool, schoolID *int) (bool, error) {
+	updated := false
+	err := s.tx.WithTx(ctx, func(q repository.DBTX) error {
+		repo := repository.NewClassRepository(q)
+		old, err := repo.GetByID(ctx, id)
+		if err != nil {
+			if errors.Is(err, repository.ErrClassNotFound) {
+				return nil
+			}
+			return err
+		}
+		if schoolID != nil && old.SchoolID != *schoolID {
+			return nil
+		}
+		if !setTeacher {
+			c.ClassTeacherID = old.ClassTeacherID
+		} else if c.ClassTeacherID != nil {
+			if err := checkStaffInSchool(ctx, q, *c.ClassTeacherID, old.SchoolID); err != nil {
+				return err
+			}
+		}
+		rows, err := repo.Update(ctx, id, c, schoolID)
+		if err != nil || rows == 0 {
+			return err
+		}
+		updated = true
+
+		fresh, err := repo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		return audit.Record(ctx, q, audit.Change{
+			SchoolID: &old.SchoolID, EntityType: audit.EntityClass, EntityID: id,
+			Action: audit.ActionUpdate, Before: old, After: fresh,
+		})
+	})
+	return updated, err
 }
 
-func (s *ClassService) Delete(ctx context.Context, id, schoolID int) error {
-	return s.repo.Delete(ctx, id, schoolID)
+// Delete переносит класс в корзину вместе с учениками, записывает их выбытие и пересчитывает счётчик школы.
+// schoolID == nil — любой класс (район), иначе только класс этой школы; false — не найден или чужой.
+func (s *ClassService) Delete(ctx context.Context, id int, schoolID *int) (bool, error) {
+	deleted := false
+	err := s.tx.WithTx(ctx, func(q repository.DBTX) error {
+		repo := repository.NewClassRepository(q)
+		c, err := repo.GetByID(ctx, id)
+		if err != nil {
+			if errors.Is(err, repository.ErrClassNotFound) {
+				return nil
+			}
+			return err
+		}
+		if schoolID != nil && c.SchoolID != *schoolID {
+			return nil
+		}
+		students, err := repo.SoftDelete(ctx, id, c.SchoolID, actorID(ctx))
+		if err != nil {
+			return err
+		}
+		deleted = true
+		if err := recordTrashMovements(ctx, q, models.MovementDeparture, students...); err != nil {
+			return err
+		}
+		if err := repository.NewSchoolRepository(q).RefreshStudentCount(ctx, c.SchoolID); err != nil {
+			return err
+		}
+		return audit.Record(ctx, q, audit.Change{
+			SchoolID: &c.SchoolID, EntityType: audit.EntityClass, EntityID: id,
+			Action: audit.ActionDelete, Before: c,
+		})
+	})
+	return deleted, err
 }
 
 func (s *ClassService) GetByID(ctx context.Context, id int) (*models.Class, error) {
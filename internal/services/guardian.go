@@ -0,0 +1,156 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"eduBase/internal/audit"
+	"eduBase/internal/models"
+	"eduBase/internal/repository"
+)
+
+var ErrInvalidGuardian = errors.New("full_name and relation (mother, father, guardian, trustee, other) required")
+
+var guardianRelations = map[string]bool{
+	"mother": true, "father": true, "guardian": true, "trustee": true, "other": true,
+}
+
+// GuardianService — родители и законные представители учеников.
+// Доступ к самому ученику проверяет обработчик, как и для остальных данных ученика.
+type GuardianService struct {
+	repo *repository.GuardianRepository
+	tx   *repository.TxManager
+}
+
+func NewGuardianService(repo *repository.GuardianRepository, tx *repository.TxManager) *GuardianService {
+	return &GuardianService{repo: repo, tx: tx}
+}
+
+// guardianLink — связь представителя с учеником в журнале изменений
+type guardianLink struct {
+	StudentID int  `json:"student_id"`
+	IsPrimary bool `json:"is_primary"`
+}
+
+func validateGuardian(g *models.Guardian) error {
+	g.FullName = strings.TrimSpace(g.FullName)
+	if g.FullName == "" || !guardianRelations[g.Relation] {
+		return ErrInvalidGuardian
+	}
+	if g.Phones == nil {
+		g.Phones = []string{}
+	}
+	return nil
+}
+
+func (s *GuardianService) GetByStudent(ctx context.Context, studentID int) ([]models.Guardian, error) {
+	return s.repo.GetByStudent(ctx, studentID)
+}
+
+// GetByStudents — представители для выгрузки: ID ученика → список
+func (s *GuardianService) GetByStudents(ctx context.Context, studentIDs []int) (map[int][]models.Guardian, error) {
+	return s.repo.GetByStudents(ctx, studentIDs)
+}
+
+// Add добавляет представителя ученику st. g.ID != 0 — привязать существующего (например, родителя
+// брата или сестры); schoolID != nil — только представителя, уже связанного с учеником этой школы.
+func (s *GuardianService) Add(ctx context.Context, st *models.Student, g *models.Guardian, schoolID *int) error {
+	return s.tx.WithTx(ctx, func(q repository.DBTX) error {
+		repo := repository.NewGuardianRepository(q)
+		isPrimary := g.IsPrimary
+		if g.ID != 0 {
+			ok, err := repo.Exists(ctx, g.ID, schoolID)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return repository.ErrGuardianNotFound
+			}
+		} else {
+			if err := validateGuardian(g); err != nil {
+				return err
+			}
+			if err := repo.Create(ctx, g); err != nil {
+				return err
+			}
+			if err := audit.Record(ctx, q, audit.Change{
+				SchoolID: &st.SchoolID, EntityType: audit.EntityGuardian, EntityID: g.ID,
+				Action: audit.ActionCreate, After: g,
+			}); err != nil {
+				return err
+			}
+		}
+
+		if err := repo.Link(ctx, st.ID, g.ID, isPrimary); err != nil {
+			return err
+		}
+		fresh, err := repo.GetForStudent(ctx, st.ID, g.ID)
+		if err != nil {
+			return err
+		}
+		*g = *fresh
+		return audit.Record(ctx, q, audit.Change{
+			SchoolID: &st.SchoolID, EntityType: audit.EntityGuardianLink, EntityID: g.ID,
+			Action: audit.ActionCreate, After: guardianLink{StudentID: st.ID, IsPrimary: fresh.IsPrimary},
+		})
+	})
+}
+
+// Update меняет данные представителя (они общие для всех его детей) и отметку основного контакта у st
+func (s *GuardianService) Update(ctx context.Context, st *models.Student, id int, g *models.Guardian) error {
+	if err := validateGuardian(g); err != nil {
+		return err
+	}
+	return s.tx.WithTx(ctx, func(q repository.DBTX) error {
+		repo := repository.NewGuardianRepository(q)
+		old, err := repo.GetForStudent(ctx, st.ID, id)
+		if err != nil {
+			return err
+		}
+		if err := repo.Update(ctx, id, g); err != nil {
+			return err
+		}
+		if err := repo.Link(ctx, st.ID, id, g.IsPrimary); err != nil {
+			return err
+		}
+		fresh, err := repo.GetForStudent(ctx, st.ID, id)
+		if err != nil {
+			return err
+		}
+		*g = *fresh
+		return audit.Record(ctx, q, audit.Change{
+			SchoolID: &st.SchoolID, EntityType: audit.EntityGuardian, EntityID: id,
+			Action: audit.ActionUpdate, Before: old, After: fresh,
+		})
+	})
+}
+
+// Remove отвязывает представителя от ученика; представитель без детей удаляется.
+// В журнал попадают и отвязка, и удаление представителя.
+func (s *GuardianService) Remove(ctx context.Context, st *models.Student, id int) error {
+	return s.tx.WithTx(ctx, func(q repository.DBTX) error {
+		repo := repository.NewGuardianRepository(q)
+		old, err := repo.GetForStudent(ctx, st.ID, id)
+		if err != nil {
+			return err
+		}
+		if _, err := repo.Unlink(ctx, st.ID, id); err != nil {
+			return err
+		}
+		if err := audit.Record(ctx, q, audit.Change{
+			SchoolID: &st.SchoolID, EntityType: audit.EntityGuardianLink, EntityID: id,
+			Action: audit.ActionDelete, Before: guardianLink{StudentID: st.ID, IsPrimary: old.IsPrimary},
+		}); err != nil {
+			return err
+		}
+		deleted, err := repo.DeleteIfOrphan(ctx, id)
+		if err != nil || !deleted {
+			return err
+		}
+		return audit.Record(ctx, q, audit.Change{
+			SchoolID: &st.SchoolID, EntityType: audit.EntityGuardian, EntityID: id,
+			Action: audit.ActionDelete, Before: old,
+		})
+	})
+}
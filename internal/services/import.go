@@ -0,0 +1,69 @@
+package services
+
+import (
+	"errors"
+	"strings"
+
+	"eduBase/internal/models"
+)
+
+var (
+	ErrImportEmpty    = errors.New("file has no data rows")
+	ErrImportTooLarge = errors.New("file has too many rows")
+)
+
+// MaxImportRows — наибольшее число строк в одном файле загрузки
+const MaxImportRows = 2000
+
+// importColumns сопоставляет колонки файла с заголовком шаблона (без учёта регистра, «*» и пробелов по краям).
+// Возвращает номер колонки по имени из шаблона; отсутствующие обязательные колонки — ошибкой строки 1.
+func importColumns(header, columns []string) (map[string]int, []models.ImportError) {
+	norm := func(s string) string {
+		return strings.ToLower(strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(s), "*")))
+	}
+
+	byName := make(map[string]int, len(header))
+	for i, h := range header {
+		byName[norm(h)] = i
+	}
+	res := make(map[string]int, len(columns))
+	var errs []models.ImportError
+	for _, c := range columns {
+		if i, ok := byName[norm(c)]; ok {
+			res[c] = i
+		} else if strings.HasSuffix(c, "*") {
+			errs = append(errs, models.ImportError{Row: 1, Column: c, Message: "required column is missing"})
+		}
+	}
+	return res, errs
+}
+
+// importRow — доступ к ячейкам строки по колонкам шаблона
+type importRow struct {
+	cells []string
+	cols  map[string]int
+}
+
+func (r importRow) get(col string) string {
+	i, ok := r.cols[col]
+	if !ok || i >= len(r.cells) {
+		return ""
+	}
+	return strings.TrimSpace(r.cells[i])
+}
+
+func (r importRow) opt(col string) *string {
+	if v := r.get(col); v != "" {
+		return &v
+	}
+	return nil
+}
+
+func (r importRow) empty() bool {
+	for _, c := range r.cells {
+		if strings.TrimSpace(c) != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,348 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	"eduBase/internal/audit"
+	"eduBase/internal/models"
+	"eduBase/internal/repository"
+)
+
+var (
+	ErrInvalidMark     = errors.New("mark must be between 2 and 5")
+	ErrInvalidPeriod   = errors.New("period must be one of: q1, q2, q3, q4, h1, h2, year")
+	ErrMarkStudent     = errors.New("student is not an active student of this class")
+	ErrMarkFutureDate  = errors.New("marks cannot be given for a future date")
+	ErrMarkOutsideYear = errors.New("date is outside the current academic year")
+	ErrMarkDuplicate   = errors.New("student is listed more than once")
+)
+
+var markPeriods = map[string]bool{
+	models.PeriodQ1: true, models.PeriodQ2: true, models.PeriodQ3: true, models.PeriodQ4: true,
+	models.PeriodH1: true, models.PeriodH2: true, models.PeriodYear: true,
+}
+
+// ValidPeriod — допустимый период итоговых отметок
+func ValidPeriod(p string) bool { return markPeriods[p] }
+
+// MarkService — журнал отметок и итоговые отметки
+type MarkService struct {
+	repo      *repository.MarkRepository
+	classRepo *repository.ClassRepository
+	tx        *repository.TxManager
+}
+
+func NewMarkService(repo *repository.MarkRepository, cr *repository.ClassRepository, tx *repository.TxManager) *MarkService {
+	return &MarkService{repo: repo, classRepo: cr, tx: tx}
+}
+
+func (s *MarkService) GetClass(ctx context.Context, classID int) (*models.Class, error) {
+	return s.classRepo.GetByID(ctx, classID)
+}
+
+// Journal — журнал класса по предмету: отметки за период [from, to] и все итоговые отметки
+func (s *MarkService) Journal(ctx context.Context, classID, subjectID int, from, to time.Time) (*models.ClassJournal, error) {
+	return journal(ctx, s.repo, classID, subjectID, from, to)
+}
+
+func journal(ctx context.Context, repo *repository.MarkRepository, classID, subjectID int, from, to time.Time) (*models.ClassJournal, error) {
+	students, err := repo.JournalStudents(ctx, classID, subjectID)
+	if err != nil {
+		return nil, err
+	}
+	marks, err := repo.GetByClass(ctx, classID, subjectID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	terms, err := repo.GetTermByClass(ctx, classID, subjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	idx := make(map[int]int, len(students))
+	for i, st := range students {
+		idx[st.StudentID] = i
+	}
+	for _, m := range marks {
+		if i, ok := idx[m.StudentID]; ok {
+			students[i].Marks = append(students[i].Marks, m)
+		}
+	}
+	for _, t := range terms {
+		if i, ok := idx[t.StudentID]; ok {
+			students[i].TermMarks = append(students[i].TermMarks, t)
+		}
+	}
+	for i := range students {
+		students[i].Average = average(students[i].Marks)
+	}
+
+	return &models.ClassJournal{
+		ClassID: classID, SubjectID: subjectID,
+		From: from.Format(time.DateOnly), To: to.Format(time.DateOnly), Students: students,
+	}, nil
+}
+
+// average — средний балл с точностью до сотых; nil, если отметок нет
+func average(marks []models.Mark) *float64 {
+	if len(marks) == 0 {
+		return nil
+	}
+	sum := 0
+	for _, m := range marks {
+		sum += m.Mark
+	}
+	avg := math.Round(float64(sum)*100/float64(len(marks))) / 100
+	return &avg
+}
+
+// activeStudents — обучающиеся ученики класса
+func activeStudents(ctx context.Context, q repository.DBTX, classID int) (map[int]bool, error) {
+	list, err := repository.NewStudentRepository(q).ListByClass(ctx, classID)
+	if err != nil {
+		return nil, err
+	}
+	res := make(map[int]bool, len(list))
+	for _, st := range list {
+		res[st.ID] = true
+	}
+	return res, nil
+}
+
+// markChange — отметка ученика в журнале изменений (entity_id — ID ученика)
+type markChange struct {
+	ClassID   int     `json:"class_id"`
+	SubjectID int     `json:"subject_id"`
+	Date      string  `json:"date,omitempty"`   // текущая отметка
+	Period    string  `json:"period,omitempty"` // итоговая отметка
+	Mark      int     `json:"mark"`
+	Note      *string `json:"note,omitempty"`
+}
+
+// recordMark пишет в журнал постановку (create), исправление (update) или снятие (delete) отметки.
+// keep — поля, по которым отметку можно найти; они остаются в записи, даже если не изменились.
+func recordMark(ctx context.Context, q repository.DBTX, schoolID int, entity string, studentID int, before, after *markChange, keep ...string) error {
+	ch := audit.Change{SchoolID: &schoolID, EntityType: entity, EntityID: studentID, Keep: keep}
+	switch {
+	case before == nil && after == nil:
+		return nil // снимали отметку, которой не было
+	case before == nil:
+		ch.Action, ch.After = audit.ActionCreate, after
+	case after == nil:
+		ch.Action, ch.Before = audit.ActionDelete, before
+	default:
+		ch.Action, ch.Before, ch.After = audit.ActionUpdate, before, after
+	}
+	return audit.Record(ctx, q, ch)
+}
+
+func markStudentIDs(entries []models.MarkEntry) []int {
+	ids := make([]int, 0, len(entries))
+	for _, e := range entries {
+		ids = append(ids, e.StudentID)
+	}
+	return ids
+}
+
+// Mark сохраняет отметки класса по предмету за дату одной транзакцией: либо все, либо ни одной.
+// Отметка без mark снимается. Ставить отметки можно только обучающимся ученикам класса текущего учебного года
+// и только за даты этого года.
+func (s *MarkService) Mark(ctx context.Context, c *models.Class, subjectID int, date time.Time, entries []models.MarkEntry) (*models.ClassJournal, error) {
+	if date.Format(time.DateOnly) > time.Now().Format(time.DateOnly) {
+		return nil, ErrMarkFutureDate
+	}
+	if err := checkMarkEntries(entries); err != nil {
+		return nil, err
+	}
+
+	var res *models.ClassJournal
+	err := s.tx.WithTx(ctx, func(q repository.DBTX) error {
+		year, err := currentClassYear(ctx, q, c)
+		if err != nil {
+			return err
+		}
+		if !inYear(year, date) {
+			return ErrMarkOutsideYear
+		}
+		if _, err := repository.NewSubjectRepository(q).GetByID(ctx, subjectID); err != nil {
+			return err
+		}
+		inClass, err := activeStudents(ctx, q, c.ID)
+		if err != nil {
+			return err
+		}
+
+		repo := repository.NewMarkRepository(q)
+		current, err := repo.GetForStudents(ctx, markStudentIDs(entries), subjectID, date)
+		if err != nil {
+			return err
+		}
+		old := make(map[int]*markChange, len(current))
+		for _, m := range current {
+			old[m.StudentID] = &markChange{ClassID: c.ID, SubjectID: subjectID, Date: m.Date, Mark: m.Mark, Note: m.Note}
+		}
+
+		markedBy := actorID(ctx)
+		for _, e := range entries {
+			if !inClass[e.StudentID] {
+				return ErrMarkStudent
+			}
+			var after *markChange
+			if e.Mark == nil {
+				err = repo.Delete(ctx, e.StudentID, subjectID, date)
+			} else {
+				err = repo.Upsert(ctx, e.StudentID, c.ID, c.SchoolID, subjectID, date, *e.Mark, e.Note, markedBy)
+				after = &markChange{ClassID: c.ID, SubjectID: subjectID, Date: date.Format(time.DateOnly), Mark: *e.Mark, Note: e.Note}
+			}
+			if err != nil {
+				return err
+			}
+			if err := recordMark(ctx, q, c.SchoolID, audit.EntityMark, e.StudentID, old[e.StudentID], after,
+				"class_id", "subject_id", "date"); err != nil {
+				return err
+			}
+		}
+
+		res, err = journal(ctx, repo, c.ID, subjectID, date, date)
+		return err
+	})
+	return res, err
+}
+
+// checkMarkEntries — отметки от 2 до 5, каждый ученик — не больше одного раза
+func checkMarkEntries(entries []models.MarkEntry) error {
+	seen := make(map[int]bool, len(entries))
+	for _, e := range entries {
+		if e.Mark != nil && (*e.Mark < 2 || *e.Mark > 5) {
+			return ErrInvalidMark
+		}
+		if seen[e.StudentID] {
+			return ErrMarkDuplicate
+		}
+		seen[e.StudentID] = true
+	}
+	return nil
+}
+
+// MarkTerm сохраняет итоговые отметки класса по предмету за период одной транзакцией.
+// Отметка без mark снимается.
+func (s *MarkService) MarkTerm(ctx context.Context, c *models.Class, subjectID int, period string, entries []models.MarkEntry) ([]models.TermMark, error) {
+	if !markPeriods[period] {
+		return nil, ErrInvalidPeriod
+	}
+	if err := checkMarkEntries(entries); err != nil {
+		return nil, err
+	}
+
+	var res []models.TermMark
+	err := s.tx.WithTx(ctx, func(q repository.DBTX) error {
+		if err := checkCurrentYear(ctx, q, c); err != nil {
+			return err
+		}
+		if _, err := repository.NewSubjectRepository(q).GetByID(ctx, subjectID); err != nil {
+			return err
+		}
+		inClass, err := activeStudents(ctx, q, c.ID)
+		if err != nil {
+			return err
+		}
+
+		repo := repository.NewMarkRepository(q)
+		current, err := repo.GetTermForStudents(ctx, markStudentIDs(entries), subjectID, c.AcademicYearID, period)
+		if err != nil {
+			return err
+		}
+		old := make(map[int]*markChange, len(current))
+		for _, t := range current {
+			old[t.StudentID] = &markChange{ClassID: t.ClassID, SubjectID: subjectID, Period: period, Mark: t.Mark}
+		}
+
+		markedBy := actorID(ctx)
+		for _, e := range entries {
+			if !inClass[e.StudentID] {
+				return ErrMarkStudent
+			}
+			var after *markChange
+			if e.Mark == nil {
+				err = repo.DeleteTerm(ctx, e.StudentID, subjectID, c.AcademicYearID, period)
+			} else {
+				err = repo.UpsertTerm(ctx, e.StudentID, c, subjectID, period, *e.Mark, markedBy)
+				after = &markChange{ClassID: c.ID, SubjectID: subjectID, Period: period, Mark: *e.Mark}
+			}
+			if err != nil {
+				return err
+			}
+			if err := recordMark(ctx, q, c.SchoolID, audit.EntityTermMark, e.StudentID, old[e.StudentID], after,
+				"class_id", "subject_id", "period"); err != nil {
+				return err
+			}
+		}
+
+		res, err = repo.GetTermByClass(ctx, c.ID, subjectID)
+		return err
+	})
+	return res, err
+}
+
+// ByStudent — отметки и итоговые отметки ученика по предметам за учебный год; yearID == nil — текущий
+func (s *MarkService) ByStudent(ctx context.Context, studentID int, yearID *int) (*models.StudentMarks, error) {
+	years := repository.NewAcademicYearRepository(s.repo.DB())
+	var year *models.AcademicYear
+	var err error
+	if yearID == nil {
+		year, err = years.GetCurrent(ctx)
+	} else {
+		year, err = years.GetByID(ctx, *yearID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	marks, err := s.repo.GetByStudent(ctx, studentID, year.ID)
+	if err != nil {
+		return nil, err
+	}
+	terms, err := s.repo.GetTermByStudent(ctx, studentID, year.ID)
+	if err != nil {
+		return nil, err
+	}
+	subjects, err := repository.NewSubjectRepository(s.repo.DB()).GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// предметы — в порядке справочника, только те, по которым есть отметки
+	bySubject := map[int]*models.SubjectMarks{}
+	for _, m := range marks {
+		bySubject[m.SubjectID] = nil
+	}
+	for _, t := range terms {
+		bySubject[t.SubjectID] = nil
+	}
+	res := &models.StudentMarks{StudentID: studentID, AcademicYearID: year.ID, Subjects: []models.SubjectMarks{}}
+	for _, sb := range subjects {
+		if _, ok := bySubject[sb.ID]; ok {
+			res.Subjects = append(res.Subjects, models.SubjectMarks{
+				SubjectID: sb.ID, SubjectName: sb.Name, Marks: []models.Mark{}, TermMarks: []models.TermMark{},
+			})
+		}
+	}
+	for i := range res.Subjects {
+		bySubject[res.Subjects[i].SubjectID] = &res.Subjects[i]
+	}
+	for _, m := range marks {
+		sm := bySubject[m.SubjectID]
+		sm.Marks = append(sm.Marks, m)
+	}
+	for _, t := range terms {
+		sm := bySubject[t.SubjectID]
+		sm.TermMarks = append(sm.TermMarks, t)
+	}
+	for i := range res.Subjects {
+		res.Subjects[i].Average = average(res.Subjects[i].Marks)
+	}
+	return res, nil
+}
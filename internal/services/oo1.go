@@ -0,0 +1,221 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"eduBase/internal/models"
+	"eduBase/internal/repository"
+)
+
+// Параллели, которые всегда есть в отчёте ОО-1
+const oo1FirstGrade, oo1LastGrade = 1, 11
+
+// Границы возраста в разделе ОО-1 о возрастном составе: младше и старше — одной строкой
+const oo1MinAge, oo1MaxAge = 5, 18
+
+// oo1Experience — интервалы педагогического стажа ОО-1: стаж меньше upTo лет (последний — без верхней границы)
+var oo1Experience = []struct {
+	label string
+	upTo  int
+}{
+	{"до 3 лет", 3}, {"от 3 до 5 лет", 5}, {"от 5 до 10 лет", 10},
+	{"от 10 до 15 лет", 15}, {"от 15 до 20 лет", 20}, {"20 лет и более", 0},
+}
+
+const oo1NotSpecified = "не указано"
+
+// ageLabel — строка раздела о возрасте; nil — дата рождения не указана
+func ageLabel(age *int) string {
+	switch {
+	case age == nil:
+		return oo1NotSpecified
+	case *age <= oo1MinAge:
+		return strconv.Itoa(oo1MinAge) + " лет и младше"
+	case *age >= oo1MaxAge:
+		return strconv.Itoa(oo1MaxAge) + " лет и старше"
+	}
+	return strconv.Itoa(*age) + " лет"
+}
+
+// experienceBucket — индекс интервала стажа в oo1Experience; стаж не указан — индекс за последним интервалом
+func experienceBucket(years *int) int {
+	if years == nil {
+		return len(oo1Experience)
+	}
+	for i, e := range oo1Experience[:len(oo1Experience)-1] {
+		if *years < e.upTo {
+			return i
+		}
+	}
+	return len(oo1Experience) - 1
+}
+
+// GetOO1 считает разделы формы ОО-1 на дату: классы и обучающиеся по параллелям, полу и возрасту,
+// работники по образованию, категории и стажу. schoolID == nil — весь район.
+func (s *StatsService) GetOO1(ctx context.Context, schoolID *int, date time.Time) (*models.OO1Report, error) {
+	year, err := repository.NewAcademicYearRepository(s.repo.DB()).GetByDate(ctx, date)
+	if err != nil {
+		return nil, err
+	}
+	classes, err := s.repo.GetOO1Classes(ctx, schoolID, year.ID, date)
+	if err != nil {
+		return nil, err
+	}
+	// возраст — на 1 января учебного года, в который попадает дата отчёта
+	ageDate := time.Date(year.StartDate.Year()+1, 1, 1, 0, 0, 0, 0, time.UTC)
+	students, err := s.repo.GetOO1Students(ctx, schoolID, date, ageDate)
+	if err != nil {
+		return nil, err
+	}
+	staff, err := s.repo.GetOO1Staff(ctx, schoolID, year.ID, date)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &models.OO1Report{
+		Date:           date.Format(time.DateOnly),
+		AgeDate:        ageDate.Format(time.DateOnly),
+		AcademicYearID: year.ID,
+		SchoolID:       schoolID,
+	}
+	if schoolID != nil {
+		school, err := s.schoolRepo.GetByID(ctx, *schoolID)
+		if err != nil {
+			return nil, err
+		}
+		res.SchoolName = school.Name
+	}
+
+	// параллели: всегда 1–11 и все, что встречаются в данных
+	grades := map[int]*models.OO1GradeRow{}
+	grade := func(g int) *models.OO1GradeRow {
+		if grades[g] == nil {
+			grades[g] = &models.OO1GradeRow{Grade: g}
+		}
+		return grades[g]
+	}
+	for g := oo1FirstGrade; g <= oo1LastGrade; g++ {
+		grade(g)
+	}
+	for g, n := range classes {
+		grade(g).Classes = n
+		res.Classes += n
+	}
+
+	ages := map[string]*models.OO1AgeRow{}
+	for a := oo1MinAge; a <= oo1MaxAge; a++ {
+		label := ageLabel(&a)
+		ages[label] = &models.OO1AgeRow{Age: label, ByGrade: map[int]int{}}
+	}
+	for _, st := range students {
+		girls := 0
+		if st.Gender != nil && *st.Gender == "female" {
+			girls = st.Count
+		}
+		gr := grade(st.Grade)
+		gr.Students += st.Count
+		gr.Girls += girls
+		res.Students += st.Count
+		res.Girls += girls
+
+		label := ageLabel(st.Age)
+		if ages[label] == nil {
+			ages[label] = &models.OO1AgeRow{Age: label, ByGrade: map[int]int{}}
+		}
+		ages[label].Students += st.Count
+		ages[label].Girls += girls
+		ages[label].ByGrade[st.Grade] += st.Count
+	}
+
+	res.Grades = make([]models.OO1GradeRow, 0, len(grades))
+	for _, g := range grades {
+		res.Grades = append(res.Grades, *g)
+	}
+	sort.Slice(res.Grades, func(i, j int) bool { return res.Grades[i].Grade < res.Grades[j].Grade })
+
+	res.Ages = make([]models.OO1AgeRow, 0, len(ages))
+	for a := oo1MinAge; a <= oo1MaxAge; a++ {
+		res.Ages = append(res.Ages, *ages[ageLabel(&a)])
+	}
+	if row := ages[oo1NotSpecified]; row != nil {
+		res.Ages = append(res.Ages, *row)
+	}
+
+	experience := make([]models.OO1StaffRow, len(oo1Experience)+1)
+	for i, e := range oo1Experience {
+		experience[i].Label = e.label
+	}
+	experience[len(oo1Experience)].Label = oo1NotSpecified
+	education, categories := &oo1Values{}, &oo1Values{}
+	for _, st := range staff {
+		teachers := 0
+		if st.Teacher {
+			teachers = st.Count
+		}
+		res.Staff += st.Count
+		res.Teachers += teachers
+		education.add(staffEducation(st.Education), st.Count, teachers)
+		categories.add(staffCategory(st.Category), st.Count, teachers)
+
+		i := experienceBucket(st.PedExperience)
+		experience[i].Staff += st.Count
+		experience[i].Teachers += teachers
+	}
+	if last := experience[len(experience)-1]; last.Staff == 0 {
+		experience = experience[:len(experience)-1]
+	}
+	res.Education = education.rows()
+	res.Categories = categories.rows()
+	res.Experience = experience
+	return res, nil
+}
+
+// oo1Values — строки раздела о работниках по значению свободного поля (образование, категория):
+// значения сравниваются без учёта регистра, пустое — «не указано»
+type oo1Values struct {
+	order []string
+	byKey map[string]*models.OO1StaffRow
+}
+
+func (v *oo1Values) add(value string, staff, teachers int) {
+	if v.byKey == nil {
+		v.byKey = map[string]*models.OO1StaffRow{}
+	}
+	value = strings.Join(strings.Fields(value), " ")
+	if value == "" {
+		value = oo1NotSpecified
+	}
+	key := strings.ToLower(value)
+	row := v.byKey[key]
+	if row == nil {
+		row = &models.OO1StaffRow{Label: value}
+		v.byKey[key] = row
+		v.order = append(v.order, key)
+	}
+	row.Staff += staff
+	row.Teachers += teachers
+}
+
+// rows — строки по убыванию числа работников, «не указано» — последней
+func (v *oo1Values) rows() []models.OO1StaffRow {
+	list := make([]models.OO1StaffRow, 0, len(v.order))
+	for _, key := range v.order {
+		if key != oo1NotSpecified {
+			list = append(list, *v.byKey[key])
+		}
+	}
+	sort.SliceStable(list, func(i, j int) bool {
+		if list[i].Staff != list[j].Staff {
+			return list[i].Staff > list[j].Staff
+		}
+		return list[i].Label < list[j].Label
+	})
+	if row := v.byKey[oo1NotSpecified]; row != nil {
+		list = append(list, *row)
+	}
+	return list
+}
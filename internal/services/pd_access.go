@@ -0,0 +1,63 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+
+	"eduBase/internal/audit"
+	"eduBase/internal/models"
+	"eduBase/internal/repository"
+)
+
+// Виды доступа к персональным данным учеников
+const (
+	PDActionView   = "view"
+	PDActionList   = "list"
+	PDActionExport = "export"
+)
+
+// PDAccessService — журнал доступа к персональным данным учеников (152-ФЗ)
+type PDAccessService struct {
+	repo *repository.PDAccessRepository
+}
+
+func NewPDAccessService(repo *repository.PDAccessRepository) *PDAccessService {
+	return &PDAccessService{repo: repo}
+}
+
+// Log фиксирует, кто и каких учеников получил. Вызывается до отдачи данных:
+// если записать факт доступа не удалось, данные не отдаются.
+func (s *PDAccessService) Log(ctx context.Context, action string, studentIDs []int, filter any) error {
+	e := &models.PDAccessEntry{Action: action, StudentIDs: studentIDs}
+	if e.StudentIDs == nil {
+		e.StudentIDs = []int{}
+	}
+	if filter != nil {
+		raw, err := json.Marshal(filter)
+		if err != nil {
+			return err
+		}
+		e.Filter = raw
+	}
+	if a, ok := audit.ActorFrom(ctx); ok {
+		e.UserID = &a.UserID
+		e.Role = &a.Role
+		if a.IP != "" {
+			e.IP = &a.IP
+		}
+	}
+	return s.repo.Insert(ctx, e)
+}
+
+func (s *PDAccessService) GetAll(ctx context.Context, f repository.PDAccessFilter) ([]models.PDAccessEntry, error) {
+	if f.Limit <= 0 {
+		f.Limit = defaultAuditLimit
+	}
+	if f.Limit > maxAuditLimit {
+		f.Limit = maxAuditLimit
+	}
+	if f.Offset < 0 {
+		f.Offset = 0
+	}
+	return s.repo.GetAll(ctx, f)
+}
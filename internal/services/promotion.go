@@ -0,0 +1,359 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"eduBase/internal/audit"
+	"eduBase/internal/models"
+	"eduBase/internal/repository"
+)
+
+var (
+	ErrInvalidTargetYear     = errors.New("target academic year must start after the current one")
+	ErrAlreadyPromoted       = errors.New("students are already enrolled in the target academic year")
+	ErrPromotionStudent      = errors.New("repeaters and leavers must be active students of the promoted classes")
+	ErrPromotionStudentTwice = errors.New("student cannot be both a repeater and a leaver")
+)
+
+// PromotionService — перевод классов на следующий учебный год
+type PromotionService struct {
+	tx *repository.TxManager
+}
+
+func NewPromotionService(tx *repository.TxManager) *PromotionService {
+	return &PromotionService{tx: tx}
+}
+
+// Promote переводит классы текущего года (школы req.SchoolID или всего района) в целевой год:
+// класс N «X» → класс N+1 «X», выпускной класс школы (11, а в основной школе — 9) выпускается.
+// Repeaters остаются в классе той же параллели, Leavers выбывают. Всё выполняется в одной транзакции;
+// при DryRun изменения только рассчитываются.
+func (s *PromotionService) Promote(ctx context.Context, req models.PromotionRequest) (*models.PromotionResult, error) {
+	var res *models.PromotionResult
+	err := s.tx.WithTx(ctx, func(q repository.DBTX) error {
+		years := repository.NewAcademicYearRepository(q)
+		cur, err := years.GetCurrent(ctx)
+		if err != nil {
+			return err
+		}
+		target, err := years.GetByID(ctx, req.TargetAcademicYearID)
+		if err != nil {
+			return err
+		}
+		if target.ID == cur.ID || !target.StartDate.After(cur.StartDate) {
+			return ErrInvalidTargetYear
+		}
+
+		p := &promotion{
+			ctx: ctx, q: q, dryRun: req.DryRun, yearID: target.ID,
+			targets: make(map[promotionKey]*models.Class),
+			res: &models.PromotionResult{
+				SourceAcademicYearID: cur.ID,
+				TargetAcademicYearID: target.ID,
+				DryRun:               req.DryRun,
+				Classes:              []models.PromotionClass{},
+				Students:             []models.PromotionStudent{},
+			},
+		}
+		if err := p.run(req, cur.ID); err != nil {
+			return err
+		}
+		res = p.res
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// promotionNote — примечание к записям книги движения, сделанным при переводе
+var promotionNote = "перевод на следующий учебный год"
+
+type promotionKey struct {
+	schoolID int
+	name     string
+}
+
+// promotion — состояние одного перевода внутри транзакции
+type promotion struct {
+	ctx     context.Context
+	q       repository.DBTX
+	dryRun  bool
+	yearID  int
+	targets map[promotionKey]*models.Class // классы целевого года: существующие и созданные
+	res     *models.PromotionResult
+}
+
+func (p *promotion) run(req models.PromotionRequest, curYearID int) error {
+	classRepo := repository.NewClassRepository(p.q)
+	studentRepo := repository.NewStudentRepository(p.q)
+
+	var classes, existing []models.Class
+	var err error
+	if req.SchoolID == nil {
+		classes, err = classRepo.GetAll(p.ctx, &curYearID)
+		if err == nil {
+			existing, err = classRepo.GetAll(p.ctx, &p.yearID)
+		}
+	} else {
+		classes, err = classRepo.GetBySchool(p.ctx, *req.SchoolID, &curYearID)
+		if err == nil {
+			existing, err = classRepo.GetBySchool(p.ctx, *req.SchoolID, &p.yearID)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	for i := range existing {
+		c := existing[i]
+		p.targets[promotionKey{c.SchoolID, c.Name}] = &c
+	}
+
+	// выпускной класс: 11, если в школе есть старшие классы, иначе 9
+	final := make(map[int]int)
+	for _, c := range classes {
+		if final[c.SchoolID] == 0 {
+			final[c.SchoolID] = 9
+		}
+		if c.Grade >= 10 {
+			final[c.SchoolID] = 11
+		}
+	}
+
+	students := make(map[int][]models.Student, len(classes))
+	var ids []int
+	active := make(map[int]bool)
+	for _, c := range classes {
+		list, err := studentRepo.ListByClass(p.ctx, c.ID)
+		if err != nil {
+			return err
+		}
+		students[c.ID] = list
+		for _, st := range list {
+			ids = append(ids, st.ID)
+			active[st.ID] = true
+		}
+	}
+
+	repeaters, leavers, err := promotionSets(req, active)
+	if err != nil {
+		return err
+	}
+	if len(ids) > 0 {
+		n, err := repository.NewEnrollmentRepository(p.q).CountInYear(p.ctx, p.yearID, ids)
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			return ErrAlreadyPromoted
+		}
+	}
+
+	schools := make(map[int]bool)
+	touched := make(map[int]bool) // классы, у которых меняется состав
+	for _, c := range classes {
+		schools[c.SchoolID] = true
+		touched[c.ID] = true
+
+		pc := models.PromotionClass{
+			SchoolID: c.SchoolID, FromClassID: c.ID, FromName: c.Name, FromGrade: c.Grade,
+			Action: models.PromotionGraduate,
+		}
+		var next *models.Class
+		if c.Grade < final[c.SchoolID] {
+			pc.Action = models.PromotionPromote
+			if next, err = p.target(c.SchoolID, nextClassName(c.Name, c.Grade+1), c.Grade+1, c.ClassTeacherID, &pc.Created); err != nil {
+				return err
+			}
+			pc.ToClassID, pc.ToName, pc.ToGrade = classIDPtr(next), next.Name, next.Grade
+			// классный руководитель переходит вместе с классом
+			if !p.dryRun {
+				if err := repository.NewUserRepository(p.q).ReassignClass(p.ctx, c.ID, next.ID); err != nil {
+					return err
+				}
+			}
+		}
+		p.res.Classes = append(p.res.Classes, pc)
+
+		for _, st := range students[c.ID] {
+			var err error
+			switch {
+			case leavers[st.ID]:
+				err = p.setStatus(st, models.StudentLeft, models.PromotionLeave)
+			case repeaters[st.ID]:
+				var same *models.Class
+				if same, err = p.target(c.SchoolID, c.Name, c.Grade, nil, nil); err == nil {
+					err = p.move(st, same, models.PromotionRepeat)
+				}
+			case next == nil:
+				err = p.setStatus(st, models.StudentGraduated, models.PromotionGraduate)
+			default:
+				err = p.move(st, next, models.PromotionPromote)
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if p.dryRun {
+		return nil
+	}
+	// счётчики: старые классы пустеют, новые заполняются; школьный — триггером и явным пересчётом
+	for _, c := range p.targets {
+		touched[c.ID] = true
+	}
+	for id := range touched {
+		if err := classRepo.RefreshStudentCount(p.ctx, id); err != nil {
+			return err
+		}
+	}
+	schoolRepo := repository.NewSchoolRepository(p.q)
+	for id := range schools {
+		if err := schoolRepo.RefreshStudentCount(p.ctx, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// promotionSets проверяет списки repeaters/leavers
+func promotionSets(req models.PromotionRequest, active map[int]bool) (map[int]bool, map[int]bool, error) {
+	repeaters := make(map[int]bool, len(req.Repeaters))
+	leavers := make(map[int]bool, len(req.Leavers))
+	for _, id := range req.Repeaters {
+		if !active[id] {
+			return nil, nil, ErrPromotionStudent
+		}
+		repeaters[id] = true
+	}
+	for _, id := range req.Leavers {
+		if !active[id] {
+			return nil, nil, ErrPromotionStudent
+		}
+		if repeaters[id] {
+			return nil, nil, ErrPromotionStudentTwice
+		}
+		leavers[id] = true
+	}
+	return repeaters, leavers, nil
+}
+
+// target — класс целевого года с таким названием; если его нет, создаётся (в dry_run — только планируется)
+// с классным руководителем teacherID
+func (p *promotion) target(schoolID int, name string, grade int, teacherID *int, created *bool) (*models.Class, error) {
+	key := promotionKey{schoolID, name}
+	if c, ok := p.targets[key]; ok {
+		return c, nil
+	}
+	c := &models.Class{Name: name, Grade: grade, SchoolID: schoolID, AcademicYearID: p.yearID, ClassTeacherID: teacherID}
+	if !p.dryRun {
+		if err := repository.NewClassRepository(p.q).Create(p.ctx, c); err != nil {
+			return nil, err
+		}
+		if err := audit.Record(p.ctx, p.q, audit.Change{
+			SchoolID: &c.SchoolID, EntityType: audit.EntityClass, EntityID: c.ID,
+			Action: audit.ActionCreate, After: c,
+		}); err != nil {
+			return nil, err
+		}
+	}
+	p.targets[key] = c
+	p.res.Totals.ClassesCreated++
+	if created != nil {
+		*created = true
+	}
+	return c, nil
+}
+
+// move переводит ученика в класс целевого года и зачисляет его в этот год
+func (p *promotion) move(st models.Student, c *models.Class, action string) error {
+	p.res.Students = append(p.res.Students, models.PromotionStudent{
+		StudentID: st.ID, FullName: st.FullName, SchoolID: st.SchoolID, FromClassID: st.ClassID,
+		Action: action, ToClassID: classIDPtr(c), ToClassName: c.Name,
+	})
+	if action == models.PromotionRepeat {
+		p.res.Totals.Repeated++
+	} else {
+		p.res.Totals.Promoted++
+	}
+	if p.dryRun {
+		return nil
+	}
+
+	if err := repository.NewStudentRepository(p.q).SetClass(p.ctx, st.ID, c.ID); err != nil {
+		return err
+	}
+	if err := repository.NewEnrollmentRepository(p.q).Upsert(p.ctx, st.ID, p.yearID, c.ID, c.SchoolID); err != nil {
+		return err
+	}
+	if err := recordMovement(p.ctx, p.q, &models.StudentMovement{
+		StudentID: st.ID, SchoolID: st.SchoolID, Kind: models.MovementClassChange,
+		FromClassID: &st.ClassID, ToClassID: &c.ID, Note: &promotionNote,
+	}); err != nil {
+		return err
+	}
+	after := st
+	after.ClassID, after.ClassName = c.ID, c.Name
+	return audit.Record(p.ctx, p.q, audit.Change{
+		SchoolID: &st.SchoolID, EntityType: audit.EntityStudent, EntityID: st.ID,
+		Action: audit.ActionUpdate, Before: st, After: after,
+	})
+}
+
+// setStatus — выпуск или выбытие: ученик остаётся в своём классе, но перестаёт считаться обучающимся
+func (p *promotion) setStatus(st models.Student, status, action string) error {
+	p.res.Students = append(p.res.Students, models.PromotionStudent{
+		StudentID: st.ID, FullName: st.FullName, SchoolID: st.SchoolID, FromClassID: st.ClassID,
+		Action: action,
+	})
+	if status == models.StudentGraduated {
+		p.res.Totals.Graduated++
+	} else {
+		p.res.Totals.Left++
+	}
+	if p.dryRun {
+		return nil
+	}
+
+	if err := repository.NewStudentRepository(p.q).SetStatus(p.ctx, st.ID, status); err != nil {
+		return err
+	}
+	// выпускник, как и выбывший, уходит из школы — в книге движения это выбытие
+	if err := recordMovement(p.ctx, p.q, &models.StudentMovement{
+		StudentID: st.ID, SchoolID: st.SchoolID, Kind: models.MovementDeparture,
+		FromSchoolID: &st.SchoolID, FromClassID: &st.ClassID, Note: &promotionNote,
+	}); err != nil {
+		return err
+	}
+	after := st
+	after.Status = status
+	return audit.Record(p.ctx, p.q, audit.Change{
+		SchoolID: &st.SchoolID, EntityType: audit.EntityStudent, EntityID: st.ID,
+		Action: audit.ActionUpdate, Before: st, After: after,
+	})
+}
+
+// classIDPtr — ID класса для ответа; у ещё не созданного класса (dry_run) ID нет
+func classIDPtr(c *models.Class) *int {
+	if c.ID == 0 {
+		return nil
+	}
+	id := c.ID
+	return &id
+}
+
+// nextClassName заменяет номер параллели в начале названия: «5А» → «6А», «10 Б» → «11 Б».
+// Название без номера не меняется.
+func nextClassName(name string, grade int) string {
+	rest := strings.TrimLeftFunc(name, unicode.IsDigit)
+	if rest == name {
+		return name
+	}
+	return strconv.Itoa(grade) + rest
+}
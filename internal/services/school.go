@@ -3,16 +3,18 @@ package services
 import (
 	"context"
 
+	"eduBase/internal/audit"
 	"eduBase/internal/models"
 	"eduBase/internal/repository"
 )
 
 type SchoolService struct {
 	repo *repository.SchoolRepository
+	tx   *repository.TxManager
 }
 
-func NewSchoolService(repo *repository.SchoolRepository) *SchoolService {
-	return &SchoolService{repo: repo}
+func NewSchoolService(repo *repository.SchoolRepository, tx *repository.TxManager) *SchoolService {
+	return &SchoolService{repo: repo, tx: tx}
 }
 
 func (s *SchoolService) GetAll(ctx context.Context) ([]models.School, error) {
@@ -24,9 +26,48 @@ func (s *SchoolService) GetByID(ctx context.Context, id int) (*models.School, er
 }
 
 func (s *SchoolService) Update(ctx context.Context, id int, req *models.School) error {
-	return s.repo.Update(ctx, id, req)
+	return s.tx.WithTx(ctx, func(q repository.DBTX) error {
+		repo := repository.NewSchoolRepository(q)
+		old, err := repo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := repo.Update(ctx, id, req); err != nil {
+			return err
+		}
+		fresh, err := repo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		return audit.Record(ctx, q, audit.Change{
+			SchoolID: &id, EntityType: audit.EntitySchool, EntityID: id,
+			Action: audit.ActionUpdate, Before: old, After: fresh,
+		})
+	})
 }
 
+// Delete переносит школу в корзину вместе с классами, сотрудниками и учениками, записывает выбытие учеников
+// и отзывает сессии всех её сотрудников.
 func (s *SchoolService) Delete(ctx context.Context, id int) error {
-	return s.repo.Delete(ctx, id)
+	return s.tx.WithTx(ctx, func(q repository.DBTX) error {
+		repo := repository.NewSchoolRepository(q)
+		school, err := repo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		students, err := repo.SoftDelete(ctx, id, actorID(ctx))
+		if err != nil {
+			return err
+		}
+		if err := recordTrashMovements(ctx, q, models.MovementDeparture, students...); err != nil {
+			return err
+		}
+		if err := repository.NewSessionRepository(q).RevokeAllForSchool(ctx, id); err != nil {
+			return err
+		}
+		return audit.Record(ctx, q, audit.Change{
+			SchoolID: &id, EntityType: audit.EntitySchool, EntityID: id,
+			Action: audit.ActionDelete, Before: school,
+		})
+	})
 }
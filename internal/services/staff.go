@@ -2,47 +2,121 @@ package services
 
 import (
 	"context"
+	"errors"
+	"time"
 
+	"eduBase/internal/audit"
 	"eduBase/internal/models"
 	"eduBase/internal/repository"
-	"github.com/jackc/pgx/v5"
 )
 
+// Допустимый возраст сотрудника по дате рождения
+const (
+	minStaffAge = 16
+	maxStaffAge = 90
+)
+
+var ErrInvalidBirthDate = errors.New("birth date is out of range")
+
+// staffBirthDateValid — дата рождения даёт возраст от minStaffAge до maxStaffAge лет
+func staffBirthDateValid(d time.Time) bool {
+	now := time.Now()
+	return !d.After(now.AddDate(-minStaffAge, 0, 0)) && !d.Before(now.AddDate(-maxStaffAge, 0, 0))
+}
+
 type StaffService struct {
 	repo *repository.StaffRepository
-	db   *pgx.Conn
+	db   repository.DBTX
+	tx   *repository.TxManager
 }
 
-func NewStaffService(repo *repository.StaffRepository) *StaffService {
-	return &StaffService{repo: repo, db: repo.DB()}
+func NewStaffService(repo *repository.StaffRepository, tx *repository.TxManager) *StaffService {
+	return &StaffService{repo: repo, db: repo.DB(), tx: tx}
 }
 
-func (s *StaffService) RepoDB() *pgx.Conn {
+func (s *StaffService) RepoDB() repository.DBTX {
 	return s.db
 }
 
 func (s *StaffService) Create(ctx context.Context, staff *models.Staff) error {
-	return s.repo.Create(ctx, staff)
+	if staff.BirthDate != nil && !staffBirthDateValid(*staff.BirthDate) {
+		return ErrInvalidBirthDate
+	}
+	return s.tx.WithTx(ctx, func(q repository.DBTX) error {
+		if err := repository.NewStaffRepository(q).Create(ctx, staff); err != nil {
+			return err
+		}
+		return audit.Record(ctx, q, audit.Change{
+			SchoolID: &staff.SchoolID, EntityType: audit.EntityStaff, EntityID: staff.ID,
+			Action: audit.ActionCreate, After: staff,
+		})
+	})
 }
 
 func (s *StaffService) GetAll(ctx context.Context, schoolID *int, f repository.StaffFilter) ([]models.Staff, error) {
 	return s.repo.GetAll(ctx, schoolID, f)
 }
 
-func (s *StaffService) Delete(ctx context.Context, id, schoolID int) error {
-	return s.repo.Delete(ctx, id, schoolID)
+// Delete переносит сотрудника в корзину; schoolID != nil — только сотрудника этой школы
+func (s *StaffService) Delete(ctx context.Context, id int, schoolID *int) (bool, error) {
+	deleted := false
+	err := s.tx.WithTx(ctx, func(q repository.DBTX) error {
+		repo := repository.NewStaffRepository(q)
+		old, err := repo.GetByID(ctx, id)
+		if err != nil {
+			if errors.Is(err, repository.ErrStaffNotFound) {
+				return nil
+			}
+			return err
+		}
+		rows, err := repo.SoftDelete(ctx, id, schoolID, actorID(ctx))
+		if err != nil || rows == 0 {
+			return err
+		}
+		deleted = true
+		return audit.Record(ctx, q, audit.Change{
+			SchoolID: &old.SchoolID, EntityType: audit.EntityStaff, EntityID: id,
+			Action: audit.ActionDelete, Before: old,
+		})
+	})
+	return deleted, err
 }
 
 func (s *StaffService) GetByID(ctx context.Context, id int) (*models.Staff, error) {
 	return s.repo.GetByID(ctx, id)
 }
 
-func (s *StaffService) Update(ctx context.Context, id int, staff *models.Staff, role string) (bool, error) {
-	rows, err := s.repo.Update(ctx, id, staff, role)
-	if err != nil {
-		return false, err
+// Update обновляет сотрудника; schoolID != nil — только сотрудника этой школы
+func (s *StaffService) Update(ctx context.Context, id int, staff *models.Staff, schoolID *int) (bool, error) {
+	if staff.BirthDate != nil && !staffBirthDateValid(*staff.BirthDate) {
+		return false, ErrInvalidBirthDate
 	}
-	return rows > 0, nil
+	updated := false
+	err := s.tx.WithTx(ctx, func(q repository.DBTX) error {
+		repo := repository.NewStaffRepository(q)
+		old, err := repo.GetByID(ctx, id)
+		if err != nil {
+			if errors.Is(err, repository.ErrStaffNotFound) {
+				return nil
+			}
+			return err
+		}
+		rows, err := repo.Update(ctx, id, staff, schoolID)
+		if err != nil || rows == 0 {
+			return err
+		}
+		updated = true
+
+		fresh, err := repo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		return audit.Record(ctx, q, audit.Change{
+			SchoolID: &old.SchoolID, EntityType: audit.EntityStaff, EntityID: id,
+			Action: audit.ActionUpdate, Before: old, After: fresh,
+		})
+	})
+	return updated, err
 }
 
 func (s *StaffService) GetStats(ctx context.Context) (map[string]int, error) {
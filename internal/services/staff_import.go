@@ -0,0 +1,284 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"eduBase/internal/audit"
+	"eduBase/internal/models"
+	"eduBase/internal/repository"
+	"eduBase/internal/utils"
+)
+
+// Способы сопоставления строк файла с сотрудниками школы при повторной загрузке
+const (
+	StaffMatchPhone    = "phone"
+	StaffMatchFullName = "full_name"
+)
+
+var ErrInvalidStaffMatch = errors.New("match must be phone or full_name")
+
+// Колонки шаблона загрузки сотрудников; * — обязательные
+const (
+	colStaffName      = "ФИО*"
+	colStaffBirth     = "Дата рождения"
+	colStaffPhone     = "Телефон*"
+	colStaffPosition  = "Должность*"
+	colStaffSubject   = "Предмет"
+	colStaffEducation = "Образование"
+	colStaffCategory  = "Категория"
+	colStaffPedExp    = "Педагогический стаж"
+	colStaffTotalExp  = "Общий стаж"
+	colStaffWorkStart = "Дата начала работы"
+	colStaffNote      = "Примечание"
+)
+
+// StaffImportColumns — заголовок шаблона загрузки сотрудников
+var StaffImportColumns = []string{
+	colStaffName, colStaffBirth, colStaffPhone, colStaffPosition, colStaffSubject, colStaffEducation,
+	colStaffCategory, colStaffPedExp, colStaffTotalExp, colStaffWorkStart, colStaffNote,
+}
+
+// StaffImportExample — пример строки шаблона
+var StaffImportExample = []any{
+	"Петрова Анна Сергеевна", "12.03.1985", "+7 900 000-00-00", "Учитель", "Математика", "Высшее",
+	"Высшая", 12, 15, "01.09.2013", "",
+}
+
+// normPhone оставляет в телефоне только цифры; российский номер с 8 приводится к 7
+func normPhone(s string) string {
+	digits := strings.Map(func(r rune) rune {
+		if unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, s)
+	if len(digits) == 11 && digits[0] == '8' {
+		digits = "7" + digits[1:]
+	}
+	return digits
+}
+
+// normName — ФИО без учёта регистра и лишних пробелов
+func normName(s string) string {
+	return strings.ToLower(strings.Join(strings.Fields(s), " "))
+}
+
+// staffImportKey — ключ сопоставления сотрудника в выбранном режиме
+func staffImportKey(st *models.Staff, match string) string {
+	if match == StaffMatchFullName {
+		return normName(st.FullName)
+	}
+	return normPhone(st.Phone)
+}
+
+// Import загружает сотрудников школы из строк xlsx/csv (первая строка — заголовок шаблона).
+// Строка, совпавшая с сотрудником школы по телефону или ФИО (match), обновляет его — заполненными ячейками;
+// остальные строки добавляют новых сотрудников. Проверяются все строки; при любой ошибке и при dryRun ничего не сохраняется.
+func (s *StaffService) Import(ctx context.Context, schoolID int, rows [][]string, match string, dryRun bool) (*models.ImportReport, error) {
+	if match != StaffMatchPhone && match != StaffMatchFullName {
+		return nil, ErrInvalidStaffMatch
+	}
+	report := &models.ImportReport{DryRun: dryRun, Errors: []models.ImportError{}}
+	if len(rows) == 0 {
+		return nil, ErrImportEmpty
+	}
+	cols, errs := importColumns(rows[0], StaffImportColumns)
+	if len(errs) > 0 {
+		report.Errors = errs
+		return report, nil
+	}
+
+	err := s.tx.WithTx(ctx, func(q repository.DBTX) error {
+		repo := repository.NewStaffRepository(q)
+		existing, err := repo.GetAll(ctx, &schoolID, repository.StaffFilter{})
+		if err != nil {
+			return err
+		}
+		// по ФИО сотрудник находится однозначно, только если тёзок в школе нет
+		byKey := make(map[string]*models.Staff, len(existing))
+		ambiguous := map[string]bool{}
+		for i := range existing {
+			key := staffImportKey(&existing[i], match)
+			if _, dup := byKey[key]; dup {
+				ambiguous[key] = true
+			}
+			byKey[key] = &existing[i]
+		}
+
+		type staffRow struct {
+			staff *models.Staff
+			old   *models.Staff
+		}
+		var list []staffRow
+		seen := map[string]int{}
+		today := time.Now().Format(time.DateOnly)
+		for i, cells := range rows[1:] {
+			row := importRow{cells: cells, cols: cols}
+			if row.empty() {
+				continue
+			}
+			report.Rows++
+			if report.Rows > MaxImportRows {
+				return ErrImportTooLarge
+			}
+			n := i + 2
+			fail := func(col, msg string) {
+				report.Errors = append(report.Errors, models.ImportError{Row: n, Column: col, Message: msg})
+			}
+
+			st := models.Staff{
+				FullName: strings.Join(strings.Fields(row.get(colStaffName)), " "),
+				Phone:    row.get(colStaffPhone), Position: row.get(colStaffPosition),
+				Subject: row.opt(colStaffSubject), Education: row.opt(colStaffEducation),
+				Category: row.opt(colStaffCategory), Note: row.opt(colStaffNote), SchoolID: schoolID,
+			}
+			ok := true
+			if st.FullName == "" {
+				fail(colStaffName, "full name is required")
+				ok = false
+			}
+			if st.Phone == "" {
+				fail(colStaffPhone, "phone is required")
+				ok = false
+			} else if l := len(normPhone(st.Phone)); l < 5 || l > 15 {
+				fail(colStaffPhone, "invalid phone")
+				ok = false
+			}
+			if st.Position == "" {
+				fail(colStaffPosition, "position is required")
+				ok = false
+			}
+			for _, c := range []struct {
+				col string
+				dst **int
+			}{{colStaffPedExp, &st.PedExperience}, {colStaffTotalExp, &st.TotalExperience}} {
+				v := row.get(c.col)
+				if v == "" {
+					continue
+				}
+				years, err := strconv.Atoi(v)
+				if err != nil || years < 0 || years > 70 {
+					fail(c.col, "experience must be a whole number of years from 0 to 70")
+					ok = false
+					continue
+				}
+				*c.dst = &years
+			}
+			if st.PedExperience != nil && st.TotalExperience != nil && *st.PedExperience > *st.TotalExperience {
+				fail(colStaffPedExp, "teaching experience exceeds total experience")
+				ok = false
+			}
+			if v := row.get(colStaffBirth); v != "" {
+				d, err := utils.ParseDate(v)
+				switch {
+				case err != nil:
+					fail(colStaffBirth, "invalid date, expected DD.MM.YYYY")
+					ok = false
+				case !staffBirthDateValid(d):
+					fail(colStaffBirth, "birth date is out of range")
+					ok = false
+				default:
+					st.BirthDate = &d
+				}
+			}
+			if v := row.get(colStaffWorkStart); v != "" {
+				d, err := utils.ParseDate(v)
+				switch {
+				case err != nil:
+					fail(colStaffWorkStart, "invalid date, expected DD.MM.YYYY")
+					ok = false
+				case d.Format(time.DateOnly) > today:
+					fail(colStaffWorkStart, "work start is in the future")
+					ok = false
+				default:
+					st.WorkStart = &d
+				}
+			}
+			if !ok {
+				continue
+			}
+
+			key := staffImportKey(&st, match)
+			if prev, dup := seen[key]; dup {
+				fail(colStaffName, "duplicate of row "+strconv.Itoa(prev))
+				continue
+			}
+			seen[key] = n
+			if ambiguous[key] {
+				fail(colStaffName, "several staff members of the school match this row")
+				continue
+			}
+
+			old := byKey[key]
+			if old != nil {
+				merged := *old
+				merged.FullName, merged.Phone, merged.Position = st.FullName, st.Phone, st.Position
+				for _, f := range []struct{ dst, src **string }{
+					{&merged.Subject, &st.Subject}, {&merged.Education, &st.Education},
+					{&merged.Category, &st.Category}, {&merged.Note, &st.Note},
+				} {
+					if *f.src != nil {
+						*f.dst = *f.src
+					}
+				}
+				if st.PedExperience != nil {
+					merged.PedExperience = st.PedExperience
+				}
+				if st.TotalExperience != nil {
+					merged.TotalExperience = st.TotalExperience
+				}
+				if st.WorkStart != nil {
+					merged.WorkStart = st.WorkStart
+				}
+				if st.BirthDate != nil {
+					merged.BirthDate = st.BirthDate
+				}
+				st = merged
+			}
+			list = append(list, staffRow{staff: &st, old: old})
+		}
+
+		if len(report.Errors) > 0 || dryRun {
+			return nil
+		}
+
+		for _, r := range list {
+			if r.old == nil {
+				if err := repo.Create(ctx, r.staff); err != nil {
+					return err
+				}
+				if err := audit.Record(ctx, q, audit.Change{
+					SchoolID: &schoolID, EntityType: audit.EntityStaff, EntityID: r.staff.ID,
+					Action: audit.ActionCreate, After: r.staff,
+				}); err != nil {
+					return err
+				}
+				continue
+			}
+			if _, err := repo.Update(ctx, r.old.ID, r.staff, &schoolID); err != nil {
+				return err
+			}
+			if err := audit.Record(ctx, q, audit.Change{
+				SchoolID: &schoolID, EntityType: audit.EntityStaff, EntityID: r.old.ID,
+				Action: audit.ActionUpdate, Before: r.old, After: r.staff,
+			}); err != nil {
+				return err
+			}
+			report.Updated++
+		}
+		report.Imported = len(list)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if report.Rows == 0 {
+		return nil, ErrImportEmpty
+	}
+	return report, nil
+}
@@ -0,0 +1,213 @@
+package services
+
+import (
+	"context"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+
+	"eduBase/internal/models"
+)
+
+// Возрастные границы кадровой статистики: предпенсионный и пенсионный возраст — с NearRetirementAge лет;
+// молодой специалист — моложе YoungSpecialistAge лет с педагогическим стажем меньше YoungSpecialistExperience лет
+const (
+	NearRetirementAge         = 55
+	YoungSpecialistAge        = 35
+	YoungSpecialistExperience = 3
+)
+
+// Квалификационные категории и уровни образования после приведения свободного текста
+const (
+	CategoryHighest  = "Высшая"
+	CategoryFirst    = "Первая"
+	CategoryPosition = "Соответствие занимаемой должности"
+	CategoryNone     = "Без категории"
+
+	EducationHigher     = "Высшее"
+	EducationVocational = "Среднее профессиональное"
+	EducationUnknown    = "Не указано"
+)
+
+// staffExperience — интервалы педагогического стажа: стаж меньше upTo лет (последний — без верхней границы)
+var staffExperience = []struct {
+	label string
+	upTo  int
+}{
+	{"до 3 лет", 3}, {"от 3 до 5 лет", 5}, {"от 5 до 10 лет", 10}, {"от 10 до 20 лет", 20}, {"20 лет и более", 0},
+}
+
+const experienceUnknown = "не указан"
+
+// staffCategory приводит категорию из карточки сотрудника к одному из значений; незнакомое — как есть
+func staffCategory(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	low := strings.ToLower(s)
+	switch {
+	case strings.Contains(low, "высш"):
+		return CategoryHighest
+	case strings.Contains(low, "перв"):
+		return CategoryFirst
+	case strings.Contains(low, "соответ") || low == "сзд":
+		return CategoryPosition
+	case low == "" || low == "нет" || strings.HasPrefix(low, "без"):
+		return CategoryNone
+	}
+	return s
+}
+
+// staffEducation приводит образование из карточки сотрудника к уровню; незнакомое — как есть
+func staffEducation(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	low := strings.ToLower(s)
+	switch {
+	case strings.Contains(low, "высш"):
+		return EducationHigher
+	case slices.Contains(strings.Fields(low), "спо") ||
+		strings.Contains(low, "сред") && (strings.Contains(low, "проф") || strings.Contains(low, "спец")):
+		return EducationVocational
+	case low == "":
+		return EducationUnknown
+	}
+	return s
+}
+
+// staffExperienceLabel — интервал педагогического стажа
+func staffExperienceLabel(years *int) string {
+	if years == nil {
+		return experienceUnknown
+	}
+	for _, e := range staffExperience[:len(staffExperience)-1] {
+		if *years < e.upTo {
+			return e.label
+		}
+	}
+	return staffExperience[len(staffExperience)-1].label
+}
+
+// percent — доля в процентах с точностью до десятых
+func percent(n, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Round(float64(n)*1000/float64(total)) / 10
+}
+
+// teacherGroups считает учителей по значениям признака. Строки: fixed — всегда и в этом порядке,
+// затем прочие значения по убыванию числа учителей, последними — trailing (если есть учителя)
+type teacherGroups struct {
+	fixed, trailing []string
+	counts          map[string]int
+}
+
+func (g *teacherGroups) add(label string, n int) {
+	if g.counts == nil {
+		g.counts = map[string]int{}
+	}
+	g.counts[label] += n
+}
+
+func (g *teacherGroups) rows(teachers int) []models.TeacherGroup {
+	known := map[string]bool{}
+	for _, label := range append(append([]string{}, g.fixed...), g.trailing...) {
+		known[label] = true
+	}
+	var rest []string
+	for label := range g.counts {
+		if !known[label] {
+			rest = append(rest, label)
+		}
+	}
+	sort.Slice(rest, func(i, j int) bool {
+		if g.counts[rest[i]] != g.counts[rest[j]] {
+			return g.counts[rest[i]] > g.counts[rest[j]]
+		}
+		return rest[i] < rest[j]
+	})
+
+	list := make([]models.TeacherGroup, 0, len(g.fixed)+len(rest)+len(g.trailing))
+	row := func(label string) models.TeacherGroup {
+		return models.TeacherGroup{Label: label, Teachers: g.counts[label], Share: percent(g.counts[label], teachers)}
+	}
+	for _, label := range g.fixed {
+		list = append(list, row(label))
+	}
+	for _, label := range rest {
+		list = append(list, row(label))
+	}
+	for _, label := range g.trailing {
+		if g.counts[label] > 0 {
+			list = append(list, row(label))
+		}
+	}
+	return list
+}
+
+// GetStaffStats — кадровый состав: учителя по категории, образованию и стажу, предпенсионного возраста
+// и молодые специалисты, разрез по школам. schoolID == nil — весь район.
+func (s *StatsService) GetStaffStats(ctx context.Context, schoolID *int) (*models.StaffStats, error) {
+	groups, err := s.repo.GetStaffGroups(ctx, schoolID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &models.StaffStats{Schools: []models.StaffSchoolRow{}}
+	categories := &teacherGroups{fixed: []string{CategoryHighest, CategoryFirst, CategoryPosition, CategoryNone}}
+	education := &teacherGroups{fixed: []string{EducationHigher, EducationVocational}, trailing: []string{EducationUnknown}}
+	experience := &teacherGroups{trailing: []string{experienceUnknown}}
+	for _, e := range staffExperience {
+		experience.fixed = append(experience.fixed, e.label)
+	}
+	for _, g := range groups {
+		// группы упорядочены по школам
+		if n := len(res.Schools); n == 0 || res.Schools[n-1].SchoolID != g.SchoolID {
+			res.Schools = append(res.Schools, models.StaffSchoolRow{SchoolID: g.SchoolID, SchoolName: g.SchoolName})
+		}
+		sc := &res.Schools[len(res.Schools)-1]
+		res.Staff += g.Count
+		sc.Staff += g.Count
+		if !g.Teacher {
+			continue
+		}
+		res.Teachers += g.Count
+		sc.Teachers += g.Count
+
+		category := staffCategory(g.Category)
+		categories.add(category, g.Count)
+		switch category {
+		case CategoryHighest:
+			sc.HighestCategory += g.Count
+		case CategoryFirst:
+			sc.FirstCategory += g.Count
+		}
+		edu := staffEducation(g.Education)
+		education.add(edu, g.Count)
+		if edu == EducationHigher {
+			sc.HigherEducation += g.Count
+		}
+		experience.add(staffExperienceLabel(g.PedExperience), g.Count)
+
+		if g.Age == nil {
+			res.NoBirthDate += g.Count
+		} else if *g.Age >= NearRetirementAge {
+			res.NearRetirement += g.Count
+			sc.NearRetirement += g.Count
+		}
+		if g.Age != nil && *g.Age < YoungSpecialistAge && g.PedExperience != nil && *g.PedExperience < YoungSpecialistExperience {
+			res.YoungSpecialists += g.Count
+			sc.YoungSpecialists += g.Count
+		}
+	}
+
+	res.Categories = categories.rows(res.Teachers)
+	res.Education = education.rows(res.Teachers)
+	res.Experience = experience.rows(res.Teachers)
+	res.NearRetirementShare = percent(res.NearRetirement, res.Teachers)
+	res.YoungSpecialistsShare = percent(res.YoungSpecialists, res.Teachers)
+	for i := range res.Schools {
+		sc := &res.Schools[i]
+		sc.CategoryShare = percent(sc.HighestCategory+sc.FirstCategory, sc.Teachers)
+	}
+	return res, nil
+}
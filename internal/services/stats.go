@@ -2,23 +2,236 @@ package services
 
 import (
 	"context"
+	"math"
+	"sort"
+	"strconv"
+	"time"
 
 	"eduBase/internal/models"
 	"eduBase/internal/repository"
-	"github.com/jackc/pgx/v5"
 )
 
 type StatsService struct {
 	repo       *repository.StatsRepository
 	schoolRepo *repository.SchoolRepository
+	tx         *repository.TxManager
 }
 
-func NewStatsService(repo *repository.StatsRepository, schoolRepo *repository.SchoolRepository) *StatsService {
-	return &StatsService{repo: repo, schoolRepo: schoolRepo}
+func NewStatsService(repo *repository.StatsRepository, schoolRepo *repository.SchoolRepository, tx *repository.TxManager) *StatsService {
+	return &StatsService{repo: repo, schoolRepo: schoolRepo, tx: tx}
 }
 
-func (s *StatsService) RepoDB() *pgx.Conn { return s.repo.DB() }
+func (s *StatsService) RepoDB() repository.DBTX { return s.repo.DB() }
 
 func (s *StatsService) GetSummary(ctx context.Context, schoolID *int) (*models.StatsSummary, error) {
 	return s.repo.GetSummary(ctx, schoolID)
 }
+
+// GetAttendance — посещаемость по школам и дням; schoolID == nil — весь район
+func (s *StatsService) GetAttendance(ctx context.Context, schoolID *int, from, to time.Time) ([]models.AttendanceDay, error) {
+	return s.repo.GetAttendance(ctx, schoolID, from, to)
+}
+
+// StandardTeachingHours — ставка учителя, часов в неделю
+const StandardTeachingHours = 18.0
+
+// GetTeachingLoad — нагрузка учителей относительно ставки, вакантные часы по школам
+// и потребность района в учителях по предметам; schoolID == nil — весь район
+func (s *StatsService) GetTeachingLoad(ctx context.Context, schoolID *int) (*models.TeachingLoad, error) {
+	teachers, err := s.repo.GetTeacherHours(ctx, schoolID)
+	if err != nil {
+		return nil, err
+	}
+	vacancies, err := s.repo.GetVacancies(ctx, schoolID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &models.TeachingLoad{StandardHours: StandardTeachingHours, Teachers: teachers, Vacancies: vacancies}
+	for i := range res.Teachers {
+		t := &res.Teachers[i]
+		t.Rate = math.Round(t.Hours*100/StandardTeachingHours) / 100
+		switch {
+		case t.Hours > StandardTeachingHours:
+			t.Status = models.LoadOver
+			res.Overloaded++
+		case t.Hours < StandardTeachingHours:
+			t.Status = models.LoadUnder
+			res.Underloaded++
+		default:
+			t.Status = models.LoadNormal
+		}
+	}
+
+	// учитель работает в одной школе, поэтому потребность считается по каждой школе и суммируется
+	need := map[int]*models.SubjectNeed{}
+	for i := range res.Vacancies {
+		v := &res.Vacancies[i]
+		v.Teachers = int(math.Ceil(v.Hours / StandardTeachingHours))
+		n, ok := need[v.SubjectID]
+		if !ok {
+			n = &models.SubjectNeed{SubjectID: v.SubjectID, SubjectName: v.SubjectName}
+			need[v.SubjectID] = n
+		}
+		n.Hours += v.Hours
+		n.Teachers += v.Teachers
+	}
+	res.Needed = make([]models.SubjectNeed, 0, len(need))
+	for _, n := range need {
+		res.Needed = append(res.Needed, *n)
+	}
+	sort.Slice(res.Needed, func(i, j int) bool { return res.Needed[i].SubjectName < res.Needed[j].SubjectName })
+	return res, nil
+}
+
+// GetPerformance — качество знаний и успеваемость по итоговым отметкам периода: по классам, школам и району.
+// yearID == nil — текущий учебный год; schoolID == nil — весь район.
+func (s *StatsService) GetPerformance(ctx context.Context, schoolID, yearID *int, period string) (*models.PerformanceStats, error) {
+	year, err := s.academicYear(ctx, yearID)
+	if err != nil {
+		return nil, err
+	}
+
+	classes, err := s.repo.GetPerformance(ctx, schoolID, year.ID, period)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &models.PerformanceStats{AcademicYearID: year.ID, Period: period, Classes: classes, Schools: []models.PerformanceRow{}}
+	district := models.PerformanceRow{}
+	for i := range res.Classes {
+		c := &res.Classes[i]
+		performanceRates(c)
+
+		// классы упорядочены по школам
+		if n := len(res.Schools); n == 0 || *res.Schools[n-1].SchoolID != *c.SchoolID {
+			res.Schools = append(res.Schools, models.PerformanceRow{SchoolID: c.SchoolID, SchoolName: c.SchoolName})
+		}
+		addPerformance(&res.Schools[len(res.Schools)-1], c)
+		addPerformance(&district, c)
+	}
+	for i := range res.Schools {
+		performanceRates(&res.Schools[i])
+	}
+	if schoolID == nil {
+		performanceRates(&district)
+		res.District = &district
+	}
+	return res, nil
+}
+
+func addPerformance(dst, src *models.PerformanceRow) {
+	dst.Students += src.Students
+	dst.Excellent += src.Excellent
+	dst.Good += src.Good
+	dst.Passed += src.Passed
+}
+
+// performanceRates считает неуспевающих и доли в процентах с точностью до десятых
+func performanceRates(p *models.PerformanceRow) {
+	p.Failing = p.Students - p.Passed
+	if p.Students > 0 {
+		p.QualityRate = math.Round(float64(p.Good)*1000/float64(p.Students)) / 10
+		p.SuccessRate = math.Round(float64(p.Passed)*1000/float64(p.Students)) / 10
+	}
+}
+
+// academicYear — учебный год по ID, по умолчанию текущий
+func (s *StatsService) academicYear(ctx context.Context, yearID *int) (*models.AcademicYear, error) {
+	years := repository.NewAcademicYearRepository(s.repo.DB())
+	if yearID == nil {
+		return years.GetCurrent(ctx)
+	}
+	return years.GetByID(ctx, *yearID)
+}
+
+// classSizeBands — интервалы наполняемости классов: от min учеников (до следующего интервала)
+var classSizeBands = []int{1, 11, 16, 21, 26, 31}
+
+// GetStudentStats — состав учеников учебного года (по умолчанию текущего): возраст на дату по полу,
+// пол по параллелям, наполняемость классов и разрез по школам. schoolID == nil — весь район, grade != nil — одна параллель.
+func (s *StatsService) GetStudentStats(ctx context.Context, schoolID, grade, yearID *int, date time.Time) (*models.StudentStats, error) {
+	year, err := s.academicYear(ctx, yearID)
+	if err != nil {
+		return nil, err
+	}
+	groups, err := s.repo.GetStudentGroups(ctx, schoolID, grade, year.ID, date)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &models.StudentStats{
+		AcademicYearID: year.ID, Date: date.Format(time.DateOnly),
+		Ages: []models.StudentAgeRow{}, Grades: []models.StudentGradeRow{}, Schools: []models.StudentSchoolRow{},
+	}
+	ages := map[int]*models.StudentAgeRow{}
+	var noAge *models.StudentAgeRow
+	grades := map[int]*models.StudentGradeRow{}
+	classSizes := map[int]int{}
+	schoolClasses := map[int]map[int]bool{}
+	for _, g := range groups {
+		res.Add(g.Gender, g.Count)
+
+		row := noAge
+		if g.Age != nil {
+			row = ages[*g.Age]
+			if row == nil {
+				row = &models.StudentAgeRow{Age: g.Age}
+				ages[*g.Age] = row
+			}
+		} else if row == nil {
+			noAge = &models.StudentAgeRow{}
+			row = noAge
+		}
+		row.Add(g.Gender, g.Count)
+
+		if grades[g.Grade] == nil {
+			grades[g.Grade] = &models.StudentGradeRow{Grade: g.Grade}
+		}
+		grades[g.Grade].Add(g.Gender, g.Count)
+		classSizes[g.ClassID] += g.Count
+
+		// группы упорядочены по школам
+		if n := len(res.Schools); n == 0 || res.Schools[n-1].SchoolID != g.SchoolID {
+			res.Schools = append(res.Schools, models.StudentSchoolRow{SchoolID: g.SchoolID, SchoolName: g.SchoolName})
+			schoolClasses[g.SchoolID] = map[int]bool{}
+		}
+		res.Schools[len(res.Schools)-1].Add(g.Gender, g.Count)
+		schoolClasses[g.SchoolID][g.ClassID] = true
+	}
+
+	for _, row := range ages {
+		res.Ages = append(res.Ages, *row)
+	}
+	sort.Slice(res.Ages, func(i, j int) bool { return *res.Ages[i].Age < *res.Ages[j].Age })
+	if noAge != nil {
+		res.Ages = append(res.Ages, *noAge)
+	}
+	for _, row := range grades {
+		res.Grades = append(res.Grades, *row)
+	}
+	sort.Slice(res.Grades, func(i, j int) bool { return res.Grades[i].Grade < res.Grades[j].Grade })
+
+	res.ClassSizes = make([]models.ClassSizeBand, len(classSizeBands))
+	for i, lo := range classSizeBands {
+		band := &res.ClassSizes[i]
+		band.Min, band.Band = lo, strconv.Itoa(lo)+" и более"
+		if i+1 < len(classSizeBands) {
+			hi := classSizeBands[i+1] - 1
+			band.Max, band.Band = &hi, strconv.Itoa(lo)+"–"+strconv.Itoa(hi)
+		}
+	}
+	for _, n := range classSizes {
+		i := sort.Search(len(classSizeBands), func(i int) bool { return classSizeBands[i] > n }) - 1
+		res.ClassSizes[i].Classes++
+		res.ClassSizes[i].Students += n
+	}
+	for i := range res.Schools {
+		sc := &res.Schools[i]
+		sc.Classes = len(schoolClasses[sc.SchoolID])
+		if sc.Classes > 0 {
+			sc.AvgClassSize = math.Round(float64(sc.Total)*10/float64(sc.Classes)) / 10
+		}
+	}
+	return res, nil
+}
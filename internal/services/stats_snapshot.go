@@ -0,0 +1,77 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"eduBase/internal/models"
+	"eduBase/internal/repository"
+)
+
+// Периодичность снимков сводной статистики
+const (
+	SnapshotDaily   = "day"
+	SnapshotMonthly = "month"
+)
+
+var ErrInvalidHistoryRange = errors.New("from must not be after to")
+
+// TakeSnapshot сохраняет сводную статистику района и каждой школы на дату; снимок за ту же дату заменяется.
+// Значения считает GetSummary — те же, что на дашборде.
+func (s *StatsService) TakeSnapshot(ctx context.Context, date time.Time) error {
+	return s.tx.WithTx(ctx, func(q repository.DBTX) error {
+		repo := repository.NewStatsRepository(q)
+		district, err := repo.GetSummary(ctx, nil)
+		if err != nil {
+			return err
+		}
+		if err := repo.SaveSnapshot(ctx, date, nil, district); err != nil {
+			return err
+		}
+		schools, err := repository.NewSchoolRepository(q).GetAll(ctx)
+		if err != nil {
+			return err
+		}
+		for _, sc := range schools {
+			sum, err := repo.GetSummary(ctx, &sc.ID)
+			if err != nil {
+				return err
+			}
+			if err := repo.SaveSnapshot(ctx, date, &sc.ID, sum); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// SnapshotDue — нужен ли снимок на now: за этот день (period=day) или месяц (period=month) снимка ещё нет
+func (s *StatsService) SnapshotDue(ctx context.Context, period string, now time.Time) (bool, error) {
+	last, err := s.repo.LastSnapshotDate(ctx)
+	if err != nil {
+		return false, err
+	}
+	if last == nil {
+		return true, nil
+	}
+	if period == SnapshotMonthly {
+		return last.Year() != now.Year() || last.Month() != now.Month(), nil
+	}
+	return last.Format(time.DateOnly) != now.Format(time.DateOnly), nil
+}
+
+// GetHistory — значения показателя сводной статистики по снимкам за период; schoolID == nil — весь район
+func (s *StatsService) GetHistory(ctx context.Context, metric string, schoolID *int, from, to time.Time) (*models.StatsHistory, error) {
+	if from.After(to) {
+		return nil, ErrInvalidHistoryRange
+	}
+	points, err := s.repo.GetHistory(ctx, metric, schoolID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return &models.StatsHistory{
+		Metric: metric, SchoolID: schoolID,
+		From: from.Format(time.DateOnly), To: to.Format(time.DateOnly), Points: points,
+	}, nil
+}
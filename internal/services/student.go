@@ -2,81 +2,264 @@ package services
 
 import (
 	"context"
+	"errors"
+	"time"
 
+	"eduBase/internal/audit"
 	"eduBase/internal/models"
 	"eduBase/internal/repository"
-	"github.com/jackc/pgx/v5"
 )
 
 type StudentService struct {
 	repo       *repository.StudentRepository
 	classRepo  *repository.ClassRepository
 	schoolRepo *repository.SchoolRepository
+	movements  *repository.MovementRepository
+	tx         *repository.TxManager
 }
 
-func NewStudentService(r *repository.StudentRepository, cr *repository.ClassRepository, sr *repository.SchoolRepository) *StudentService {
-	return &StudentService{repo: r, classRepo: cr, schoolRepo: sr}
+func NewStudentService(r *repository.StudentRepository, cr *repository.ClassRepository, sr *repository.SchoolRepository, mr *repository.MovementRepository, tx *repository.TxManager) *StudentService {
+	return &StudentService{repo: r, classRepo: cr, schoolRepo: sr, movements: mr, tx: tx}
 }
 
 // ==== 🔧 Геттеры для БД ====
-func (s *StudentService) SchoolRepoDB() *pgx.Conn {
+func (s *StudentService) SchoolRepoDB() repository.DBTX {
 	return s.schoolRepo.DB()
 }
 
-func (s *StudentService) ClassRepoDB() *pgx.Conn {
+func (s *StudentService) ClassRepoDB() repository.DBTX {
 	return s.classRepo.DB()
 }
 
 // ==== 🔧 CRUD ====
 func (s *StudentService) Create(ctx context.Context, st *models.Student) error {
-	if err := s.repo.Create(ctx, st); err != nil {
+	return s.tx.WithTx(ctx, func(q repository.DBTX) error {
+		c, err := checkClassInSchool(ctx, q, st.ClassID, st.SchoolID)
+		if err != nil {
+			return err
+		}
+		if err := checkCurrentYear(ctx, q, c); err != nil {
+			return err
+		}
+		if err := createStudent(ctx, q, st, c); err != nil {
+			return err
+		}
+		return updateCounts(ctx, q, st.SchoolID, st.ClassID)
+	})
+}
+
+// createStudent зачисляет ученика в класс c: запись, зачисление на учебный год, прибытие в книге движения и аудит.
+// Счётчики класса и школы пересчитывает вызывающий.
+func createStudent(ctx context.Context, q repository.DBTX, st *models.Student, c *models.Class) error {
+	if err := repository.NewStudentRepository(q).Create(ctx, st); err != nil {
 		return err
 	}
-	return s.UpdateCounts(ctx, st.SchoolID, st.ClassID)
+	if err := repository.NewEnrollmentRepository(q).Upsert(ctx, st.ID, c.AcademicYearID, c.ID, st.SchoolID); err != nil {
+		return err
+	}
+	if err := recordMovement(ctx, q, &models.StudentMovement{
+		StudentID: st.ID, SchoolID: st.SchoolID, Kind: models.MovementArrival,
+		ToSchoolID: &st.SchoolID, ToClassID: &st.ClassID,
+	}); err != nil {
+		return err
+	}
+	return audit.Record(ctx, q, audit.Change{
+		SchoolID: &st.SchoolID, EntityType: audit.EntityStudent, EntityID: st.ID,
+		Action: audit.ActionCreate, After: st,
+	})
 }
 
 func (s *StudentService) GetAll(ctx context.Context, schoolID *int, f repository.StudentFilter) ([]models.Student, error) {
 	return s.repo.GetAll(ctx, schoolID, f)
 }
 
-func (s *StudentService) Delete(ctx context.Context, id, schoolID, classID int) error {
-	if err := s.repo.Delete(ctx, id, schoolID); err != nil {
-		return err
+// Примечания к записям книги движения при удалении ученика в корзину и восстановлении из неё
+var deletionNote, restoreNote = "удалён в корзину", "восстановлен из корзины"
+
+// recordTrashMovements записывает выбытие (kind = departure, удаление в корзину) или прибытие
+// (kind = arrival, восстановление) учеников — одного или перенесённых вместе с классом или школой.
+// Окончившие и выбывшие уже записаны выбывшими — для них записей нет.
+func recordTrashMovements(ctx context.Context, q repository.DBTX, kind string, students ...models.Student) error {
+	for _, st := range students {
+		if st.Status != models.StudentActive {
+			continue
+		}
+		m := &models.StudentMovement{StudentID: st.ID, SchoolID: st.SchoolID, Kind: kind}
+		if kind == models.MovementDeparture {
+			m.FromSchoolID, m.FromClassID, m.Note = &st.SchoolID, &st.ClassID, &deletionNote
+		} else {
+			m.ToSchoolID, m.ToClassID, m.Note = &st.SchoolID, &st.ClassID, &restoreNote
+		}
+		if err := recordMovement(ctx, q, m); err != nil {
+			return err
+		}
 	}
-	return s.UpdateCounts(ctx, schoolID, classID)
+	return nil
+}
+
+// Delete переносит ученика в корзину, пересчитывает счётчики класса и школы и записывает выбытие в книгу движения.
+// schoolID != nil — только ученика этой школы; false — не найден или чужой.
+func (s *StudentService) Delete(ctx context.Context, id int, schoolID *int) (bool, error) {
+	deleted := false
+	err := s.tx.WithTx(ctx, func(q repository.DBTX) error {
+		repo := repository.NewStudentRepository(q)
+		st, err := repo.GetByID(ctx, id)
+		if err != nil {
+			if errors.Is(err, repository.ErrStudentNotFound) {
+				return nil
+			}
+			return err
+		}
+		if schoolID != nil && st.SchoolID != *schoolID {
+			return nil
+		}
+		if err := repo.SoftDelete(ctx, id, st.SchoolID, actorID(ctx)); err != nil {
+			return err
+		}
+		deleted = true
+		if err := updateCounts(ctx, q, st.SchoolID, st.ClassID); err != nil {
+			return err
+		}
+		// удалённый ученик больше не числится в школе
+		if err := recordTrashMovements(ctx, q, models.MovementDeparture, *st); err != nil {
+			return err
+		}
+		return audit.Record(ctx, q, audit.Change{
+			SchoolID: &st.SchoolID, EntityType: audit.EntityStudent, EntityID: st.ID,
+			Action: audit.ActionDelete, Before: st,
+		})
+	})
+	return deleted, err
 }
 
 // ==== 🔧 Обновление счётчиков ====
 func (s *StudentService) UpdateCounts(ctx context.Context, schoolID, classID int) error {
-	count, err := s.repo.CountByClass(ctx, classID)
-	if err != nil {
+	return s.tx.WithTx(ctx, func(q repository.DBTX) error {
+		return updateCounts(ctx, q, schoolID, classID)
+	})
+}
+
+// updateCounts пересчитывает student_count класса и школы в рамках переданной транзакции
+func updateCounts(ctx context.Context, q repository.DBTX, schoolID, classID int) error {
+	if err := repository.NewClassRepository(q).RefreshStudentCount(ctx, classID); err != nil {
 		return err
 	}
-	_, err = s.classRepo.DB().Exec(ctx, `UPDATE classes SET student_count=$1 WHERE id=$2`, count, classID)
+	return repository.NewSchoolRepository(q).RefreshStudentCount(ctx, schoolID)
+}
+
+// checkClassInSchool — класс существует и принадлежит школе
+func checkClassInSchool(ctx context.Context, q repository.DBTX, classID, schoolID int) (*models.Class, error) {
+	c, err := repository.NewClassRepository(q).GetByID(ctx, classID)
 	if err != nil {
-		return err
+		if errors.Is(err, repository.ErrClassNotFound) {
+			return nil, ErrClassNotInSchool
+		}
+		return nil, err
+	}
+	if c.SchoolID != schoolID {
+		return nil, ErrClassNotInSchool
 	}
+	return c, nil
+}
 
-	_, err = s.schoolRepo.DB().Exec(ctx, `
-		UPDATE schools
-		SET student_count=(SELECT COUNT(*) FROM students WHERE school_id=$1)
-		WHERE id=$1`, schoolID)
+// checkCurrentYear — зачислить ученика можно только в класс текущего учебного года
+func checkCurrentYear(ctx context.Context, q repository.DBTX, c *models.Class) error {
+	_, err := currentClassYear(ctx, q, c)
 	return err
 }
 
+// currentClassYear возвращает текущий учебный год, если класс c относится к нему, иначе ErrClassArchived
+func currentClassYear(ctx context.Context, q repository.DBTX, c *models.Class) (*models.AcademicYear, error) {
+	cur, err := repository.NewAcademicYearRepository(q).GetCurrent(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if c.AcademicYearID != cur.ID {
+		return nil, ErrClassArchived
+	}
+	return cur, nil
+}
+
+// inYear — дата попадает в учебный год (границы включительно)
+func inYear(y *models.AcademicYear, date time.Time) bool {
+	d := date.Format(time.DateOnly)
+	return d >= y.StartDate.Format(time.DateOnly) && d <= y.EndDate.Format(time.DateOnly)
+}
+
 func (s *StudentService) GetByID(ctx context.Context, id int) (*models.Student, error) {
 	return s.repo.GetByID(ctx, id)
 }
 
-func (s *StudentService) Update(ctx context.Context, id int, st *models.Student, role string) (bool, error) {
-	rows, err := s.repo.Update(ctx, id, st, role)
-	if err != nil {
-		return false, err
-	}
-	if rows > 0 {
-		_ = s.UpdateCounts(ctx, st.SchoolID, st.ClassID)
-	}
-	return rows > 0, nil
+// Update обновляет ученика; schoolID != nil — только ученика этой школы.
+// Новый класс должен принадлежать школе ученика и текущему учебному году.
+func (s *StudentService) Update(ctx context.Context, id int, st *models.Student, schoolID *int) (bool, error) {
+	updated := false
+	err := s.tx.WithTx(ctx, func(q repository.DBTX) error {
+		repo := repository.NewStudentRepository(q)
+		old, err := repo.GetByID(ctx, id)
+		if err != nil {
+			if errors.Is(err, repository.ErrStudentNotFound) {
+				return nil
+			}
+			return err
+		}
+
+		if schoolID != nil && old.SchoolID != *schoolID {
+			return nil
+		}
+		c, err := checkClassInSchool(ctx, q, st.ClassID, old.SchoolID)
+		if err != nil {
+			return err
+		}
+		if old.ClassID != st.ClassID {
+			if err := checkCurrentYear(ctx, q, c); err != nil {
+				return err
+			}
+		}
+
+		rows, err := repo.Update(ctx, id, st, schoolID)
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			return nil
+		}
+		updated = true
+
+		// при переводе в другой класс пересчитываем и старый класс
+		if old.ClassID != st.ClassID {
+			if err := repository.NewEnrollmentRepository(q).Upsert(ctx, id, c.AcademicYearID, c.ID, old.SchoolID); err != nil {
+				return err
+			}
+			if err := updateCounts(ctx, q, old.SchoolID, old.ClassID); err != nil {
+				return err
+			}
+			if err := recordMovement(ctx, q, &models.StudentMovement{
+				StudentID: id, SchoolID: old.SchoolID, Kind: models.MovementClassChange,
+				FromClassID: &old.ClassID, ToClassID: &st.ClassID,
+			}); err != nil {
+				return err
+			}
+		}
+		if err := updateCounts(ctx, q, old.SchoolID, st.ClassID); err != nil {
+			return err
+		}
+
+		updatedSt, err := repo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		return audit.Record(ctx, q, audit.Change{
+			SchoolID: &old.SchoolID, EntityType: audit.EntityStudent, EntityID: id,
+			Action: audit.ActionUpdate, Before: old, After: updatedSt,
+		})
+	})
+	return updated, err
+}
+
+// Movements — книга движения ученика; schoolID != nil — только если ученик числился в этой школе
+func (s *StudentService) Movements(ctx context.Context, id int, schoolID *int) ([]models.StudentMovement, error) {
+	return s.movements.ListByStudent(ctx, id, schoolID)
 }
 
 func (s *StudentService) GetStats(ctx context.Context) (map[string]int, error) {
@@ -0,0 +1,198 @@
+package services
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"eduBase/internal/models"
+	"eduBase/internal/repository"
+	"eduBase/internal/utils"
+)
+
+// Колонки шаблона загрузки учеников; * — обязательные
+const (
+	colStudentName    = "ФИО*"
+	colStudentClass   = "Класс*"
+	colStudentBirth   = "Дата рождения"
+	colStudentGender  = "Пол"
+	colStudentPhone   = "Телефон"
+	colStudentAddress = "Адрес"
+	colStudentNote    = "Примечание"
+)
+
+// StudentImportColumns — заголовок шаблона загрузки учеников
+var StudentImportColumns = []string{
+	colStudentName, colStudentClass, colStudentBirth, colStudentGender,
+	colStudentPhone, colStudentAddress, colStudentNote,
+}
+
+// StudentImportExample — пример строки шаблона
+var StudentImportExample = []any{"Иванов Иван Иванович", "5А", "01.09.2014", "М", "+7 900 000-00-00", "ул. Ленина, 1", ""}
+
+var genders = map[string]string{
+	"м": "male", "муж": "male", "мужской": "male", "male": "male",
+	"ж": "female", "жен": "female", "женский": "female", "female": "female",
+}
+
+// classNameReplacer убирает разделители и заменяет латинские буквы на похожие кириллические
+var classNameReplacer = strings.NewReplacer(
+	" ", "", "-", "", "\u00a0", "",
+	"A", "А", "B", "В", "C", "С", "E", "Е", "H", "Н", "K", "К", "M", "М", "O", "О", "P", "Р", "T", "Т", "X", "Х",
+)
+
+// className приводит название класса к виду для сравнения: «5 а», «5-А», «5A» (латиницей) и «5А» совпадают
+func className(s string) string {
+	return classNameReplacer.Replace(strings.ToUpper(strings.TrimSpace(s)))
+}
+
+// studentKey — ключ поиска дублей: ФИО без учёта регистра и лишних пробелов и дата рождения
+func studentKey(fullName string, birth *time.Time) string {
+	key := strings.ToLower(strings.Join(strings.Fields(fullName), " "))
+	if birth != nil {
+		key += "|" + birth.Format(time.DateOnly)
+	}
+	return key
+}
+
+// Import загружает учеников школы из строк xlsx-файла (первая строка — заголовок шаблона) в классы текущего учебного года.
+// Проверяются все строки; при любой ошибке и при dryRun ничего не сохраняется, иначе все ученики зачисляются одной транзакцией.
+func (s *StudentService) Import(ctx context.Context, schoolID int, rows [][]string, dryRun bool) (*models.ImportReport, error) {
+	report := &models.ImportReport{DryRun: dryRun, Errors: []models.ImportError{}}
+	if len(rows) == 0 {
+		return nil, ErrImportEmpty
+	}
+	cols, errs := importColumns(rows[0], StudentImportColumns)
+	if len(errs) > 0 {
+		report.Errors = errs
+		return report, nil
+	}
+
+	var students []models.Student
+	err := s.tx.WithTx(ctx, func(q repository.DBTX) error {
+		classes, err := repository.NewClassRepository(q).GetBySchool(ctx, schoolID, nil)
+		if err != nil {
+			return err
+		}
+		byName := make(map[string]models.Class, len(classes))
+		byID := make(map[int]models.Class, len(classes))
+		for _, c := range classes {
+			byName[className(c.Name)] = c
+			byID[c.ID] = c
+		}
+
+		existing, err := repository.NewStudentRepository(q).ListNames(ctx, schoolID)
+		if err != nil {
+			return err
+		}
+		seen := make(map[string]int, len(existing)+len(rows))
+		for _, st := range existing {
+			seen[studentKey(st.FullName, st.BirthDate)] = 0
+		}
+
+		today := time.Now()
+		for i, cells := range rows[1:] {
+			row := importRow{cells: cells, cols: cols}
+			if row.empty() {
+				continue
+			}
+			report.Rows++
+			if report.Rows > MaxImportRows {
+				return ErrImportTooLarge
+			}
+			n := i + 2
+			fail := func(col, msg string) {
+				report.Errors = append(report.Errors, models.ImportError{Row: n, Column: col, Message: msg})
+			}
+
+			st := models.Student{
+				FullName: strings.Join(strings.Fields(row.get(colStudentName)), " "),
+				Phone:    row.opt(colStudentPhone), Address: row.opt(colStudentAddress), Note: row.opt(colStudentNote),
+				SchoolID: schoolID, Status: models.StudentActive,
+			}
+			ok := true
+			if st.FullName == "" {
+				fail(colStudentName, "full name is required")
+				ok = false
+			}
+			if v := row.get(colStudentClass); v == "" {
+				fail(colStudentClass, "class is required")
+				ok = false
+			} else if c, found := byName[className(v)]; !found {
+				fail(colStudentClass, "class "+v+" not found in the current academic year")
+				ok = false
+			} else {
+				st.ClassID, st.ClassName = c.ID, c.Name
+			}
+			if v := row.get(colStudentBirth); v != "" {
+				d, err := utils.ParseDate(v)
+				switch {
+				case err != nil:
+					fail(colStudentBirth, "invalid date, expected DD.MM.YYYY")
+					ok = false
+				case d.After(today.AddDate(-3, 0, 0)) || d.Before(today.AddDate(-30, 0, 0)):
+					fail(colStudentBirth, "birth date is out of range")
+					ok = false
+				default:
+					st.BirthDate = &d
+				}
+			}
+			if v := row.get(colStudentGender); v != "" {
+				g, found := genders[strings.ToLower(v)]
+				if !found {
+					fail(colStudentGender, "gender must be М or Ж")
+					ok = false
+				} else {
+					st.Gender = &g
+				}
+			}
+			if !ok {
+				continue
+			}
+
+			key := studentKey(st.FullName, st.BirthDate)
+			if prev, dup := seen[key]; dup {
+				if prev == 0 {
+					fail(colStudentName, "student is already enrolled in the school")
+				} else {
+					fail(colStudentName, "duplicate of row "+strconv.Itoa(prev))
+				}
+				continue
+			}
+			seen[key] = n
+			students = append(students, st)
+		}
+
+		if len(report.Errors) > 0 || dryRun || len(students) == 0 {
+			return nil
+		}
+
+		counted := map[int]bool{}
+		for i := range students {
+			st := &students[i]
+			c := byID[st.ClassID]
+			if err := createStudent(ctx, q, st, &c); err != nil {
+				return err
+			}
+			counted[c.ID] = true
+		}
+		for classID := range counted {
+			if err := repository.NewClassRepository(q).RefreshStudentCount(ctx, classID); err != nil {
+				return err
+			}
+		}
+		if err := repository.NewSchoolRepository(q).RefreshStudentCount(ctx, schoolID); err != nil {
+			return err
+		}
+		report.Imported = len(students)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if report.Rows == 0 {
+		return nil, ErrImportEmpty
+	}
+	return report, nil
+}
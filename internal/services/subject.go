@@ -0,0 +1,84 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"eduBase/internal/audit"
+	"eduBase/internal/models"
+	"eduBase/internal/repository"
+)
+
+var ErrSubjectNameRequired = errors.New("name required")
+
+// SubjectService — справочник предметов района (ведёт РОО)
+type SubjectService struct {
+	repo *repository.SubjectRepository
+	tx   *repository.TxManager
+}
+
+func NewSubjectService(repo *repository.SubjectRepository, tx *repository.TxManager) *SubjectService {
+	return &SubjectService{repo: repo, tx: tx}
+}
+
+func (s *SubjectService) GetAll(ctx context.Context) ([]models.Subject, error) {
+	return s.repo.GetAll(ctx)
+}
+
+func (s *SubjectService) Create(ctx context.Context, sb *models.Subject) error {
+	sb.Name = strings.TrimSpace(sb.Name)
+	if sb.Name == "" {
+		return ErrSubjectNameRequired
+	}
+	return s.tx.WithTx(ctx, func(q repository.DBTX) error {
+		if err := repository.NewSubjectRepository(q).Create(ctx, sb); err != nil {
+			return err
+		}
+		return audit.Record(ctx, q, audit.Change{
+			EntityType: audit.EntitySubject, EntityID: sb.ID, Action: audit.ActionCreate, After: sb,
+		})
+	})
+}
+
+func (s *SubjectService) Update(ctx context.Context, id int, sb *models.Subject) error {
+	sb.Name = strings.TrimSpace(sb.Name)
+	if sb.Name == "" {
+		return ErrSubjectNameRequired
+	}
+	return s.tx.WithTx(ctx, func(q repository.DBTX) error {
+		repo := repository.NewSubjectRepository(q)
+		old, err := repo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := repo.Update(ctx, id, sb); err != nil {
+			return err
+		}
+		fresh, err := repo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		*sb = *fresh
+		return audit.Record(ctx, q, audit.Change{
+			EntityType: audit.EntitySubject, EntityID: id, Action: audit.ActionUpdate, Before: old, After: fresh,
+		})
+	})
+}
+
+// Delete удаляет предмет, если он не используется в назначениях
+func (s *SubjectService) Delete(ctx context.Context, id int) error {
+	return s.tx.WithTx(ctx, func(q repository.DBTX) error {
+		repo := repository.NewSubjectRepository(q)
+		old, err := repo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := repo.Delete(ctx, id); err != nil {
+			return err
+		}
+		return audit.Record(ctx, q, audit.Change{
+			EntityType: audit.EntitySubject, EntityID: id, Action: audit.ActionDelete, Before: old,
+		})
+	})
+}
@@ -0,0 +1,173 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"eduBase/internal/audit"
+	"eduBase/internal/models"
+	"eduBase/internal/repository"
+)
+
+var (
+	ErrInvalidTimetableSlot = errors.New("weekday must be 1-6 and lesson 1-12")
+	ErrTimetableConflict    = errors.New("timetable conflict")
+)
+
+// TimetableService — расписание уроков классов
+type TimetableService struct {
+	repo *repository.TimetableRepository
+	tx   *repository.TxManager
+}
+
+func NewTimetableService(repo *repository.TimetableRepository, tx *repository.TxManager) *TimetableService {
+	return &TimetableService{repo: repo, tx: tx}
+}
+
+func (s *TimetableService) GetByClass(ctx context.Context, classID int) ([]models.TimetableEntry, error) {
+	return s.repo.GetByClass(ctx, classID)
+}
+
+// GetByStaff — уроки учителя за учебный год; yearID == nil — текущий
+func (s *TimetableService) GetByStaff(ctx context.Context, staffID int, yearID *int) ([]models.TimetableEntry, error) {
+	return s.repo.GetByStaff(ctx, staffID, yearID)
+}
+
+// Create добавляет урок в расписание класса c. Доступ к классу проверяет вызывающий.
+func (s *TimetableService) Create(ctx context.Context, c *models.Class, e *models.TimetableEntry) error {
+	e.ClassID, e.SchoolID = c.ID, c.SchoolID
+	return s.tx.WithTx(ctx, func(q repository.DBTX) error {
+		if err := s.check(ctx, q, c, e, 0); err != nil {
+			return err
+		}
+		repo := repository.NewTimetableRepository(q)
+		if err := repo.Create(ctx, e); err != nil {
+			return err
+		}
+		fresh, err := repo.GetByID(ctx, e.ID)
+		if err != nil {
+			return err
+		}
+		*e = *fresh
+		return audit.Record(ctx, q, audit.Change{
+			SchoolID: &e.SchoolID, EntityType: audit.EntityTimetable, EntityID: e.ID,
+			Action: audit.ActionCreate, After: e,
+		})
+	})
+}
+
+// Update меняет урок id в расписании класса c
+func (s *TimetableService) Update(ctx context.Context, c *models.Class, id int, e *models.TimetableEntry) (*models.TimetableEntry, error) {
+	e.ClassID, e.SchoolID = c.ID, c.SchoolID
+	var res *models.TimetableEntry
+	err := s.tx.WithTx(ctx, func(q repository.DBTX) error {
+		repo := repository.NewTimetableRepository(q)
+		old, err := repo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if old.ClassID != c.ID {
+			return repository.ErrTimetableEntryNotFound
+		}
+		if err := s.check(ctx, q, c, e, id); err != nil {
+			return err
+		}
+		if err := repo.Update(ctx, id, e); err != nil {
+			return err
+		}
+		if res, err = repo.GetByID(ctx, id); err != nil {
+			return err
+		}
+		return audit.Record(ctx, q, audit.Change{
+			SchoolID: &c.SchoolID, EntityType: audit.EntityTimetable, EntityID: id,
+			Action: audit.ActionUpdate, Before: old, After: res,
+		})
+	})
+	return res, err
+}
+
+// Delete убирает урок id из расписания класса c
+func (s *TimetableService) Delete(ctx context.Context, c *models.Class, id int) error {
+	return s.tx.WithTx(ctx, func(q repository.DBTX) error {
+		repo := repository.NewTimetableRepository(q)
+		old, err := repo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if old.ClassID != c.ID {
+			return repository.ErrTimetableEntryNotFound
+		}
+		if err := checkCurrentYear(ctx, q, c); err != nil {
+			return err
+		}
+		if err := repo.Delete(ctx, id); err != nil {
+			return err
+		}
+		return audit.Record(ctx, q, audit.Change{
+			SchoolID: &c.SchoolID, EntityType: audit.EntityTimetable, EntityID: id,
+			Action: audit.ActionDelete, Before: old,
+		})
+	})
+}
+
+// check проверяет урок и его пересечения с другими уроками того же учебного года:
+// класс, учитель и кабинет не могут быть заняты дважды в одно время. Исключение — подгруппы:
+// уроки одного предмета класса у разных учителей. Менять можно только расписание текущего учебного года.
+// Школа блокируется до конца транзакции, поэтому параллельная правка не пройдёт мимо проверки.
+func (s *TimetableService) check(ctx context.Context, q repository.DBTX, c *models.Class, e *models.TimetableEntry, excludeID int) error {
+	if e.Weekday < 1 || e.Weekday > 6 || e.Lesson < 1 || e.Lesson > 12 {
+		return ErrInvalidTimetableSlot
+	}
+	if err := checkCurrentYear(ctx, q, c); err != nil {
+		return err
+	}
+	if e.Room != nil {
+		room := strings.TrimSpace(*e.Room)
+		if room == "" {
+			e.Room = nil
+		} else {
+			e.Room = &room
+		}
+	}
+	if _, err := repository.NewSubjectRepository(q).GetByID(ctx, e.SubjectID); err != nil {
+		return err
+	}
+	if e.StaffID != nil {
+		if err := checkStaffInSchool(ctx, q, *e.StaffID, c.SchoolID); err != nil {
+			return err
+		}
+	}
+
+	repo := repository.NewTimetableRepository(q)
+	if err := repo.LockSchool(ctx, c.SchoolID); err != nil {
+		return err
+	}
+	conflicts, err := repo.Conflicts(ctx, e, c.AcademicYearID, excludeID)
+	if err != nil || len(conflicts) == 0 {
+		return err
+	}
+	for _, o := range conflicts {
+		if o.ClassID == e.ClassID && !subgroups(o, *e) {
+			return repository.ErrTimetableSlotTaken
+		}
+	}
+	for _, o := range conflicts {
+		if e.StaffID != nil && o.StaffID != nil && *o.StaffID == *e.StaffID {
+			return fmt.Errorf("%w: teacher already has a lesson in class %s at this time", ErrTimetableConflict, o.ClassName)
+		}
+	}
+	for _, o := range conflicts {
+		if e.Room != nil && o.Room != nil && strings.EqualFold(*o.Room, *e.Room) {
+			return fmt.Errorf("%w: room %s is taken by class %s at this time", ErrTimetableConflict, *o.Room, o.ClassName)
+		}
+	}
+	// остались только подгруппы того же класса
+	return nil
+}
+
+// subgroups — уроки класса в одно время по одному предмету у разных учителей (деление на подгруппы)
+func subgroups(a, b models.TimetableEntry) bool {
+	return a.SubjectID == b.SubjectID && a.StaffID != nil && b.StaffID != nil && *a.StaffID != *b.StaffID
+}
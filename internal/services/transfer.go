@@ -0,0 +1,241 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"eduBase/internal/audit"
+	"eduBase/internal/models"
+	"eduBase/internal/repository"
+)
+
+var (
+	ErrTransferSameSchool  = errors.New("student already studies in this school")
+	ErrTransferStudent     = errors.New("only active students can be transferred")
+	ErrTransferNotPending  = errors.New("transfer is already closed")
+	ErrTransferOutdated    = errors.New("student has left the sending school since the transfer was created")
+	ErrTransferClassNeeded = errors.New("class_id required")
+)
+
+// TransferService — перевод учеников между школами района
+type TransferService struct {
+	repo *repository.TransferRepository
+	tx   *repository.TxManager
+}
+
+func NewTransferService(repo *repository.TransferRepository, tx *repository.TxManager) *TransferService {
+	return &TransferService{repo: repo, tx: tx}
+}
+
+// GetAll — заявки; f.SchoolID != nil — только заявки, где школа отправляет или принимает
+func (s *TransferService) GetAll(ctx context.Context, f repository.TransferFilter) ([]models.StudentTransfer, error) {
+	return s.repo.GetAll(ctx, f)
+}
+
+// GetByID — заявка; schoolID != nil — только если школа её отправила или принимает
+func (s *TransferService) GetByID(ctx context.Context, id int, schoolID *int) (*models.StudentTransfer, error) {
+	t, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if schoolID != nil && t.FromSchoolID != *schoolID && t.ToSchoolID != *schoolID {
+		return nil, repository.ErrTransferNotFound
+	}
+	return t, nil
+}
+
+// Create заводит заявку от школы ученика. schoolID != nil — ученик должен учиться в этой школе.
+// forceClassID != nil (только РОО) — перевод проводится сразу, без решения принимающей школы.
+func (s *TransferService) Create(ctx context.Context, t *models.StudentTransfer, schoolID, forceClassID *int) error {
+	return s.tx.WithTx(ctx, func(q repository.DBTX) error {
+		st, err := repository.NewStudentRepository(q).GetByID(ctx, t.StudentID)
+		if err != nil {
+			return err
+		}
+		if schoolID != nil && st.SchoolID != *schoolID {
+			return repository.ErrStudentNotFound
+		}
+		if st.Status != models.StudentActive {
+			return ErrTransferStudent
+		}
+		if st.SchoolID == t.ToSchoolID {
+			return ErrTransferSameSchool
+		}
+		if _, err := repository.NewSchoolRepository(q).GetByID(ctx, t.ToSchoolID); err != nil {
+			return err
+		}
+
+		t.FromSchoolID = st.SchoolID
+		t.FromClassID = &st.ClassID
+		t.CreatedBy = actorID(ctx)
+		repo := repository.NewTransferRepository(q)
+		if err := repo.Create(ctx, t); err != nil {
+			return err
+		}
+		if err := audit.Record(ctx, q, audit.Change{
+			SchoolID: &t.FromSchoolID, EntityType: audit.EntityTransfer, EntityID: t.ID,
+			Action: audit.ActionCreate, After: t,
+		}); err != nil {
+			return err
+		}
+
+		if forceClassID != nil {
+			if err := s.accept(ctx, q, t, *forceClassID); err != nil {
+				return err
+			}
+		}
+		fresh, err := repo.GetByID(ctx, t.ID)
+		if err != nil {
+			return err
+		}
+		*t = *fresh
+		return nil
+	})
+}
+
+// Accept — принимающая школа зачисляет ученика в свой класс текущего года.
+// schoolID != nil — только заявки в эту школу; РОО может провести любую.
+func (s *TransferService) Accept(ctx context.Context, id, classID int, schoolID *int) (*models.StudentTransfer, error) {
+	var res *models.StudentTransfer
+	err := s.tx.WithTx(ctx, func(q repository.DBTX) error {
+		repo := repository.NewTransferRepository(q)
+		t, err := repo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if schoolID != nil && t.ToSchoolID != *schoolID {
+			return repository.ErrTransferNotFound
+		}
+		if err := s.accept(ctx, q, t, classID); err != nil {
+			return err
+		}
+		res, err = repo.GetByID(ctx, id)
+		return err
+	})
+	return res, err
+}
+
+// accept переводит ученика по заявке t в класс classID школы-получателя
+func (s *TransferService) accept(ctx context.Context, q repository.DBTX, t *models.StudentTransfer, classID int) error {
+	if t.Status != models.TransferPending {
+		return ErrTransferNotPending
+	}
+	students := repository.NewStudentRepository(q)
+	st, err := students.GetByID(ctx, t.StudentID)
+	if err != nil {
+		if errors.Is(err, repository.ErrStudentNotFound) {
+			return ErrTransferOutdated
+		}
+		return err
+	}
+	if st.SchoolID != t.FromSchoolID || st.Status != models.StudentActive {
+		return ErrTransferOutdated
+	}
+	c, err := checkClassInSchool(ctx, q, classID, t.ToSchoolID)
+	if err != nil {
+		return err
+	}
+	if err := checkCurrentYear(ctx, q, c); err != nil {
+		return err
+	}
+
+	ok, err := repository.NewTransferRepository(q).Decide(ctx, t.ID, models.TransferAccepted, &c.ID, nil, actorID(ctx))
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrTransferNotPending
+	}
+	if err := students.SetSchool(ctx, st.ID, t.ToSchoolID, c.ID); err != nil {
+		return err
+	}
+	if err := repository.NewEnrollmentRepository(q).Upsert(ctx, st.ID, c.AcademicYearID, c.ID, t.ToSchoolID); err != nil {
+		return err
+	}
+	if err := updateCounts(ctx, q, st.SchoolID, st.ClassID); err != nil {
+		return err
+	}
+	if err := updateCounts(ctx, q, t.ToSchoolID, c.ID); err != nil {
+		return err
+	}
+
+	// выбытие в книге движения отправляющей школы и прибытие — принимающей
+	if err := recordMovement(ctx, q, &models.StudentMovement{
+		StudentID: st.ID, SchoolID: st.SchoolID, Kind: models.MovementDeparture,
+		FromSchoolID: &st.SchoolID, ToSchoolID: &t.ToSchoolID, FromClassID: &st.ClassID,
+		TransferID: &t.ID, Note: t.Reason,
+	}); err != nil {
+		return err
+	}
+	if err := recordMovement(ctx, q, &models.StudentMovement{
+		StudentID: st.ID, SchoolID: t.ToSchoolID, Kind: models.MovementArrival,
+		FromSchoolID: &st.SchoolID, ToSchoolID: &t.ToSchoolID, ToClassID: &c.ID,
+		TransferID: &t.ID, Note: t.Reason,
+	}); err != nil {
+		return err
+	}
+
+	after := *st
+	after.SchoolID, after.ClassID, after.ClassName = t.ToSchoolID, c.ID, c.Name
+	if err := audit.Record(ctx, q, audit.Change{
+		SchoolID: &t.ToSchoolID, EntityType: audit.EntityStudent, EntityID: st.ID,
+		Action: audit.ActionUpdate, Before: st, After: &after,
+	}); err != nil {
+		return err
+	}
+	decided := *t
+	decided.Status, decided.ToClassID = models.TransferAccepted, &c.ID
+	return audit.Record(ctx, q, audit.Change{
+		SchoolID: &t.FromSchoolID, EntityType: audit.EntityTransfer, EntityID: t.ID,
+		Action: audit.ActionUpdate, Before: t, After: &decided,
+	})
+}
+
+// Reject — принимающая школа (или РОО) отказывает в переводе
+func (s *TransferService) Reject(ctx context.Context, id int, note *string, schoolID *int) (*models.StudentTransfer, error) {
+	return s.close(ctx, id, models.TransferRejected, note, func(t *models.StudentTransfer) bool {
+		return schoolID == nil || t.ToSchoolID == *schoolID
+	})
+}
+
+// Cancel — отправляющая школа (или РОО) отзывает заявку
+func (s *TransferService) Cancel(ctx context.Context, id int, note *string, schoolID *int) (*models.StudentTransfer, error) {
+	return s.close(ctx, id, models.TransferCancelled, note, func(t *models.StudentTransfer) bool {
+		return schoolID == nil || t.FromSchoolID == *schoolID
+	})
+}
+
+func (s *TransferService) close(ctx context.Context, id int, status string, note *string, allowed func(*models.StudentTransfer) bool) (*models.StudentTransfer, error) {
+	var res *models.StudentTransfer
+	err := s.tx.WithTx(ctx, func(q repository.DBTX) error {
+		repo := repository.NewTransferRepository(q)
+		t, err := repo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if !allowed(t) {
+			return repository.ErrTransferNotFound
+		}
+		ok, err := repo.Decide(ctx, id, status, nil, note, actorID(ctx))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrTransferNotPending
+		}
+		if res, err = repo.GetByID(ctx, id); err != nil {
+			return err
+		}
+		return audit.Record(ctx, q, audit.Change{
+			SchoolID: &t.FromSchoolID, EntityType: audit.EntityTransfer, EntityID: id,
+			Action: audit.ActionUpdate, Before: t, After: res,
+		})
+	})
+	return res, err
+}
+
+// recordMovement пишет запись книги движения от имени текущего пользователя
+func recordMovement(ctx context.Context, q repository.DBTX, m *models.StudentMovement) error {
+	m.UserID = actorID(ctx)
+	return repository.NewMovementRepository(q).Create(ctx, m)
+}
@@ -0,0 +1,119 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"eduBase/internal/audit"
+	"eduBase/internal/models"
+	"eduBase/internal/repository"
+)
+
+var ErrParentDeleted = errors.New("parent record is in the trash, restore it first")
+
+// TrashService — корзина: просмотр, восстановление и окончательная очистка по сроку хранения
+type TrashService struct {
+	repo *repository.TrashRepository
+	tx   *repository.TxManager
+}
+
+func NewTrashService(repo *repository.TrashRepository, tx *repository.TxManager) *TrashService {
+	return &TrashService{repo: repo, tx: tx}
+}
+
+// actorID — кто выполняет действие (для deleted_by); nil — системное действие
+func actorID(ctx context.Context) *int {
+	if a, ok := audit.ActorFrom(ctx); ok {
+		return &a.UserID
+	}
+	return nil
+}
+
+// List возвращает содержимое корзины; schoolID != nil — только записи этой школы
+func (s *TrashService) List(ctx context.Context, types []string, schoolID *int) ([]models.TrashItem, error) {
+	return s.repo.List(ctx, types, schoolID)
+}
+
+// Restore возвращает запись из корзины вместе с удалёнными одновременно с ней дочерними записями.
+// schoolID != nil — только запись этой школы; false — нет в корзине или чужая.
+func (s *TrashService) Restore(ctx context.Context, entityType string, id int, schoolID *int) (bool, error) {
+	restored := false
+	err := s.tx.WithTx(ctx, func(q repository.DBTX) error {
+		trash := repository.NewTrashRepository(q)
+		it, err := trash.Get(ctx, entityType, id)
+		if err != nil {
+			if errors.Is(err, repository.ErrTrashItemNotFound) {
+				return nil
+			}
+			return err
+		}
+		if schoolID != nil && it.SchoolID != *schoolID {
+			return nil
+		}
+		if err := checkParentActive(ctx, q, it); err != nil {
+			return err
+		}
+
+		students, err := trash.Restore(ctx, it)
+		if err != nil {
+			return err
+		}
+		restored = true
+		// при удалении записано выбытие — восстановленные ученики снова прибывают в школу
+		if err := recordTrashMovements(ctx, q, models.MovementArrival, students...); err != nil {
+			return err
+		}
+
+		// счётчики школы пересчитываются триггерами, счётчики классов — здесь
+		switch it.EntityType {
+		case repository.TrashStudent:
+			if err := updateCounts(ctx, q, it.SchoolID, *it.ClassID); err != nil {
+				return err
+			}
+		case repository.TrashClass:
+			if err := updateCounts(ctx, q, it.SchoolID, it.ID); err != nil {
+				return err
+			}
+		}
+
+		return audit.Record(ctx, q, audit.Change{
+			SchoolID: &it.SchoolID, EntityType: it.EntityType, EntityID: it.ID,
+			Action: audit.ActionRestore, Before: it,
+		})
+	})
+	return restored, err
+}
+
+// checkParentActive — нельзя восстановить запись, пока в корзине её школа или класс
+func checkParentActive(ctx context.Context, q repository.DBTX, it *models.TrashItem) error {
+	switch it.EntityType {
+	case repository.TrashClass, repository.TrashStaff:
+		if _, err := repository.NewSchoolRepository(q).GetByID(ctx, it.SchoolID); err != nil {
+			if errors.Is(err, repository.ErrSchoolNotFound) {
+				return ErrParentDeleted
+			}
+			return err
+		}
+	case repository.TrashStudent:
+		// активный класс означает и активную школу
+		if _, err := repository.NewClassRepository(q).GetByID(ctx, *it.ClassID); err != nil {
+			if errors.Is(err, repository.ErrClassNotFound) {
+				return ErrParentDeleted
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// Purge окончательно удаляет записи, пролежавшие в корзине дольше retention
+func (s *TrashService) Purge(ctx context.Context, retention time.Duration) (map[string]int64, error) {
+	var res map[string]int64
+	err := s.tx.WithTx(ctx, func(q repository.DBTX) error {
+		var err error
+		res, err = repository.NewTrashRepository(q).Purge(ctx, retention)
+		return err
+	})
+	return res, err
+}
@@ -0,0 +1,282 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"eduBase/internal/access"
+	"eduBase/internal/audit"
+	"eduBase/internal/models"
+	"eduBase/internal/repository"
+	"eduBase/internal/utils"
+)
+
+var (
+	ErrCannotDeleteSelf = errors.New("you cannot delete your own account")
+	ErrLastRooUser      = errors.New("cannot remove the last ROO administrator")
+	ErrInvalidRole      = errors.New("role is not allowed here")
+	ErrProtectedAccount = errors.New("this account cannot be changed here")
+	ErrClassNotInSchool = errors.New("class does not belong to the school")
+	ErrClassArchived    = errors.New("class belongs to an archived academic year")
+)
+
+// UserService — управление учётными записями.
+// schoolID == nil — районные учётные записи (roo, inspector) для /roo/users,
+// иначе — сотрудники конкретной школы (deputy, secretary, teacher) для /school/users.
+type UserService struct {
+	repo *repository.UserRepository
+	tx   *repository.TxManager
+}
+
+func NewUserService(repo *repository.UserRepository, tx *repository.TxManager) *UserService {
+	return &UserService{repo: repo, tx: tx}
+}
+
+// editableRoles — роли, которые можно назначать в данной зоне
+func editableRoles(schoolID *int) []string {
+	if schoolID == nil {
+		return []string{access.RoleROO, access.RoleInspector}
+	}
+	return []string{access.RoleDeputy, access.RoleSecretary, access.RoleTeacher}
+}
+
+func roleAllowed(schoolID *int, role string) bool {
+	for _, r := range editableRoles(schoolID) {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *UserService) GetAll(ctx context.Context, schoolID *int) ([]models.User, error) {
+	if schoolID == nil {
+		return s.repo.ListByRoles(ctx, access.RoleROO, access.RoleInspector)
+	}
+	return s.repo.ListBySchool(ctx, *schoolID)
+}
+
+// GetByID возвращает пользователя, только если он в зоне видимости
+func (s *UserService) GetByID(ctx context.Context, schoolID *int, id int) (*models.User, error) {
+	return getScopedUser(ctx, s.repo, schoolID, id)
+}
+
+func getScopedUser(ctx context.Context, repo *repository.UserRepository, schoolID *int, id int) (*models.User, error) {
+	u, err := repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if schoolID == nil && !access.IsDistrictRole(u.Role) {
+		return nil, repository.ErrUserNotFound
+	}
+	if schoolID != nil && (u.SchoolID == nil || *u.SchoolID != *schoolID) {
+		return nil, repository.ErrUserNotFound
+	}
+	return u, nil
+}
+
+// Create заводит пользователя. Если пароль не задан — генерирует его.
+// Возвращённый пароль показывается один раз; при первом входе его нужно сменить.
+func (s *UserService) Create(ctx context.Context, schoolID *int, u *models.User, password string) (string, error) {
+	if u.Role == "" && schoolID == nil {
+		u.Role = access.RoleROO
+	}
+	if !roleAllowed(schoolID, u.Role) {
+		return "", ErrInvalidRole
+	}
+	u.SchoolID = schoolID
+	if err := s.checkClass(ctx, u); err != nil {
+		return "", err
+	}
+
+	if password == "" {
+		generated, err := utils.GeneratePassword(12)
+		if err != nil {
+			return "", errors.New("failed to generate password")
+		}
+		password = generated
+	}
+	if len(password) < minPasswordLength {
+		return "", ErrWeakPassword
+	}
+
+	hash, err := utils.HashPassword(password)
+	if err != nil {
+		return "", errors.New("failed to hash password")
+	}
+	u.Password = hash
+	u.MustChangePassword = true
+
+	err = s.tx.WithTx(ctx, func(q repository.DBTX) error {
+		if err := repository.NewUserRepository(q).Create(ctx, u); err != nil {
+			return err
+		}
+		return audit.Record(ctx, q, audit.Change{
+			SchoolID: u.SchoolID, EntityType: audit.EntityUser, EntityID: u.ID,
+			Action: audit.ActionCreate, After: u,
+		})
+	})
+	if err != nil {
+		return "", err
+	}
+	return password, nil
+}
+
+func (s *UserService) Update(ctx context.Context, schoolID *int, id int, u *models.User) error {
+	return s.tx.WithTx(ctx, func(q repository.DBTX) error {
+		repo := repository.NewUserRepository(q)
+		old, err := getScopedUser(ctx, repo, schoolID, id)
+		if err != nil {
+			return err
+		}
+		if !roleAllowed(schoolID, old.Role) {
+			return ErrProtectedAccount
+		}
+		if u.Role == "" {
+			u.Role = old.Role
+		}
+		if !roleAllowed(schoolID, u.Role) {
+			return ErrInvalidRole
+		}
+		u.SchoolID = old.SchoolID
+		if err := s.checkClass(ctx, u); err != nil {
+			return err
+		}
+		if old.Role == access.RoleROO && u.Role != access.RoleROO {
+			if err := ensureAnotherRoo(ctx, repo, old); err != nil {
+				return err
+			}
+		}
+
+		if _, err := repo.UpdateProfile(ctx, id, u); err != nil {
+			return err
+		}
+		// смена роли или класса меняет права — старые токены отзываем
+		if old.Role != u.Role || !sameInt(old.ClassID, u.ClassID) {
+			if err := repository.NewSessionRepository(q).RevokeAllForUser(ctx, id); err != nil {
+				return err
+			}
+		}
+
+		fresh, err := repo.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		return audit.Record(ctx, q, audit.Change{
+			SchoolID: old.SchoolID, EntityType: audit.EntityUser, EntityID: id,
+			Action: audit.ActionUpdate, Before: old, After: fresh,
+		})
+	})
+}
+
+// Delete удаляет пользователя (сессии удаляются каскадом).
+// Нельзя удалить себя, последнего администратора РОО и директорскую учётную запись школы.
+func (s *UserService) Delete(ctx context.Context, schoolID *int, id, actorID int) error {
+	if id == actorID {
+		return ErrCannotDeleteSelf
+	}
+	return s.tx.WithTx(ctx, func(q repository.DBTX) error {
+		repo := repository.NewUserRepository(q)
+		u, err := getScopedUser(ctx, repo, schoolID, id)
+		if err != nil {
+			return err
+		}
+		if !roleAllowed(schoolID, u.Role) {
+			return ErrProtectedAccount
+		}
+		if u.Role == access.RoleROO {
+			if err := ensureAnotherRoo(ctx, repo, u); err != nil {
+				return err
+			}
+		}
+		if err := repo.Delete(ctx, id); err != nil {
+			return err
+		}
+		return audit.Record(ctx, q, audit.Change{
+			SchoolID: u.SchoolID, EntityType: audit.EntityUser, EntityID: id,
+			Action: audit.ActionDelete, Before: u,
+		})
+	})
+}
+
+// ResetPassword выдаёт пользователю новый временный пароль и завершает его сессии.
+func (s *UserService) ResetPassword(ctx context.Context, schoolID *int, id int) (string, error) {
+	password, err := utils.GeneratePassword(12)
+	if err != nil {
+		return "", errors.New("failed to generate password")
+	}
+	hash, err := utils.HashPassword(password)
+	if err != nil {
+		return "", errors.New("failed to hash password")
+	}
+
+	err = s.tx.WithTx(ctx, func(q repository.DBTX) error {
+		repo := repository.NewUserRepository(q)
+		u, err := getScopedUser(ctx, repo, schoolID, id)
+		if err != nil {
+			return err
+		}
+		if !roleAllowed(schoolID, u.Role) {
+			return ErrProtectedAccount
+		}
+		if err := repo.SetPassword(ctx, id, hash, true); err != nil {
+			return err
+		}
+		if err := repository.NewSessionRepository(q).RevokeAllForUser(ctx, id); err != nil {
+			return err
+		}
+		return audit.Record(ctx, q, audit.Change{
+			SchoolID: u.SchoolID, EntityType: audit.EntityUser, EntityID: id,
+			Action: audit.ActionResetPassword,
+		})
+	})
+	if err != nil {
+		return "", err
+	}
+	return password, nil
+}
+
+// checkClass — класс учителя должен быть из его школы; у остальных ролей класса нет
+func (s *UserService) checkClass(ctx context.Context, u *models.User) error {
+	if u.Role != access.RoleTeacher {
+		u.ClassID = nil
+		return nil
+	}
+	if u.ClassID == nil {
+		return nil
+	}
+	c, err := repository.NewClassRepository(s.repo.DB()).GetByID(ctx, *u.ClassID)
+	if err != nil {
+		if errors.Is(err, repository.ErrClassNotFound) {
+			return ErrClassNotInSchool
+		}
+		return err
+	}
+	if u.SchoolID == nil || c.SchoolID != *u.SchoolID {
+		return ErrClassNotInSchool
+	}
+	return nil
+}
+
+// ensureAnotherRoo проверяет, что кроме изменяемой учётной записи u есть ещё администратор РОО, который может войти.
+// Вызывается в транзакции: строки РОО блокируются до её конца.
+func ensureAnotherRoo(ctx context.Context, repo *repository.UserRepository, u *models.User) error {
+	n, err := repo.LockByRole(ctx, access.RoleROO)
+	if err != nil {
+		return err
+	}
+	if u.Password != models.LockedPassword {
+		n-- // сама изменяемая запись
+	}
+	if n < 1 {
+		return ErrLastRooUser
+	}
+	return nil
+}
+
+func sameInt(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
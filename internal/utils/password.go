@@ -3,6 +3,9 @@ package utils
 import (
 	"crypto/rand"
 	"math/big"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
 )
 
 const letters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
@@ -19,3 +22,17 @@ func GeneratePassword(length int) (string, error) {
 	}
 	return string(pass), nil
 }
+
+// HashPassword возвращает bcrypt-хэш пароля.
+func HashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// IsHashed сообщает, похожа ли строка на bcrypt-хэш ($2a$, $2b$, $2y$).
+func IsHashed(s string) bool {
+	return len(s) == 60 && strings.HasPrefix(s, "$2") && s[3] == '$'
+}
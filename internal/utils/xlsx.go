@@ -0,0 +1,144 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Sheet — лист книги xlsx: заголовок и строки
+type Sheet struct {
+	Name   string
+	Header []string
+	Rows   [][]any
+}
+
+// sheetNameReplacer убирает символы, недопустимые в имени листа Excel
+var sheetNameReplacer = strings.NewReplacer(":", " ", "\\", " ", "/", " ", "?", " ", "*", " ", "[", "(", "]", ")")
+
+// SheetName — допустимое и не занятое в книге имя листа: без запрещённых символов, не длиннее 31 знака;
+// при совпадении добавляется номер. Выданное имя отмечается в used.
+func SheetName(name string, used map[string]bool) string {
+	name = strings.Trim(strings.Join(strings.Fields(sheetNameReplacer.Replace(name)), " "), "'")
+	if name == "" {
+		name = "Лист"
+	}
+	base := []rune(name)
+	for n := 1; ; n++ {
+		suffix := ""
+		if n > 1 {
+			suffix = fmt.Sprintf(" (%d)", n)
+		}
+		cut := base
+		if limit := 31 - len([]rune(suffix)); len(cut) > limit {
+			cut = cut[:limit]
+		}
+		candidate := strings.TrimSpace(string(cut)) + suffix
+		if !used[strings.ToLower(candidate)] {
+			used[strings.ToLower(candidate)] = true
+			return candidate
+		}
+	}
+}
+
+// WriteXLSX записывает книгу из листов: заголовок жирным и закреплён, ширина колонок — по заголовку.
+func WriteXLSX(w io.Writer, sheets ...Sheet) error {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	bold, err := f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}})
+	if err != nil {
+		return err
+	}
+	for i, sh := range sheets {
+		if i == 0 {
+			if err := f.SetSheetName(f.GetSheetName(0), sh.Name); err != nil {
+				return err
+			}
+		} else if _, err := f.NewSheet(sh.Name); err != nil {
+			return err
+		}
+
+		if err := f.SetSheetRow(sh.Name, "A1", &sh.Header); err != nil {
+			return err
+		}
+		if err := f.SetRowStyle(sh.Name, 1, 1, bold); err != nil {
+			return err
+		}
+		for j, h := range sh.Header {
+			col, _ := excelize.ColumnNumberToName(j + 1)
+			if err := f.SetColWidth(sh.Name, col, col, float64(max(len([]rune(h))+4, 12))); err != nil {
+				return err
+			}
+		}
+		if err := f.SetPanes(sh.Name, &excelize.Panes{
+			Freeze: true, YSplit: 1, TopLeftCell: "A2", ActivePane: "bottomLeft",
+		}); err != nil {
+			return err
+		}
+		for j, row := range sh.Rows {
+			if err := f.SetSheetRow(sh.Name, fmt.Sprintf("A%d", j+2), &row); err != nil {
+				return err
+			}
+		}
+	}
+	return f.Write(w)
+}
+
+// ReadXLSX читает первый лист книги: значения ячеек без форматирования
+// (даты — порядковыми номерами Excel, см. ParseDate)
+func ReadXLSX(r io.Reader) ([][]string, error) {
+	f, err := excelize.OpenReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	sheets := f.GetSheetList()
+	if len(sheets) == 0 {
+		return nil, errors.New("workbook has no sheets")
+	}
+	return f.GetRows(sheets[0], excelize.Options{RawCellValue: true})
+}
+
+// ReadCSV читает CSV в UTF-8 (BOM допускается); разделитель — «;» или «,», определяется по первой строке
+func ReadCSV(r io.Reader) ([][]string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	cr := csv.NewReader(bytes.NewReader(data))
+	first, _, _ := bytes.Cut(data, []byte("\n"))
+	if bytes.Count(first, []byte(";")) > bytes.Count(first, []byte(",")) {
+		cr.Comma = ';'
+	}
+	cr.FieldsPerRecord = -1
+	return cr.ReadAll()
+}
+
+// ParseDate разбирает дату из ячейки: ДД.ММ.ГГГГ, ГГГГ-ММ-ДД или порядковый номер даты Excel
+func ParseDate(v string) (time.Time, error) {
+	v = strings.TrimSpace(v)
+	for _, layout := range []string{"02.01.2006", "2.1.2006", time.DateOnly} {
+		if t, err := time.Parse(layout, v); err == nil {
+			return t, nil
+		}
+	}
+	if n, err := strconv.ParseFloat(v, 64); err == nil && n > 0 {
+		t, err := excelize.ExcelDateToTime(n, false)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", v)
+}
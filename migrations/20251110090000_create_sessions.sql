@@ -0,0 +1,17 @@
+-- +goose Up
+CREATE TABLE sessions (
+                          id BIGSERIAL PRIMARY KEY,
+                          user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                          refresh_token_hash TEXT UNIQUE NOT NULL,
+                          expires_at TIMESTAMP NOT NULL,
+                          revoked_at TIMESTAMP,
+                          replaced_by BIGINT REFERENCES sessions(id) ON DELETE SET NULL,
+                          user_agent TEXT,
+                          ip TEXT,
+                          created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX idx_sessions_user_id ON sessions(user_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS sessions;
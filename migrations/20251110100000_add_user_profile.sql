@@ -0,0 +1,12 @@
+-- +goose Up
+ALTER TABLE users
+    ADD COLUMN full_name TEXT,
+    ADD COLUMN must_change_password BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- бывший захардкоженный admin/admin обязан сменить пароль при следующем входе
+UPDATE users SET must_change_password = TRUE WHERE email = 'admin' AND role = 'roo';
+
+-- +goose Down
+ALTER TABLE users
+    DROP COLUMN IF EXISTS must_change_password,
+    DROP COLUMN IF EXISTS full_name;
@@ -0,0 +1,28 @@
+-- +goose Up
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_role_check;
+
+-- учётная запись школы становится директорской
+UPDATE users SET role = 'director' WHERE role = 'school';
+
+ALTER TABLE users
+    ADD CONSTRAINT users_role_check
+        CHECK (role IN ('roo','inspector','director','deputy','secretary','teacher')),
+    ADD COLUMN school_id INT REFERENCES schools(id) ON DELETE CASCADE,
+    ADD COLUMN class_id INT REFERENCES classes(id) ON DELETE SET NULL;
+
+UPDATE users u
+SET school_id = s.id
+FROM schools s
+WHERE s.user_id = u.id;
+
+CREATE INDEX idx_users_school_id ON users(school_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_users_school_id;
+DELETE FROM users WHERE role IN ('inspector','deputy','secretary','teacher');
+ALTER TABLE users
+    DROP COLUMN IF EXISTS class_id,
+    DROP COLUMN IF EXISTS school_id,
+    DROP CONSTRAINT IF EXISTS users_role_check;
+UPDATE users SET role = 'school' WHERE role = 'director';
+ALTER TABLE users ADD CONSTRAINT users_role_check CHECK (role IN ('roo','school'));
@@ -0,0 +1,42 @@
+-- +goose Up
+CREATE TABLE audit_log (
+                           id BIGSERIAL PRIMARY KEY,
+                           user_id INT,
+                           role TEXT,
+                           school_id INT,
+                           entity_type TEXT NOT NULL,
+                           entity_id INT NOT NULL,
+                           action TEXT NOT NULL CHECK (action IN ('create','update','delete','reset_password')),
+                           before JSONB,
+                           after JSONB,
+                           ip TEXT,
+                           created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- без внешних ключей: история должна переживать удаление пользователей и школ
+CREATE INDEX idx_audit_log_school_id ON audit_log(school_id, created_at);
+CREATE INDEX idx_audit_log_entity ON audit_log(entity_type, entity_id);
+CREATE INDEX idx_audit_log_user_id ON audit_log(user_id);
+CREATE INDEX idx_audit_log_created_at ON audit_log(created_at);
+
+-- журнал только дополняется
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION audit_log_append_only()
+    RETURNS TRIGGER
+    LANGUAGE plpgsql
+AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$;
+-- +goose StatementEnd
+
+CREATE TRIGGER trg_audit_log_append_only
+    BEFORE UPDATE OR DELETE ON audit_log
+    FOR EACH ROW
+EXECUTE FUNCTION audit_log_append_only();
+
+-- +goose Down
+DROP TRIGGER IF EXISTS trg_audit_log_append_only ON audit_log;
+DROP FUNCTION IF EXISTS audit_log_append_only();
+DROP TABLE IF EXISTS audit_log;
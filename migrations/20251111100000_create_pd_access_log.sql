@@ -0,0 +1,37 @@
+-- +goose Up
+CREATE TABLE pd_access_log (
+                               id BIGSERIAL PRIMARY KEY,
+                               user_id INT,
+                               role TEXT,
+                               action TEXT NOT NULL CHECK (action IN ('view','list','export')),
+                               student_ids INT[] NOT NULL DEFAULT '{}',
+                               filter JSONB,
+                               ip TEXT,
+                               created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- поиск "кто смотрел ученика X" идёт по элементу массива
+CREATE INDEX idx_pd_access_log_student_ids ON pd_access_log USING GIN (student_ids);
+CREATE INDEX idx_pd_access_log_user_id ON pd_access_log(user_id, created_at);
+CREATE INDEX idx_pd_access_log_created_at ON pd_access_log(created_at);
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION pd_access_log_append_only()
+    RETURNS TRIGGER
+    LANGUAGE plpgsql
+AS $$
+BEGIN
+    RAISE EXCEPTION 'pd_access_log is append-only';
+END;
+$$;
+-- +goose StatementEnd
+
+CREATE TRIGGER trg_pd_access_log_append_only
+    BEFORE UPDATE OR DELETE ON pd_access_log
+    FOR EACH ROW
+EXECUTE FUNCTION pd_access_log_append_only();
+
+-- +goose Down
+DROP TRIGGER IF EXISTS trg_pd_access_log_append_only ON pd_access_log;
+DROP FUNCTION IF EXISTS pd_access_log_append_only();
+DROP TABLE IF EXISTS pd_access_log;
@@ -0,0 +1,203 @@
+-- +goose Up
+ALTER TABLE schools  ADD COLUMN deleted_at TIMESTAMP, ADD COLUMN deleted_by INT;
+ALTER TABLE classes  ADD COLUMN deleted_at TIMESTAMP, ADD COLUMN deleted_by INT;
+ALTER TABLE staff    ADD COLUMN deleted_at TIMESTAMP, ADD COLUMN deleted_by INT;
+ALTER TABLE students ADD COLUMN deleted_at TIMESTAMP, ADD COLUMN deleted_by INT;
+
+-- корзина и очистка по сроку хранения
+CREATE INDEX idx_schools_deleted_at  ON schools(deleted_at)  WHERE deleted_at IS NOT NULL;
+CREATE INDEX idx_classes_deleted_at  ON classes(deleted_at)  WHERE deleted_at IS NOT NULL;
+CREATE INDEX idx_staff_deleted_at    ON staff(deleted_at)    WHERE deleted_at IS NOT NULL;
+CREATE INDEX idx_students_deleted_at ON students(deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- === Счётчики школы учитывают только неудалённые записи ===
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION update_school_class_count()
+    RETURNS TRIGGER
+    LANGUAGE plpgsql
+AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        UPDATE schools
+        SET class_count = (
+            SELECT COUNT(*) FROM classes WHERE school_id = OLD.school_id AND deleted_at IS NULL
+        )
+        WHERE id = OLD.school_id;
+        RETURN OLD;
+    ELSE
+        UPDATE schools
+        SET class_count = (
+            SELECT COUNT(*) FROM classes WHERE school_id = NEW.school_id AND deleted_at IS NULL
+        )
+        WHERE id = NEW.school_id;
+
+        IF TG_OP = 'UPDATE' AND NEW.school_id IS DISTINCT FROM OLD.school_id THEN
+            UPDATE schools
+            SET class_count = (
+                SELECT COUNT(*) FROM classes WHERE school_id = OLD.school_id AND deleted_at IS NULL
+            )
+            WHERE id = OLD.school_id;
+        END IF;
+
+        RETURN NEW;
+    END IF;
+END;
+$$;
+-- +goose StatementEnd
+
+DROP TRIGGER IF EXISTS trg_update_school_class_count ON classes;
+CREATE TRIGGER trg_update_school_class_count
+    AFTER INSERT OR DELETE OR UPDATE OF school_id, deleted_at
+    ON classes
+    FOR EACH ROW
+EXECUTE FUNCTION update_school_class_count();
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION update_school_student_count()
+    RETURNS TRIGGER
+    LANGUAGE plpgsql
+AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        UPDATE schools
+        SET student_count = (
+            SELECT COUNT(*) FROM students WHERE school_id = OLD.school_id AND deleted_at IS NULL
+        )
+        WHERE id = OLD.school_id;
+        RETURN OLD;
+    ELSE
+        UPDATE schools
+        SET student_count = (
+            SELECT COUNT(*) FROM students WHERE school_id = NEW.school_id AND deleted_at IS NULL
+        )
+        WHERE id = NEW.school_id;
+
+        IF TG_OP = 'UPDATE' AND NEW.school_id IS DISTINCT FROM OLD.school_id THEN
+            UPDATE schools
+            SET student_count = (
+                SELECT COUNT(*) FROM students WHERE school_id = OLD.school_id AND deleted_at IS NULL
+            )
+            WHERE id = OLD.school_id;
+        END IF;
+
+        RETURN NEW;
+    END IF;
+END;
+$$;
+-- +goose StatementEnd
+
+DROP TRIGGER IF EXISTS trg_update_school_student_count ON students;
+CREATE TRIGGER trg_update_school_student_count
+    AFTER INSERT OR DELETE OR UPDATE OF school_id, deleted_at
+    ON students
+    FOR EACH ROW
+EXECUTE FUNCTION update_school_student_count();
+
+-- восстановление из корзины попадает в журнал изменений
+ALTER TABLE audit_log DROP CONSTRAINT IF EXISTS audit_log_action_check;
+ALTER TABLE audit_log ADD CONSTRAINT audit_log_action_check
+    CHECK (action IN ('create','update','delete','restore','reset_password'));
+
+-- +goose Down
+-- журнал только дополняется, поэтому старые записи 'restore' не проверяем
+ALTER TABLE audit_log DROP CONSTRAINT IF EXISTS audit_log_action_check;
+ALTER TABLE audit_log ADD CONSTRAINT audit_log_action_check
+    CHECK (action IN ('create','update','delete','reset_password')) NOT VALID;
+
+-- записи из корзины при откате удаляются окончательно
+DELETE FROM students WHERE deleted_at IS NOT NULL;
+DELETE FROM staff    WHERE deleted_at IS NOT NULL;
+DELETE FROM classes  WHERE deleted_at IS NOT NULL;
+DELETE FROM schools  WHERE deleted_at IS NOT NULL;
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION update_school_class_count()
+    RETURNS TRIGGER
+    LANGUAGE plpgsql
+AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        UPDATE schools
+        SET class_count = (
+            SELECT COUNT(*) FROM classes WHERE school_id = OLD.school_id
+        )
+        WHERE id = OLD.school_id;
+        RETURN OLD;
+    ELSE
+        UPDATE schools
+        SET class_count = (
+            SELECT COUNT(*) FROM classes WHERE school_id = NEW.school_id
+        )
+        WHERE id = NEW.school_id;
+
+        IF TG_OP = 'UPDATE' AND NEW.school_id IS DISTINCT FROM OLD.school_id THEN
+            UPDATE schools
+            SET class_count = (
+                SELECT COUNT(*) FROM classes WHERE school_id = OLD.school_id
+            )
+            WHERE id = OLD.school_id;
+        END IF;
+
+        RETURN NEW;
+    END IF;
+END;
+$$;
+-- +goose StatementEnd
+
+DROP TRIGGER IF EXISTS trg_update_school_class_count ON classes;
+CREATE TRIGGER trg_update_school_class_count
+    AFTER INSERT OR DELETE OR UPDATE OF school_id
+    ON classes
+    FOR EACH ROW
+EXECUTE FUNCTION update_school_class_count();
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION update_school_student_count()
+    RETURNS TRIGGER
+    LANGUAGE plpgsql
+AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        UPDATE schools
+        SET student_count = (
+            SELECT COUNT(*) FROM students WHERE school_id = OLD.school_id
+        )
+        WHERE id = OLD.school_id;
+        RETURN OLD;
+    ELSE
+        UPDATE schools
+        SET student_count = (
+            SELECT COUNT(*) FROM students WHERE school_id = NEW.school_id
+        )
+        WHERE id = NEW.school_id;
+
+        IF TG_OP = 'UPDATE' AND NEW.school_id IS DISTINCT FROM OLD.school_id THEN
+            UPDATE schools
+            SET student_count = (
+                SELECT COUNT(*) FROM students WHERE school_id = OLD.school_id
+            )
+            WHERE id = OLD.school_id;
+        END IF;
+
+        RETURN NEW;
+    END IF;
+END;
+$$;
+-- +goose StatementEnd
+
+DROP TRIGGER IF EXISTS trg_update_school_student_count ON students;
+CREATE TRIGGER trg_update_school_student_count
+    AFTER INSERT OR DELETE OR UPDATE OF school_id
+    ON students
+    FOR EACH ROW
+EXECUTE FUNCTION update_school_student_count();
+
+DROP INDEX IF EXISTS idx_students_deleted_at;
+DROP INDEX IF EXISTS idx_staff_deleted_at;
+DROP INDEX IF EXISTS idx_classes_deleted_at;
+DROP INDEX IF EXISTS idx_schools_deleted_at;
+
+ALTER TABLE students DROP COLUMN deleted_at, DROP COLUMN deleted_by;
+ALTER TABLE staff    DROP COLUMN deleted_at, DROP COLUMN deleted_by;
+ALTER TABLE classes  DROP COLUMN deleted_at, DROP COLUMN deleted_by;
+ALTER TABLE schools  DROP COLUMN deleted_at, DROP COLUMN deleted_by;
@@ -0,0 +1,132 @@
+-- +goose Up
+CREATE TABLE academic_years (
+                                id SERIAL PRIMARY KEY,
+                                name TEXT NOT NULL UNIQUE,
+                                start_date DATE NOT NULL,
+                                end_date DATE NOT NULL,
+                                is_current BOOLEAN NOT NULL DEFAULT FALSE,
+                                created_at TIMESTAMP DEFAULT NOW(),
+                                CHECK (end_date > start_date)
+);
+
+-- текущим может быть только один учебный год
+CREATE UNIQUE INDEX uq_academic_years_current ON academic_years(is_current) WHERE is_current;
+
+-- текущий учебный год по сегодняшней дате (с 1 сентября)
+INSERT INTO academic_years (name, start_date, end_date, is_current)
+SELECT y || '/' || (y + 1), make_date(y, 9, 1), make_date(y + 1, 8, 31), TRUE
+FROM (
+    SELECT CASE WHEN EXTRACT(MONTH FROM CURRENT_DATE) >= 9
+                THEN EXTRACT(YEAR FROM CURRENT_DATE)::int
+                ELSE EXTRACT(YEAR FROM CURRENT_DATE)::int - 1
+           END AS y
+) t;
+
+-- существующие классы относятся к текущему году
+ALTER TABLE classes ADD COLUMN academic_year_id INT REFERENCES academic_years(id);
+UPDATE classes SET academic_year_id = (SELECT id FROM academic_years WHERE is_current);
+ALTER TABLE classes ALTER COLUMN academic_year_id SET NOT NULL;
+CREATE INDEX idx_classes_academic_year_id ON classes(academic_year_id, school_id);
+
+-- в каком классе ученик учился в каждом учебном году
+CREATE TABLE student_enrollments (
+                                     id SERIAL PRIMARY KEY,
+                                     student_id INT NOT NULL REFERENCES students(id) ON DELETE CASCADE,
+                                     academic_year_id INT NOT NULL REFERENCES academic_years(id),
+                                     class_id INT NOT NULL REFERENCES classes(id) ON DELETE CASCADE,
+                                     school_id INT NOT NULL REFERENCES schools(id) ON DELETE CASCADE,
+                                     created_at TIMESTAMP DEFAULT NOW(),
+                                     UNIQUE (student_id, academic_year_id)
+);
+
+CREATE INDEX idx_student_enrollments_year ON student_enrollments(academic_year_id, school_id);
+CREATE INDEX idx_student_enrollments_class_id ON student_enrollments(class_id);
+
+INSERT INTO student_enrollments (student_id, academic_year_id, class_id, school_id)
+SELECT s.id, c.academic_year_id, s.class_id, s.school_id
+FROM students s
+JOIN classes c ON c.id = s.class_id;
+
+-- === class_count школы — только классы текущего учебного года ===
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION update_school_class_count()
+    RETURNS TRIGGER
+    LANGUAGE plpgsql
+AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        UPDATE schools
+        SET class_count = (
+            SELECT COUNT(*) FROM classes
+            WHERE school_id = OLD.school_id AND deleted_at IS NULL
+              AND academic_year_id = (SELECT id FROM academic_years WHERE is_current)
+        )
+        WHERE id = OLD.school_id;
+        RETURN OLD;
+    ELSE
+        UPDATE schools
+        SET class_count = (
+            SELECT COUNT(*) FROM classes
+            WHERE school_id = NEW.school_id AND deleted_at IS NULL
+              AND academic_year_id = (SELECT id FROM academic_years WHERE is_current)
+        )
+        WHERE id = NEW.school_id;
+
+        IF TG_OP = 'UPDATE' AND NEW.school_id IS DISTINCT FROM OLD.school_id THEN
+            UPDATE schools
+            SET class_count = (
+                SELECT COUNT(*) FROM classes
+                WHERE school_id = OLD.school_id AND deleted_at IS NULL
+                  AND academic_year_id = (SELECT id FROM academic_years WHERE is_current)
+            )
+            WHERE id = OLD.school_id;
+        END IF;
+
+        RETURN NEW;
+    END IF;
+END;
+$$;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION update_school_class_count()
+    RETURNS TRIGGER
+    LANGUAGE plpgsql
+AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        UPDATE schools
+        SET class_count = (
+            SELECT COUNT(*) FROM classes WHERE school_id = OLD.school_id AND deleted_at IS NULL
+        )
+        WHERE id = OLD.school_id;
+        RETURN OLD;
+    ELSE
+        UPDATE schools
+        SET class_count = (
+            SELECT COUNT(*) FROM classes WHERE school_id = NEW.school_id AND deleted_at IS NULL
+        )
+        WHERE id = NEW.school_id;
+
+        IF TG_OP = 'UPDATE' AND NEW.school_id IS DISTINCT FROM OLD.school_id THEN
+            UPDATE schools
+            SET class_count = (
+                SELECT COUNT(*) FROM classes WHERE school_id = OLD.school_id AND deleted_at IS NULL
+            )
+            WHERE id = OLD.school_id;
+        END IF;
+
+        RETURN NEW;
+    END IF;
+END;
+$$;
+-- +goose StatementEnd
+
+DROP TABLE IF EXISTS student_enrollments;
+DROP INDEX IF EXISTS idx_classes_academic_year_id;
+ALTER TABLE classes DROP COLUMN IF EXISTS academic_year_id;
+DROP TABLE IF EXISTS academic_years;
+
+UPDATE schools s
+SET class_count = (SELECT COUNT(*) FROM classes c WHERE c.school_id = s.id AND c.deleted_at IS NULL);
@@ -0,0 +1,100 @@
+-- +goose Up
+-- active — учится; graduated — окончил школу; left — выбыл
+ALTER TABLE students
+    ADD COLUMN status TEXT NOT NULL DEFAULT 'active' CHECK (status IN ('active','graduated','left')),
+    ADD COLUMN status_changed_at TIMESTAMP;
+
+CREATE INDEX idx_students_status ON students(school_id, status);
+
+-- === student_count школы — только обучающиеся ===
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION update_school_student_count()
+    RETURNS TRIGGER
+    LANGUAGE plpgsql
+AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        UPDATE schools
+        SET student_count = (
+            SELECT COUNT(*) FROM students
+            WHERE school_id = OLD.school_id AND deleted_at IS NULL AND status = 'active'
+        )
+        WHERE id = OLD.school_id;
+        RETURN OLD;
+    ELSE
+        UPDATE schools
+        SET student_count = (
+            SELECT COUNT(*) FROM students
+            WHERE school_id = NEW.school_id AND deleted_at IS NULL AND status = 'active'
+        )
+        WHERE id = NEW.school_id;
+
+        IF TG_OP = 'UPDATE' AND NEW.school_id IS DISTINCT FROM OLD.school_id THEN
+            UPDATE schools
+            SET student_count = (
+                SELECT COUNT(*) FROM students
+                WHERE school_id = OLD.school_id AND deleted_at IS NULL AND status = 'active'
+            )
+            WHERE id = OLD.school_id;
+        END IF;
+
+        RETURN NEW;
+    END IF;
+END;
+$$;
+-- +goose StatementEnd
+
+DROP TRIGGER IF EXISTS trg_update_school_student_count ON students;
+CREATE TRIGGER trg_update_school_student_count
+    AFTER INSERT OR DELETE OR UPDATE OF school_id, deleted_at, status
+    ON students
+    FOR EACH ROW
+EXECUTE FUNCTION update_school_student_count();
+
+-- +goose Down
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION update_school_student_count()
+    RETURNS TRIGGER
+    LANGUAGE plpgsql
+AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        UPDATE schools
+        SET student_count = (
+            SELECT COUNT(*) FROM students WHERE school_id = OLD.school_id AND deleted_at IS NULL
+        )
+        WHERE id = OLD.school_id;
+        RETURN OLD;
+    ELSE
+        UPDATE schools
+        SET student_count = (
+            SELECT COUNT(*) FROM students WHERE school_id = NEW.school_id AND deleted_at IS NULL
+        )
+        WHERE id = NEW.school_id;
+
+        IF TG_OP = 'UPDATE' AND NEW.school_id IS DISTINCT FROM OLD.school_id THEN
+            UPDATE schools
+            SET student_count = (
+                SELECT COUNT(*) FROM students WHERE school_id = OLD.school_id AND deleted_at IS NULL
+            )
+            WHERE id = OLD.school_id;
+        END IF;
+
+        RETURN NEW;
+    END IF;
+END;
+$$;
+-- +goose StatementEnd
+
+DROP TRIGGER IF EXISTS trg_update_school_student_count ON students;
+CREATE TRIGGER trg_update_school_student_count
+    AFTER INSERT OR DELETE OR UPDATE OF school_id, deleted_at
+    ON students
+    FOR EACH ROW
+EXECUTE FUNCTION update_school_student_count();
+
+DROP INDEX IF EXISTS idx_students_status;
+ALTER TABLE students DROP COLUMN status_changed_at, DROP COLUMN status;
+
+UPDATE schools s
+SET student_count = (SELECT COUNT(*) FROM students st WHERE st.school_id = s.id AND st.deleted_at IS NULL);
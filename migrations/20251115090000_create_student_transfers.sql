@@ -0,0 +1,53 @@
+-- +goose Up
+-- заявки на перевод ученика в другую школу района
+CREATE TABLE student_transfers (
+    id SERIAL PRIMARY KEY,
+    student_id INT NOT NULL REFERENCES students(id) ON DELETE CASCADE,
+    from_school_id INT NOT NULL REFERENCES schools(id) ON DELETE CASCADE,
+    from_class_id INT REFERENCES classes(id) ON DELETE SET NULL,
+    to_school_id INT NOT NULL REFERENCES schools(id) ON DELETE CASCADE,
+    to_class_id INT REFERENCES classes(id) ON DELETE SET NULL,
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending','accepted','rejected','cancelled')),
+    reason TEXT,
+    decision_note TEXT,
+    created_by INT REFERENCES users(id) ON DELETE SET NULL,
+    decided_by INT REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    decided_at TIMESTAMP,
+    CHECK (from_school_id <> to_school_id)
+);
+
+-- у ученика не больше одной незавершённой заявки
+CREATE UNIQUE INDEX uq_student_transfers_pending ON student_transfers(student_id) WHERE status = 'pending';
+CREATE INDEX idx_student_transfers_from ON student_transfers(from_school_id, status);
+CREATE INDEX idx_student_transfers_to ON student_transfers(to_school_id, status);
+
+-- движение учеников: прибытие в школу, выбытие, смена класса внутри школы
+CREATE TABLE student_movements (
+    id BIGSERIAL PRIMARY KEY,
+    student_id INT NOT NULL REFERENCES students(id) ON DELETE CASCADE,
+    school_id INT NOT NULL, -- школа, в книге движения которой запись
+    kind TEXT NOT NULL CHECK (kind IN ('arrival','departure','class_change')),
+    from_school_id INT,
+    to_school_id INT,
+    from_class_id INT,
+    to_class_id INT,
+    transfer_id INT REFERENCES student_transfers(id) ON DELETE SET NULL,
+    academic_year_id INT REFERENCES academic_years(id),
+    user_id INT,
+    note TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_student_movements_student ON student_movements(student_id, created_at);
+CREATE INDEX idx_student_movements_school ON student_movements(school_id, created_at);
+
+-- уже зачисленные ученики — прибытие в свою школу
+INSERT INTO student_movements (student_id, school_id, kind, to_school_id, to_class_id, academic_year_id, created_at)
+SELECT s.id, s.school_id, 'arrival', s.school_id, s.class_id, c.academic_year_id, s.created_at
+FROM students s
+JOIN classes c ON c.id = s.class_id;
+
+-- +goose Down
+DROP TABLE IF EXISTS student_movements;
+DROP TABLE IF EXISTS student_transfers;
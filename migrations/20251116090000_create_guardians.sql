@@ -0,0 +1,27 @@
+-- +goose Up
+-- родители и законные представители
+CREATE TABLE guardians (
+    id SERIAL PRIMARY KEY,
+    full_name TEXT NOT NULL,
+    relation TEXT NOT NULL CHECK (relation IN ('mother','father','guardian','trustee','other')),
+    phones TEXT[] NOT NULL DEFAULT '{}',
+    email TEXT,
+    workplace TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- один представитель может быть связан с несколькими детьми (братья и сёстры)
+CREATE TABLE student_guardians (
+    student_id INT NOT NULL REFERENCES students(id) ON DELETE CASCADE,
+    guardian_id INT NOT NULL REFERENCES guardians(id) ON DELETE CASCADE,
+    is_primary BOOLEAN NOT NULL DEFAULT FALSE, -- основной контакт по ребёнку
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (student_id, guardian_id)
+);
+
+CREATE INDEX idx_student_guardians_guardian ON student_guardians(guardian_id);
+CREATE UNIQUE INDEX uq_student_guardians_primary ON student_guardians(student_id) WHERE is_primary;
+
+-- +goose Down
+DROP TABLE IF EXISTS student_guardians;
+DROP TABLE IF EXISTS guardians;
@@ -0,0 +1,20 @@
+-- +goose Up
+-- посещаемость: одна отметка на ученика за день
+CREATE TABLE attendance (
+    id BIGSERIAL PRIMARY KEY,
+    student_id INT NOT NULL REFERENCES students(id) ON DELETE CASCADE,
+    class_id INT NOT NULL REFERENCES classes(id) ON DELETE CASCADE,
+    school_id INT NOT NULL REFERENCES schools(id) ON DELETE CASCADE,
+    date DATE NOT NULL,
+    status TEXT NOT NULL CHECK (status IN ('present','sick','excused','unexcused','late')),
+    note TEXT,
+    marked_by INT REFERENCES users(id) ON DELETE SET NULL,
+    marked_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (student_id, date)
+);
+
+CREATE INDEX idx_attendance_class_date ON attendance(class_id, date);
+CREATE INDEX idx_attendance_school_date ON attendance(school_id, date);
+
+-- +goose Down
+DROP TABLE IF EXISTS attendance;
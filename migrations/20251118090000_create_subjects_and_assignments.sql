@@ -0,0 +1,34 @@
+-- +goose Up
+-- справочник предметов района
+CREATE TABLE subjects (
+    id SERIAL PRIMARY KEY,
+    name TEXT NOT NULL UNIQUE,
+    short_name TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- кто ведёт предмет в классе и сколько часов в неделю; staff_id NULL — вакансия.
+-- Учебный год задаётся классом.
+CREATE TABLE teaching_assignments (
+    id SERIAL PRIMARY KEY,
+    school_id INT NOT NULL REFERENCES schools(id) ON DELETE CASCADE,
+    class_id INT NOT NULL REFERENCES classes(id) ON DELETE CASCADE,
+    subject_id INT NOT NULL REFERENCES subjects(id) ON DELETE RESTRICT,
+    staff_id INT REFERENCES staff(id) ON DELETE SET NULL,
+    hours_per_week NUMERIC(4,1) NOT NULL CHECK (hours_per_week > 0),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- предмет в классе может делиться между учителями (подгруппы), но один учитель — одна запись
+CREATE UNIQUE INDEX uq_teaching_assignments_staff ON teaching_assignments(class_id, subject_id, staff_id)
+    WHERE staff_id IS NOT NULL;
+CREATE INDEX idx_teaching_assignments_school ON teaching_assignments(school_id);
+CREATE INDEX idx_teaching_assignments_staff ON teaching_assignments(staff_id);
+
+-- классный руководитель
+ALTER TABLE classes ADD COLUMN class_teacher_id INT REFERENCES staff(id) ON DELETE SET NULL;
+
+-- +goose Down
+ALTER TABLE classes DROP COLUMN IF EXISTS class_teacher_id;
+DROP TABLE IF EXISTS teaching_assignments;
+DROP TABLE IF EXISTS subjects;
@@ -0,0 +1,21 @@
+-- +goose Up
+-- расписание: урок класса в день недели (1 — понедельник … 6 — суббота) и номер урока.
+-- Учебный год задаётся классом; занятость учителя и кабинета проверяется в сервисе.
+CREATE TABLE timetable_entries (
+    id SERIAL PRIMARY KEY,
+    school_id INT NOT NULL REFERENCES schools(id) ON DELETE CASCADE,
+    class_id INT NOT NULL REFERENCES classes(id) ON DELETE CASCADE,
+    weekday SMALLINT NOT NULL CHECK (weekday BETWEEN 1 AND 6),
+    lesson SMALLINT NOT NULL CHECK (lesson BETWEEN 1 AND 12),
+    subject_id INT NOT NULL REFERENCES subjects(id) ON DELETE RESTRICT,
+    staff_id INT REFERENCES staff(id) ON DELETE SET NULL,
+    room TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX uq_timetable_class_slot ON timetable_entries(class_id, weekday, lesson);
+CREATE INDEX idx_timetable_school_slot ON timetable_entries(school_id, weekday, lesson);
+CREATE INDEX idx_timetable_staff ON timetable_entries(staff_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS timetable_entries;
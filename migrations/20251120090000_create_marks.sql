@@ -0,0 +1,39 @@
+-- +goose Up
+-- журнал: одна отметка 2–5 на ученика по предмету за дату урока
+CREATE TABLE marks (
+    id BIGSERIAL PRIMARY KEY,
+    student_id INT NOT NULL REFERENCES students(id) ON DELETE CASCADE,
+    class_id INT NOT NULL REFERENCES classes(id) ON DELETE CASCADE,
+    school_id INT NOT NULL REFERENCES schools(id) ON DELETE CASCADE,
+    subject_id INT NOT NULL REFERENCES subjects(id) ON DELETE RESTRICT,
+    date DATE NOT NULL,
+    mark SMALLINT NOT NULL CHECK (mark BETWEEN 2 AND 5),
+    note TEXT,
+    marked_by INT REFERENCES users(id) ON DELETE SET NULL,
+    marked_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (student_id, subject_id, date)
+);
+
+CREATE INDEX idx_marks_class_subject_date ON marks(class_id, subject_id, date);
+
+-- итоговые отметки: четверти (q1–q4), полугодия (h1, h2) и год
+CREATE TABLE term_marks (
+    id SERIAL PRIMARY KEY,
+    student_id INT NOT NULL REFERENCES students(id) ON DELETE CASCADE,
+    class_id INT NOT NULL REFERENCES classes(id) ON DELETE CASCADE,
+    school_id INT NOT NULL REFERENCES schools(id) ON DELETE CASCADE,
+    academic_year_id INT NOT NULL REFERENCES academic_years(id) ON DELETE RESTRICT,
+    subject_id INT NOT NULL REFERENCES subjects(id) ON DELETE RESTRICT,
+    period TEXT NOT NULL CHECK (period IN ('q1','q2','q3','q4','h1','h2','year')),
+    mark SMALLINT NOT NULL CHECK (mark BETWEEN 2 AND 5),
+    marked_by INT REFERENCES users(id) ON DELETE SET NULL,
+    marked_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (student_id, subject_id, academic_year_id, period)
+);
+
+CREATE INDEX idx_term_marks_class ON term_marks(class_id, subject_id, period);
+CREATE INDEX idx_term_marks_school_period ON term_marks(school_id, academic_year_id, period);
+
+-- +goose Down
+DROP TABLE IF EXISTS term_marks;
+DROP TABLE IF EXISTS marks;
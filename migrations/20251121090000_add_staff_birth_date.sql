@@ -0,0 +1,6 @@
+-- +goose Up
+-- дата рождения сотрудника — для статистики по возрасту (предпенсионный возраст, молодые специалисты)
+ALTER TABLE staff ADD COLUMN birth_date DATE;
+
+-- +goose Down
+ALTER TABLE staff DROP COLUMN IF EXISTS birth_date;
@@ -0,0 +1,20 @@
+-- +goose Up
+-- ежедневные (или ежемесячные) снимки сводной статистики: по району (school_id IS NULL) и по каждой школе
+CREATE TABLE stats_snapshots (
+    id BIGSERIAL PRIMARY KEY,
+    date DATE NOT NULL,
+    school_id INT REFERENCES schools(id) ON DELETE CASCADE,
+    schools INT NOT NULL,
+    classes INT NOT NULL,
+    students INT NOT NULL,
+    teachers INT NOT NULL,
+    staff_total INT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- один снимок района и каждой школы за дату
+CREATE UNIQUE INDEX uq_stats_snapshots_date ON stats_snapshots(date, (COALESCE(school_id, 0)));
+CREATE INDEX idx_stats_snapshots_school ON stats_snapshots(school_id, date);
+
+-- +goose Down
+DROP TABLE IF EXISTS stats_snapshots;
@@ -0,0 +1,11 @@
+-- +goose Up
+-- смена пароля самим пользователем попадает в журнал изменений
+ALTER TABLE audit_log DROP CONSTRAINT IF EXISTS audit_log_action_check;
+ALTER TABLE audit_log ADD CONSTRAINT audit_log_action_check
+    CHECK (action IN ('create','update','delete','restore','reset_password','change_password'));
+
+-- +goose Down
+-- журнал только дополняется, поэтому старые записи 'change_password' не проверяем
+ALTER TABLE audit_log DROP CONSTRAINT IF EXISTS audit_log_action_check;
+ALTER TABLE audit_log ADD CONSTRAINT audit_log_action_check
+    CHECK (action IN ('create','update','delete','restore','reset_password')) NOT VALID;
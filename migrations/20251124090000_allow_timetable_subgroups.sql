@@ -0,0 +1,14 @@
+-- +goose Up
+-- предмет класса может делиться между учителями (подгруппы) — такие уроки идут в одно время.
+-- Класс в слоте однозначен с точностью до предмета и учителя; остальное проверяет сервис.
+-- staff_id NULL в уникальном индексе различаются, поэтому уход учителя (ON DELETE SET NULL) не нарушает индекс.
+DROP INDEX IF EXISTS uq_timetable_class_slot;
+CREATE UNIQUE INDEX uq_timetable_class_slot ON timetable_entries(class_id, weekday, lesson, subject_id, staff_id);
+
+-- +goose Down
+-- в слоте класса остаётся один урок — подгруппы сверх первой удаляются
+DELETE FROM timetable_entries t
+USING timetable_entries o
+WHERE o.class_id = t.class_id AND o.weekday = t.weekday AND o.lesson = t.lesson AND o.id < t.id;
+DROP INDEX IF EXISTS uq_timetable_class_slot;
+CREATE UNIQUE INDEX uq_timetable_class_slot ON timetable_entries(class_id, weekday, lesson);
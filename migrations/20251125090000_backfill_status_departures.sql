@@ -0,0 +1,17 @@
+-- +goose Up
+-- окончившим и выбывшим, получившим статус до того, как выпуск и выбытие стали записываться в книгу движения, —
+-- выбытие на дату смены статуса
+INSERT INTO student_movements (student_id, school_id, kind, from_school_id, from_class_id, academic_year_id, note, created_at)
+SELECT s.id, s.school_id, 'departure', s.school_id, s.class_id, c.academic_year_id,
+       CASE s.status WHEN 'graduated' THEN 'выпуск (по статусу ученика)' ELSE 'выбыл (по статусу ученика)' END,
+       COALESCE(s.status_changed_at, NOW())
+FROM students s
+JOIN classes c ON c.id = s.class_id
+WHERE s.status IN ('graduated', 'left')
+  AND COALESCE((SELECT m.kind FROM student_movements m
+                WHERE m.student_id = s.id
+                ORDER BY m.created_at DESC, m.id DESC LIMIT 1), '') <> 'departure';
+
+-- +goose Down
+DELETE FROM student_movements
+WHERE kind = 'departure' AND note IN ('выпуск (по статусу ученика)', 'выбыл (по статусу ученика)');
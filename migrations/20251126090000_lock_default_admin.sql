@@ -0,0 +1,13 @@
+-- +goose Up
+-- бывший захардкоженный admin/admin: пароль известен всем, поэтому учётная запись блокируется
+-- (пароль '!' не совпадает ни с одним). Войти под ней можно только после сброса пароля РОО;
+-- если других РОО нет, при старте создаётся администратор из ADMIN_EMAIL/ADMIN_PASSWORD.
+UPDATE users SET password = '!', must_change_password = TRUE WHERE email = 'admin' AND role = 'roo';
+
+UPDATE sessions SET revoked_at = NOW()
+WHERE revoked_at IS NULL
+  AND user_id IN (SELECT id FROM users WHERE email = 'admin' AND role = 'roo');
+
+-- +goose Down
+-- старый пароль не восстановить: учётная запись остаётся заблокированной до сброса пароля
+SELECT 1;